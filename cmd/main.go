@@ -1,53 +1,476 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+	// Embed the IANA time zone database so event time zones resolve even in
+	// images without one
+	_ "time/tzdata"
 
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/auth"
 	"github.com/jhonathanssegura/ticket-events/internal/awsconfig"
+	"github.com/jhonathanssegura/ticket-events/internal/config"
 	"github.com/jhonathanssegura/ticket-events/internal/db"
 	"github.com/jhonathanssegura/ticket-events/internal/handler"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/logging"
+	"github.com/jhonathanssegura/ticket-events/internal/metrics"
+	"github.com/jhonathanssegura/ticket-events/internal/middleware"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
 	"github.com/jhonathanssegura/ticket-events/internal/queue"
+	"github.com/jhonathanssegura/ticket-events/internal/ratelimit"
+	"github.com/jhonathanssegura/ticket-events/internal/search"
+	"github.com/jhonathanssegura/ticket-events/internal/service"
+	"github.com/jhonathanssegura/ticket-events/internal/storage"
+	"github.com/jhonathanssegura/ticket-events/internal/tracing"
+	"github.com/jhonathanssegura/ticket-events/internal/validation"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+const (
+	// seriesLease is the lease held by the replica materializing series.
+	seriesLease = "series-materializer"
+	// pricesLease is the lease held by the replica recording price changes.
+	pricesLease = "price-recorder"
 )
 
 func main() {
-	cfg, err := awsconfig.LoadAWSConfig()
+	appCfg := config.Load()
+	slog.SetDefault(logging.New(appCfg.LogLevel))
+
+	shutdownTracing, err := tracing.Setup(context.Background(), appCfg.TraceExporter, appCfg.ServiceName, appCfg.TraceSampleRatio)
 	if err != nil {
-		log.Fatalf("Error cargando configuración AWS: %v", err)
+		slog.Error("error configurando trazas", "error", err)
+		os.Exit(1)
 	}
 
-	queueURL := "http://localhost:4566/000000000000/event-queue"
+	if err := validation.Register(); err != nil {
+		slog.Error("error configurando validaciones", "error", err)
+		os.Exit(1)
+	}
 
-	sqsClient := &queue.SQSClient{
-		Client:   sqs.NewFromConfig(cfg),
-		QueueURL: queueURL,
+	awsCfg, err := awsconfig.LoadAWSConfig()
+	if err != nil {
+		slog.Error("error cargando configuración AWS", "error", err)
+		os.Exit(1)
 	}
 
-	dynamoClient := &db.DynamoClient{
-		Client: dynamodb.NewFromConfig(cfg),
+	verifier, err := newVerifier(appCfg)
+	if err != nil {
+		slog.Error("error configurando autenticación", "error", err)
+		os.Exit(1)
+	}
+	if !verifier.Enabled() {
+		slog.Warn("no hay claves JWT configuradas: las operaciones de escritura serán rechazadas")
 	}
 
-	handlerEvent := handler.NewEventHandler(sqsClient, dynamoClient)
+	sqsClient := queue.NewSQSClient(awsCfg, appCfg.QueueURL)
+	dynamoClient := db.NewDynamoClient(awsCfg)
+	imageStorage := storage.NewS3Client(awsCfg, appCfg.ImageBucket, appCfg.ImageBaseURL)
+
+	// Every event written through the client is indexed right away; the
+	// periodic rebuild picks up writes made by other instances
+	searchIndex := search.NewIndex()
+	dynamoClient.SetEventIndexer(searchIndex)
+
+	rateLimit, err := newRateLimit(appCfg, dynamoClient)
+	if err != nil {
+		slog.Error("error configurando límites de peticiones", "error", err)
+		os.Exit(1)
+	}
+
+	seriesService := service.NewSeriesService(dynamoClient, appCfg.SeriesHorizon)
+
+	handlerEvent := handler.NewEventHandler(sqsClient, dynamoClient, imageStorage)
+	handlerSeries := handler.NewSeriesHandler(dynamoClient, seriesService)
+	handlerSearch := handler.NewSearchHandler(dynamoClient, searchIndex)
 	handlerCategory := handler.NewCategoryHandler(dynamoClient)
+	handlerTax := handler.NewTaxHandler(dynamoClient)
+	handlerImage := handler.NewImageHandler(dynamoClient, imageStorage, appCfg.ImageMaxMB)
+	handlerVenue := handler.NewVenueHandler(dynamoClient)
+	handlerSeat := handler.NewSeatHandler(dynamoClient, appCfg.SeatHoldTTL)
+	handlerPromo := handler.NewPromoHandler(dynamoClient)
+	handlerAPIKey := handler.NewAPIKeyHandler(dynamoClient)
+	handlerHealth := handler.NewHealthHandler(sqsClient, dynamoClient, imageStorage, appCfg.ReadinessTimeout, appCfg.ReadinessCacheTTL)
 	// handlerQR := handler.NewQRHandler(dynamoClient)
 
-	r := gin.Default()
+	r := gin.New()
+	// X-Forwarded-For is only believed from these proxies; otherwise clients
+	// could forge it to get a fresh rate limit bucket on every request
+	if err := r.SetTrustedProxies(appCfg.TrustedProxies); err != nil {
+		slog.Error("error configurando proxies de confianza", "error", err)
+		os.Exit(1)
+	}
+	r.Use(
+		otelgin.Middleware(appCfg.ServiceName, otelgin.WithFilter(middleware.SkipProbes)),
+		middleware.RequestID(),
+		middleware.Locale(),
+		middleware.Logger(),
+		middleware.Recovery(),
+		middleware.Metrics(),
+		// Image uploads are capped by their handler at IMAGE_MAX_MB
+		middleware.BodyLimit(int64(appCfg.MaxBodyKB)<<10, "POST /api/events/:id/image"),
+	)
+
+	r.NoRoute(func(c *gin.Context) { problem.NotFound(c, i18n.RouteNotFound) })
 
-	api := r.Group("/api")
+	// Liveness and readiness probes
+	r.GET("/healthz", handlerHealth.Healthz)
+	r.GET("/readyz", handlerHealth.Readyz)
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// Every /api request is scoped to a tenant; partners may authenticate
+	// with an API key, which is then checked against each route's scope.
+	// Requests are rate limited per API key or client IP
+	api := r.Group("/api",
+		middleware.Tenant(appCfg.TenantBaseDomain, appCfg.DefaultTenant),
+		middleware.APIKey(dynamoClient),
+		rateLimit,
+	)
+
+	public := api.Group("", middleware.RequireTenant())
 	{
+		// Public read endpoints
+		public.GET("/events", middleware.RequireAccess(auth.ScopeEventsRead), handlerEvent.ListEvents)
+		public.GET("/events/search", middleware.RequireAccess(auth.ScopeEventsRead), handlerSearch.SearchEvents)
+		public.GET("/events/:id", middleware.RequireAccess(auth.ScopeEventsRead), handlerEvent.GetEvent)
+		public.GET("/events/:id/seatmap", middleware.RequireAccess(auth.ScopeEventsRead), handlerSeat.GetSeatMap)
+		public.GET("/events/:id/seats", middleware.RequireAccess(auth.ScopeEventsRead), handlerSeat.ListSeats)
+		public.POST("/events/:id/quote", middleware.RequireAccess(auth.ScopeEventsRead), handlerPromo.QuoteEvent)
+		public.GET("/categories/:id", middleware.RequireAccess(auth.ScopeEventsRead), handlerCategory.GetCategory)
+		public.GET("/venues", middleware.RequireAccess(auth.ScopeEventsRead), handlerVenue.ListVenues)
+		public.GET("/venues/:id", middleware.RequireAccess(auth.ScopeEventsRead), handlerVenue.GetVenue)
+		public.GET("/series/:id", middleware.RequireAccess(auth.ScopeEventsRead), handlerSeries.GetSeries)
+		public.GET("/series/:id/events", middleware.RequireAccess(auth.ScopeEventsRead), handlerSeries.ListSeriesEvents)
+	}
+
+	// Write endpoints require an organizer or admin token, or an API key
+	// with the matching scope
+	manage := api.Group("", middleware.Authenticate(verifier), middleware.RequireTenant())
+	{
+		canReadEvents := middleware.RequireAccess(auth.ScopeEventsRead, auth.RoleOrganizer, auth.RoleAdmin)
+		canWriteEvents := middleware.RequireAccess(auth.ScopeEventsWrite, auth.RoleOrganizer, auth.RoleAdmin)
+		canWriteCategories := middleware.RequireAccess(auth.ScopeCategoriesWrite, auth.RoleOrganizer, auth.RoleAdmin)
+		canWriteVenues := middleware.RequireAccess(auth.ScopeVenuesWrite, auth.RoleOrganizer, auth.RoleAdmin)
+		canDeleteVenues := middleware.RequireAccess(auth.ScopeVenuesWrite, auth.RoleAdmin)
+		canHoldSeats := middleware.RequireAccess(auth.ScopeSeatsHold, auth.RoleViewer, auth.RoleOrganizer, auth.RoleAdmin)
+		canWritePromos := middleware.RequireAccess(auth.ScopePromosWrite, auth.RoleOrganizer, auth.RoleAdmin)
+		canRedeemPromos := middleware.RequireAccess(auth.ScopePromosRedeem, auth.RoleViewer, auth.RoleOrganizer, auth.RoleAdmin)
+		// Creation endpoints can be retried safely with an Idempotency-Key
+		idempotent := middleware.Idempotency(dynamoClient, appCfg.IdempotencyTTL, appCfg.IdempotencyWait)
+
 		// Event management endpoints
-		api.GET("/events", handlerEvent.ListEvents)
-		api.GET("/events/:id", handlerEvent.GetEvent)
-		api.POST("/events", handlerEvent.CreateEvent)
-		api.PUT("/events/:id", handlerEvent.UpdateEvent)
-		api.DELETE("/events/:id", handlerEvent.DeleteEvent)
+		manage.POST("/events", canWriteEvents, idempotent, handlerEvent.CreateEvent)
+		manage.PUT("/events/:id", canWriteEvents, handlerEvent.UpdateEvent)
+		manage.DELETE("/events/:id", canWriteEvents, handlerEvent.DeleteEvent)
+		manage.POST("/events/:id/transfer", canWriteEvents, handlerEvent.TransferEvent)
+		manage.PUT("/events/:id/translations/:locale", canWriteEvents, handlerEvent.PutEventTranslation)
+		manage.DELETE("/events/:id/translations/:locale", canWriteEvents, handlerEvent.DeleteEventTranslation)
+		manage.POST("/events/:id/image", canWriteEvents, handlerImage.UploadEventImage)
+		manage.GET("/me/events", canReadEvents, handlerEvent.ListMyEvents)
+		manage.GET("/events/:id/pricing-rules", canReadEvents, handlerEvent.GetPricingRules)
+		manage.PUT("/events/:id/pricing-rules", canWriteEvents, handlerEvent.PutPricingRules)
+		manage.DELETE("/events/:id/pricing-rules", canWriteEvents, handlerEvent.DeletePricingRules)
+		manage.GET("/events/:id/price-history", canReadEvents, handlerEvent.ListPriceHistory)
+		// Assigned seating endpoints; any authenticated buyer may hold seats
+		manage.PUT("/events/:id/seatmap", canWriteEvents, handlerSeat.PutSeatMap)
+		manage.POST("/events/:id/holds", canHoldSeats, idempotent, handlerSeat.CreateHold)
+		manage.GET("/events/:id/holds/:hold_id", canHoldSeats, handlerSeat.GetHold)
+		manage.POST("/events/:id/holds/:hold_id/confirm", canHoldSeats, handlerSeat.ConfirmHold)
+		manage.DELETE("/events/:id/holds/:hold_id", canHoldSeats, handlerSeat.ReleaseHold)
+		// Promo code endpoints; redeeming uses up the code for the caller
+		manage.POST("/promo-codes", canWritePromos, idempotent, handlerPromo.CreatePromoCode)
+		manage.GET("/promo-codes", canWritePromos, handlerPromo.ListPromoCodes)
+		manage.GET("/promo-codes/:code", canWritePromos, handlerPromo.GetPromoCode)
+		manage.PUT("/promo-codes/:code", canWritePromos, handlerPromo.UpdatePromoCode)
+		manage.DELETE("/promo-codes/:code", canWritePromos, handlerPromo.DeletePromoCode)
+		manage.POST("/promo-codes/:code/redemptions", canRedeemPromos, idempotent, handlerPromo.RedeemPromoCode)
+		// Recurring series endpoints
+		manage.POST("/series", canWriteEvents, idempotent, handlerSeries.CreateSeries)
+		manage.PUT("/series/:id", canWriteEvents, handlerSeries.UpdateSeries)
+		manage.DELETE("/series/:id", canWriteEvents, handlerSeries.DeleteSeries)
+		manage.PUT("/series/:id/events/:event_id", canWriteEvents, handlerSeries.UpdateOccurrence)
 		// Category endpoint
-		api.POST("/categories", handlerCategory.CreateCategory)
+		manage.POST("/categories", canWriteCategories, idempotent, handlerCategory.CreateCategory)
+		manage.PUT("/categories/:id/translations/:locale", canWriteCategories, handlerCategory.PutCategoryTranslation)
+		manage.DELETE("/categories/:id/translations/:locale", canWriteCategories, handlerCategory.DeleteCategoryTranslation)
+		// Venue endpoints; events may be linked to them while they exist, so
+		// only admins delete them
+		manage.POST("/venues", canWriteVenues, idempotent, handlerVenue.CreateVenue)
+		manage.PUT("/venues/:id", canWriteVenues, handlerVenue.UpdateVenue)
+		manage.DELETE("/venues/:id", canDeleteVenues, handlerVenue.DeleteVenue)
 		// QR code endpoints eliminados
 	}
 
-	log.Println("🚀 Iniciando servidor de eventos en puerto 8080...")
-	r.Run(":8080")
+	// Administration endpoints, only for admin tokens
+	admin := manage.Group("/admin", middleware.RequireRole(auth.RoleAdmin))
+	{
+		admin.POST("/api-keys", handlerAPIKey.CreateAPIKey)
+		admin.GET("/api-keys", handlerAPIKey.ListAPIKeys)
+		admin.POST("/api-keys/:id/rotate", handlerAPIKey.RotateAPIKey)
+		admin.DELETE("/api-keys/:id", handlerAPIKey.RevokeAPIKey)
+		// Taxes per country of the venue and service fees per category
+		admin.GET("/tax-rates", handlerTax.ListTaxJurisdictions)
+		admin.GET("/tax-rates/:country", handlerTax.GetTaxJurisdiction)
+		admin.PUT("/tax-rates/:country", handlerTax.PutTaxJurisdiction)
+		admin.DELETE("/tax-rates/:country", handlerTax.DeleteTaxJurisdiction)
+		admin.GET("/categories/:id/fees", handlerCategory.GetCategoryFees)
+		admin.PUT("/categories/:id/fees", handlerCategory.PutCategoryFees)
+		admin.DELETE("/categories/:id/fees", handlerCategory.DeleteCategoryFees)
+	}
+
+	srv := &http.Server{
+		Addr:              ":" + appCfg.Port,
+		Handler:           r,
+		ReadTimeout:       appCfg.ReadTimeout,
+		ReadHeaderTimeout: appCfg.ReadHeaderTimeout,
+		WriteTimeout:      appCfg.WriteTimeout,
+		IdleTimeout:       appCfg.IdleTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Background workers stop when ctx is cancelled and are awaited on shutdown.
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		refreshEventMetrics(ctx, dynamoClient, appCfg.MetricsRefresh)
+	}()
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		materializeSeries(ctx, seriesService, dynamoClient, appCfg.SeriesInterval)
+	}()
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		recordPriceChanges(ctx, dynamoClient, appCfg.PriceRefresh)
+	}()
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		rebuildSearchIndex(ctx, dynamoClient, searchIndex, appCfg.SearchRebuild)
+	}()
+
+	go func() {
+		slog.Info("iniciando servidor de eventos", "port", appCfg.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("error iniciando servidor", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	slog.Info("señal de apagado recibida, drenando peticiones en curso")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), appCfg.ShutdownTimeout)
+	defer cancel()
+
+	// Stop accepting connections and wait for in-flight requests first, so
+	// no handler can enqueue a publish after the queue has been flushed.
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("error cerrando servidor HTTP", "error", err)
+	}
+	if err := waitWorkers(shutdownCtx, &workers); err != nil {
+		slog.Error("error esperando tareas en segundo plano", "error", err)
+	}
+	if err := sqsClient.Flush(shutdownCtx); err != nil {
+		slog.Error("error vaciando publicaciones pendientes", "error", err)
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("error exportando trazas pendientes", "error", err)
+	}
+
+	slog.Info("servidor detenido")
+}
+
+func newVerifier(appCfg config.Config) (*auth.Verifier, error) {
+	var jwks *auth.JWKS
+	switch {
+	case appCfg.JWKSFile != "":
+		var err error
+		if jwks, err = auth.LoadJWKSFile(appCfg.JWKSFile); err != nil {
+			return nil, err
+		}
+	case appCfg.JWKSURL != "":
+		jwks = auth.NewRemoteJWKS(appCfg.JWKSURL, appCfg.JWKSRefresh)
+	}
+	return auth.NewVerifier(auth.VerifierConfig{
+		HMACSecret: appCfg.JWTSecret,
+		JWKS:       jwks,
+		Issuer:     appCfg.JWTIssuer,
+		Audience:   appCfg.JWTAudience,
+	}), nil
+}
+
+// newRateLimit builds the rate limiting middleware for the configured backend.
+// With the "none" backend it is a no-op.
+func newRateLimit(appCfg config.Config, dynamoClient *db.DynamoClient) (gin.HandlerFunc, error) {
+	var store ratelimit.Store
+	switch appCfg.RateLimitBackend {
+	case ratelimit.BackendNone:
+		return func(c *gin.Context) { c.Next() }, nil
+	case ratelimit.BackendMemory:
+		store = ratelimit.NewMemoryStore()
+	case ratelimit.BackendDynamoDB:
+		store = ratelimit.NewDynamoStore(dynamoClient)
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend %q", appCfg.RateLimitBackend)
+	}
+
+	anonymous, err := ratelimit.ParseLimit(appCfg.RateLimitAnon)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, err := ratelimit.ParseLimit(appCfg.RateLimitAPIKey)
+	if err != nil {
+		return nil, err
+	}
+	routes, err := ratelimit.ParseRouteLimits(appCfg.RateLimitRoutes)
+	if err != nil {
+		return nil, err
+	}
+	return middleware.RateLimit(store, ratelimit.Policy{
+		Anonymous: anonymous,
+		APIKey:    apiKey,
+		Routes:    routes,
+	}), nil
+}
+
+// refreshEventMetrics periodically updates the events-per-status gauge until
+// ctx is cancelled.
+func refreshEventMetrics(ctx context.Context, dynamoClient *db.DynamoClient, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		counts, err := dynamoClient.CountEventsByStatus(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.WarnContext(ctx, "error actualizando métricas de eventos", "error", err)
+			}
+		} else {
+			metrics.SetEventsByStatus(counts)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// materializeSeries periodically extends recurring series up to the horizon
+// until ctx is cancelled. Only the replica holding the lease runs each pass,
+// so replicas do not race each other over the same series; if it stops, the
+// lease expires and another one takes over.
+func materializeSeries(ctx context.Context, seriesService *service.SeriesService, dynamoClient *db.DynamoClient, interval time.Duration) {
+	owner := leaseOwner()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// The lease outlives the interval so the holder renews it in time
+		leader, err := dynamoClient.AcquireLease(ctx, seriesLease, owner, interval*3/2)
+		if err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "error obteniendo turno para materializar series", "error", err)
+		}
+		if leader {
+			if err := seriesService.MaterializeAll(ctx); err != nil && ctx.Err() == nil {
+				slog.WarnContext(ctx, "error materializando series", "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordPriceChanges periodically records in the price history the changes
+// brought by time steps until ctx is cancelled. Like materializeSeries, only
+// the replica holding the lease runs each pass.
+func recordPriceChanges(ctx context.Context, dynamoClient *db.DynamoClient, interval time.Duration) {
+	owner := leaseOwner()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		leader, err := dynamoClient.AcquireLease(ctx, pricesLease, owner, interval*3/2)
+		if err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "error obteniendo turno para registrar cambios de precio", "error", err)
+		}
+		if leader {
+			if err := service.RecordPriceChanges(ctx, dynamoClient); err != nil && ctx.Err() == nil {
+				slog.WarnContext(ctx, "error registrando cambios de precio", "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// leaseOwner identifies this replica when it takes a lease.
+func leaseOwner() string {
+	owner := uuid.NewString()
+	if hostname, err := os.Hostname(); err == nil {
+		owner = hostname + "/" + owner
+	}
+	return owner
+}
+
+// rebuildSearchIndex periodically reloads the search index from the events
+// table until ctx is cancelled.
+func rebuildSearchIndex(ctx context.Context, dynamoClient *db.DynamoClient, searchIndex *search.Index, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := searchIndex.Rebuild(func() ([]model.Event, error) {
+			return dynamoClient.ScanEvents(ctx)
+		})
+		if err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "error reconstruyendo índice de búsqueda", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func waitWorkers(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ScopeEventsRead      = "events:read"
+	ScopeEventsWrite     = "events:write"
+	ScopeCategoriesWrite = "categories:write"
+	ScopeVenuesWrite     = "venues:write"
+	ScopeSeatsHold       = "seats:hold"
+	ScopePromosWrite     = "promos:write"
+	ScopePromosRedeem    = "promos:redeem"
+)
+
+// Scopes lists every scope an API key may be granted.
+var Scopes = []string{
+	ScopeEventsRead, ScopeEventsWrite, ScopeCategoriesWrite, ScopeVenuesWrite,
+	ScopeSeatsHold, ScopePromosWrite, ScopePromosRedeem,
+}
+
+// apiKeyPrefix identifies keys issued by this service, e.g. in secret
+// scanners.
+const apiKeyPrefix = "tev"
+
+var ErrMalformedAPIKey = errors.New("malformed API key")
+
+// ValidScope reports whether scope is known.
+func ValidScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+// GenerateAPIKey returns a new plaintext key for id and the hash to store.
+// Keys look like tev_<id>_<secret> so the record can be fetched by ID before
+// comparing hashes.
+func GenerateAPIKey(id uuid.UUID) (plaintext, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(secret)
+	plaintext = apiKeyPrefix + "_" + strings.ReplaceAll(id.String(), "-", "") + "_" + encoded
+	return plaintext, HashAPIKeySecret(encoded), nil
+}
+
+// ParseAPIKey splits a plaintext key into its ID and secret. The secret is
+// base64url, whose alphabet includes '_', so only the first two separators
+// are significant.
+func ParseAPIKey(plaintext string) (uuid.UUID, string, error) {
+	parts := strings.SplitN(plaintext, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyPrefix || parts[2] == "" {
+		return uuid.Nil, "", ErrMalformedAPIKey
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return uuid.Nil, "", ErrMalformedAPIKey
+	}
+	return id, parts[2], nil
+}
+
+// HashAPIKeySecret hashes a key secret for storage. Secrets are 256 random
+// bits, so a plain SHA-256 is enough.
+func HashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// MatchAPIKeySecret compares secret against a stored hash in constant time.
+func MatchAPIKeySecret(secret, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPIKeySecret(secret)), []byte(hash)) == 1
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestGenerateParseAPIKeyRoundTrip(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		id := uuid.New()
+		plaintext, hash, err := GenerateAPIKey(id)
+		if err != nil {
+			t.Fatalf("GenerateAPIKey: %v", err)
+		}
+
+		gotID, secret, err := ParseAPIKey(plaintext)
+		if err != nil {
+			t.Fatalf("ParseAPIKey(%q): %v", plaintext, err)
+		}
+		if gotID != id {
+			t.Fatalf("ParseAPIKey(%q) id = %s, want %s", plaintext, gotID, id)
+		}
+		if !MatchAPIKeySecret(secret, hash) {
+			t.Fatalf("ParseAPIKey(%q) secret does not match the stored hash", plaintext)
+		}
+	}
+}
+
+func TestParseAPIKey(t *testing.T) {
+	id := uuid.MustParse("0f8fad5b-d9cb-469f-a165-70867728950e")
+	compact := strings.ReplaceAll(id.String(), "-", "")
+
+	tests := []struct {
+		name       string
+		plaintext  string
+		wantSecret string
+		wantErr    bool
+	}{
+		{name: "valid", plaintext: "tev_" + compact + "_abc", wantSecret: "abc"},
+		{name: "secret with underscores", plaintext: "tev_" + compact + "_a_b_c", wantSecret: "a_b_c"},
+		{name: "wrong prefix", plaintext: "xyz_" + compact + "_abc", wantErr: true},
+		{name: "missing secret", plaintext: "tev_" + compact, wantErr: true},
+		{name: "empty secret", plaintext: "tev_" + compact + "_", wantErr: true},
+		{name: "bad id", plaintext: "tev_nope_abc", wantErr: true},
+		{name: "empty", plaintext: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotID, secret, err := ParseAPIKey(tt.plaintext)
+			if tt.wantErr {
+				if err != ErrMalformedAPIKey {
+					t.Fatalf("err = %v, want ErrMalformedAPIKey", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotID != id || secret != tt.wantSecret {
+				t.Fatalf("got (%s, %q), want (%s, %q)", gotID, secret, id, tt.wantSecret)
+			}
+		})
+	}
+}
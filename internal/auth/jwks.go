@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// minRefetchInterval limits how often an unknown kid triggers a refetch of
+// a remote JWKS.
+const minRefetchInterval = 30 * time.Second
+
+// JWKS is a set of RSA public keys loaded from a file or fetched from a URL.
+// Remote sets are refreshed every refresh interval and when a token
+// references an unknown kid.
+type JWKS struct {
+	url     string
+	client  *http.Client
+	refresh time.Duration
+
+	mu        sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// LoadJWKSFile reads a static JWKS document from path.
+func LoadJWKSFile(path string) (*JWKS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading JWKS file: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	return &JWKS{keys: keys}, nil
+}
+
+// NewRemoteJWKS returns a JWKS fetched lazily from url.
+func NewRemoteJWKS(url string, refresh time.Duration) *JWKS {
+	return &JWKS{
+		url:     url,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		refresh: refresh,
+	}
+}
+
+// Key returns the key for kid. An empty kid matches when the set holds a
+// single key.
+func (j *JWKS) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	if j.url != "" && j.stale() {
+		if err := j.fetch(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if key, ok := j.lookup(kid); ok {
+		return key, nil
+	}
+	if j.url != "" && j.canRefetch() {
+		if err := j.fetch(ctx); err != nil {
+			return nil, err
+		}
+		if key, ok := j.lookup(kid); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+}
+
+func (j *JWKS) lookup(kid string) (*rsa.PublicKey, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if kid == "" && len(j.keys) == 1 {
+		for _, k := range j.keys {
+			return k, true
+		}
+	}
+	key, ok := j.keys[kid]
+	return key, ok
+}
+
+func (j *JWKS) stale() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.keys == nil || time.Since(j.fetchedAt) > j.refresh
+}
+
+func (j *JWKS) canRefetch() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return time.Since(j.fetchedAt) > minRefetchInterval
+}
+
+func (j *JWKS) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return fmt.Errorf("error building JWKS request: %w", err)
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error fetching JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error fetching JWKS: unexpected status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("error reading JWKS: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	j.keys = keys
+	j.fetchedAt = time.Now()
+	j.mu.Unlock()
+	return nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func parseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range doc.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus for key %q: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent for key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS contains no RSA signing keys")
+	}
+	return keys, nil
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// jwksServer serves the keys it holds as a JWKS and counts the fetches.
+type jwksServer struct {
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	fetches int
+}
+
+func (s *jwksServer) set(keys map[string]*rsa.PublicKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func (s *jwksServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetches++
+	w.Write(jwksDocument(s.keys))
+}
+
+func jwksDocument(keys map[string]*rsa.PublicKey) []byte {
+	doc := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	for kid, key := range keys {
+		doc.Keys = append(doc.Keys, jsonWebKey{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	data, _ := json.Marshal(doc)
+	return data
+}
+
+func TestRemoteJWKSRefresh(t *testing.T) {
+	k1, k2 := newRSAKey(t), newRSAKey(t)
+	server := &jwksServer{keys: map[string]*rsa.PublicKey{"k1": &k1.PublicKey}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	ctx := context.Background()
+
+	jwks := NewRemoteJWKS(ts.URL, time.Hour)
+	if key, err := jwks.Key(ctx, "k1"); err != nil || key.N.Cmp(k1.N) != 0 {
+		t.Fatalf("Key(k1) = %v, %v", key, err)
+	}
+	if _, err := jwks.Key(ctx, "k1"); err != nil || server.fetches != 1 {
+		t.Fatalf("cached Key(k1): err = %v, fetches = %d, want 1", err, server.fetches)
+	}
+
+	// The provider rotates to k2; an unknown kid is not refetched right
+	// after a fetch
+	server.set(map[string]*rsa.PublicKey{"k2": &k2.PublicKey})
+	if _, err := jwks.Key(ctx, "k2"); !errors.Is(err, ErrUnknownKey) || server.fetches != 1 {
+		t.Fatalf("Key(k2) right after a fetch: err = %v, fetches = %d, want ErrUnknownKey and 1", err, server.fetches)
+	}
+
+	jwks.fetchedAt = time.Now().Add(-minRefetchInterval - time.Second)
+	if key, err := jwks.Key(ctx, "k2"); err != nil || key.N.Cmp(k2.N) != 0 || server.fetches != 2 {
+		t.Fatalf("Key(k2) after rotation: key = %v, err = %v, fetches = %d", key, err, server.fetches)
+	}
+	if _, err := jwks.Key(ctx, "k1"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Key(k1) after rotation: err = %v, want ErrUnknownKey", err)
+	}
+
+	// Past the refresh interval the set is fetched again even for a known kid
+	server.set(map[string]*rsa.PublicKey{"k2": &k2.PublicKey, "k1": &k1.PublicKey})
+	jwks.fetchedAt = time.Now().Add(-2 * time.Hour)
+	if _, err := jwks.Key(ctx, "k2"); err != nil || server.fetches != 3 {
+		t.Fatalf("Key(k2) when stale: err = %v, fetches = %d, want 3", err, server.fetches)
+	}
+	if _, err := jwks.Key(ctx, ""); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Key(\"\") with two keys: err = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestRemoteJWKSFetchError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	if _, err := NewRemoteJWKS(ts.URL, time.Hour).Key(context.Background(), "k1"); err == nil || errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("err = %v, want a fetch error", err)
+	}
+}
+
+func TestLoadJWKSFile(t *testing.T) {
+	key := newRSAKey(t)
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "signing key", content: string(jwksDocument(map[string]*rsa.PublicKey{"k1": &key.PublicKey}))},
+		{name: "only encryption keys", content: `{"keys":[{"kty":"RSA","kid":"k1","use":"enc","n":"AQAB","e":"AQAB"}]}`, wantErr: true},
+		{name: "only EC keys", content: `{"keys":[{"kty":"EC","kid":"k1"}]}`, wantErr: true},
+		{name: "bad modulus", content: `{"keys":[{"kty":"RSA","kid":"k1","n":"!!","e":"AQAB"}]}`, wantErr: true},
+		{name: "not JSON", content: `keys`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "jwks.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("writing JWKS file: %v", err)
+			}
+			jwks, err := LoadJWKSFile(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, err := jwks.Key(context.Background(), "k1"); err != nil || got.N.Cmp(key.N) != 0 {
+				t.Fatalf("Key(k1) = %v, %v", got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrMissingKey   = errors.New("no key configured for token algorithm")
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// Claims are the JWT claims understood by the service. Roles may be sent
+// either as a "roles" array or a single "role" string.
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles    []string `json:"roles,omitempty"`
+	Role     string   `json:"role,omitempty"`
+	TenantID string   `json:"tenant_id,omitempty"`
+}
+
+// Verifier validates HS256 tokens with a shared secret and RS256 tokens with
+// keys from a JWKS.
+type Verifier struct {
+	hmacSecret []byte
+	jwks       *JWKS
+	parser     *jwt.Parser
+}
+
+type VerifierConfig struct {
+	HMACSecret string
+	JWKS       *JWKS
+	Issuer     string
+	Audience   string
+}
+
+func NewVerifier(cfg VerifierConfig) *Verifier {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	return &Verifier{
+		hmacSecret: []byte(cfg.HMACSecret),
+		jwks:       cfg.JWKS,
+		parser:     jwt.NewParser(opts...),
+	}
+}
+
+// Enabled reports whether at least one key source is configured.
+func (v *Verifier) Enabled() bool {
+	return len(v.hmacSecret) > 0 || v.jwks != nil
+}
+
+// Verify checks the token signature and standard claims and returns the
+// caller it identifies.
+func (v *Verifier) Verify(ctx context.Context, tokenString string) (*Principal, error) {
+	claims := &Claims{}
+	_, err := v.parser.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (any, error) {
+		switch t.Method.Alg() {
+		case jwt.SigningMethodHS256.Alg():
+			if len(v.hmacSecret) == 0 {
+				return nil, ErrMissingKey
+			}
+			return v.hmacSecret, nil
+		case jwt.SigningMethodRS256.Alg():
+			if v.jwks == nil {
+				return nil, ErrMissingKey
+			}
+			kid, _ := t.Header["kid"].(string)
+			return v.jwks.Key(ctx, kid)
+		}
+		return nil, ErrMissingKey
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	roles := slices.Clone(claims.Roles)
+	if claims.Role != "" && !slices.Contains(roles, claims.Role) {
+		roles = append(roles, claims.Role)
+	}
+	return &Principal{Subject: claims.Subject, Roles: roles, TenantID: claims.TenantID}, nil
+}
@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating RSA key: %v", err)
+	}
+	return key
+}
+
+func validClaims() Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			Issuer:    "https://issuer.example.com",
+			Audience:  jwt.ClaimStrings{"ticket-events"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles:    []string{RoleOrganizer},
+		Role:     RoleAdmin,
+		TenantID: "acme",
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return signed
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey := newRSAKey(t)
+	otherKey := newRSAKey(t)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: mustMarshalPKIX(t, &rsaKey.PublicKey)})
+	jwks := &JWKS{keys: map[string]*rsa.PublicKey{"k1": &rsaKey.PublicKey}}
+
+	withClaims := func(edit func(*Claims)) Claims {
+		claims := validClaims()
+		edit(&claims)
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		cfg     VerifierConfig
+		token   string
+		wantErr error
+	}{
+		{
+			name:  "HS256",
+			cfg:   VerifierConfig{HMACSecret: testSecret},
+			token: sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), validClaims()),
+		},
+		{
+			name:  "RS256",
+			cfg:   VerifierConfig{JWKS: jwks},
+			token: sign(t, jwt.SigningMethodRS256, "k1", rsaKey, validClaims()),
+		},
+		{
+			name:  "RS256 without kid and a single key",
+			cfg:   VerifierConfig{JWKS: jwks},
+			token: sign(t, jwt.SigningMethodRS256, "", rsaKey, validClaims()),
+		},
+		{
+			name:  "issuer and audience match",
+			cfg:   VerifierConfig{HMACSecret: testSecret, Issuer: "https://issuer.example.com", Audience: "ticket-events"},
+			token: sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), validClaims()),
+		},
+		{
+			name:    "HS256 wrong secret",
+			cfg:     VerifierConfig{HMACSecret: testSecret},
+			token:   sign(t, jwt.SigningMethodHS256, "", []byte("other-secret"), validClaims()),
+			wantErr: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:    "RS256 signed by another key",
+			cfg:     VerifierConfig{JWKS: jwks},
+			token:   sign(t, jwt.SigningMethodRS256, "k1", otherKey, validClaims()),
+			wantErr: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:    "expired",
+			cfg:     VerifierConfig{HMACSecret: testSecret},
+			token:   sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), withClaims(func(c *Claims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute)) })),
+			wantErr: jwt.ErrTokenExpired,
+		},
+		{
+			name:    "no expiration",
+			cfg:     VerifierConfig{HMACSecret: testSecret},
+			token:   sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), withClaims(func(c *Claims) { c.ExpiresAt = nil })),
+			wantErr: jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:    "wrong issuer",
+			cfg:     VerifierConfig{HMACSecret: testSecret, Issuer: "https://issuer.example.com"},
+			token:   sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), withClaims(func(c *Claims) { c.Issuer = "https://evil.example.com" })),
+			wantErr: jwt.ErrTokenInvalidIssuer,
+		},
+		{
+			name:    "wrong audience",
+			cfg:     VerifierConfig{HMACSecret: testSecret, Audience: "ticket-events"},
+			token:   sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), withClaims(func(c *Claims) { c.Audience = jwt.ClaimStrings{"other-api"} })),
+			wantErr: jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name:    "missing subject",
+			cfg:     VerifierConfig{HMACSecret: testSecret},
+			token:   sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), withClaims(func(c *Claims) { c.Subject = "" })),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "unknown kid",
+			cfg:     VerifierConfig{JWKS: jwks},
+			token:   sign(t, jwt.SigningMethodRS256, "k2", rsaKey, validClaims()),
+			wantErr: ErrUnknownKey,
+		},
+		{
+			name:    "RS256 without JWKS",
+			cfg:     VerifierConfig{HMACSecret: testSecret},
+			token:   sign(t, jwt.SigningMethodRS256, "k1", rsaKey, validClaims()),
+			wantErr: ErrMissingKey,
+		},
+		{
+			// The public key is known to anyone, so it must never be used as
+			// an HMAC secret
+			name:    "HS256 signed with the RSA public key",
+			cfg:     VerifierConfig{JWKS: jwks},
+			token:   sign(t, jwt.SigningMethodHS256, "k1", publicPEM, validClaims()),
+			wantErr: ErrMissingKey,
+		},
+		{
+			name:    "HS256 signed with the RSA public key and a secret configured",
+			cfg:     VerifierConfig{HMACSecret: testSecret, JWKS: jwks},
+			token:   sign(t, jwt.SigningMethodHS256, "k1", publicPEM, validClaims()),
+			wantErr: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:    "HS384 not accepted",
+			cfg:     VerifierConfig{HMACSecret: testSecret},
+			token:   sign(t, jwt.SigningMethodHS384, "", []byte(testSecret), validClaims()),
+			wantErr: jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:    "unsigned",
+			cfg:     VerifierConfig{HMACSecret: testSecret},
+			token:   sign(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, validClaims()),
+			wantErr: jwt.ErrTokenSignatureInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := NewVerifier(tt.cfg).Verify(context.Background(), tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, ErrInvalidToken) || !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if principal.Subject != "user-1" || principal.TenantID != "acme" {
+				t.Fatalf("principal = %+v", principal)
+			}
+			if !slices.Equal(principal.Roles, []string{RoleOrganizer, RoleAdmin}) {
+				t.Fatalf("roles = %v, want [organizer admin]", principal.Roles)
+			}
+		})
+	}
+}
+
+func mustMarshalPKIX(t *testing.T, key *rsa.PublicKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("marshaling public key: %v", err)
+	}
+	return der
+}
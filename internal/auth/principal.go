@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+const (
+	RoleAdmin     = "admin"
+	RoleOrganizer = "organizer"
+	RoleViewer    = "viewer"
+)
+
+// Principal is the authenticated caller of a request. TenantID is empty for
+// tokens that are not bound to a tenant. Callers using an API key have
+// APIKeyID set, act on behalf of the key owner and are limited to Scopes.
+type Principal struct {
+	Subject  string
+	Roles    []string
+	TenantID string
+	APIKeyID string
+	Scopes   []string
+}
+
+// IsAPIKey reports whether the principal authenticated with an API key.
+func (p *Principal) IsAPIKey() bool {
+	return p != nil && p.APIKeyID != ""
+}
+
+// HasScope reports whether the principal was granted scope.
+func (p *Principal) HasScope(scope string) bool {
+	return p != nil && slices.Contains(p.Scopes, scope)
+}
+
+// HasRole reports whether the principal holds any of roles.
+func (p *Principal) HasRole(roles ...string) bool {
+	if p == nil {
+		return false
+	}
+	for _, r := range roles {
+		if slices.Contains(p.Roles, r) {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, or nil for anonymous
+// requests.
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the runtime settings of the service, read from environment
+// variables with defaults suited for local development against LocalStack.
+type Config struct {
+	Port              string
+	LogLevel          string
+	QueueURL          string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+	ReadinessTimeout  time.Duration
+	ReadinessCacheTTL time.Duration
+	MetricsRefresh    time.Duration
+	ServiceName       string
+	TraceExporter     string
+	TraceSampleRatio  float64
+	JWTSecret         string
+	JWKSFile          string
+	JWKSURL           string
+	JWKSRefresh       time.Duration
+	JWTIssuer         string
+	JWTAudience       string
+	TenantBaseDomain  string
+	DefaultTenant     string
+	RateLimitBackend  string
+	RateLimitAnon     string
+	RateLimitAPIKey   string
+	RateLimitRoutes   string
+	TrustedProxies    []string
+	IdempotencyTTL    time.Duration
+	IdempotencyWait   time.Duration
+	SeriesHorizon     time.Duration
+	SeriesInterval    time.Duration
+	SearchRebuild     time.Duration
+	PriceRefresh      time.Duration
+	SeatHoldTTL       time.Duration
+	ImageBucket       string
+	ImageBaseURL      string
+	ImageMaxMB        int
+	MaxBodyKB         int
+}
+
+func Load() Config {
+	return Config{
+		Port:              getEnv("PORT", "8080"),
+		LogLevel:          getEnv("LOG_LEVEL", "info"),
+		QueueURL:          getEnv("QUEUE_URL", "http://localhost:4566/000000000000/event-queue"),
+		ReadTimeout:       getDuration("HTTP_READ_TIMEOUT", 10*time.Second),
+		ReadHeaderTimeout: getDuration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		WriteTimeout:      getDuration("HTTP_WRITE_TIMEOUT", 15*time.Second),
+		IdleTimeout:       getDuration("HTTP_IDLE_TIMEOUT", 60*time.Second),
+		ShutdownTimeout:   getDuration("SHUTDOWN_TIMEOUT", 20*time.Second),
+		ReadinessTimeout:  getDuration("READINESS_TIMEOUT", 2*time.Second),
+		ReadinessCacheTTL: getDuration("READINESS_CACHE_TTL", 5*time.Second),
+		MetricsRefresh:    getDuration("METRICS_REFRESH_INTERVAL", time.Minute),
+		ServiceName:       getEnv("OTEL_SERVICE_NAME", "ticket-events"),
+		TraceExporter:     getEnv("OTEL_TRACES_EXPORTER", "none"),
+		TraceSampleRatio:  getFloat("OTEL_TRACES_SAMPLE_RATIO", 1),
+		JWTSecret:         getEnv("JWT_HS256_SECRET", ""),
+		JWKSFile:          getEnv("JWT_JWKS_FILE", ""),
+		JWKSURL:           getEnv("JWT_JWKS_URL", ""),
+		JWKSRefresh:       getDuration("JWT_JWKS_REFRESH_INTERVAL", 15*time.Minute),
+		JWTIssuer:         getEnv("JWT_ISSUER", ""),
+		JWTAudience:       getEnv("JWT_AUDIENCE", ""),
+		TenantBaseDomain:  getEnv("TENANT_BASE_DOMAIN", ""),
+		DefaultTenant:     os.Getenv("DEFAULT_TENANT"),
+		RateLimitBackend:  getEnv("RATE_LIMIT_BACKEND", "memory"),
+		RateLimitAnon:     getEnv("RATE_LIMIT_ANONYMOUS", "10:20"),
+		RateLimitAPIKey:   getEnv("RATE_LIMIT_API_KEY", "50:100"),
+		RateLimitRoutes:   os.Getenv("RATE_LIMIT_ROUTES"),
+		TrustedProxies:    getList("TRUSTED_PROXIES"),
+		IdempotencyTTL:    getDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		IdempotencyWait:   getDuration("IDEMPOTENCY_WAIT", 5*time.Second),
+		SeriesHorizon:     getDuration("SERIES_HORIZON", 90*24*time.Hour),
+		SeriesInterval:    getDuration("SERIES_MATERIALIZE_INTERVAL", time.Hour),
+		SearchRebuild:     getDuration("SEARCH_REBUILD_INTERVAL", 10*time.Minute),
+		PriceRefresh:      getDuration("PRICE_REFRESH_INTERVAL", 5*time.Minute),
+		SeatHoldTTL:       getDuration("SEAT_HOLD_TTL", 10*time.Minute),
+		ImageBucket:       getEnv("IMAGE_BUCKET", "event-images"),
+		ImageBaseURL:      getEnv("IMAGE_BASE_URL", "http://localhost:4566/event-images"),
+		ImageMaxMB:        getInt("IMAGE_MAX_MB", 5),
+		MaxBodyKB:         getInt("MAX_BODY_KB", 1024),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+// getList splits a comma-separated value, skipping empty items. It returns
+// nil if the variable is unset or empty.
+func getList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return fallback
+	}
+	// Every duration is a timeout, TTL or ticker interval, none of which
+	// can be zero or negative (time.NewTicker panics on them)
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		slog.Warn("valor de configuración inválido, usando valor por defecto", "key", key, "value", v, "default", fallback.String())
+		return fallback
+	}
+	return d
+}
+
+func getFloat(key string, fallback float64) float64 {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		slog.Warn("valor de configuración inválido, usando valor por defecto", "key", key, "value", v, "default", fallback)
+		return fallback
+	}
+	return f
+}
+
+func getInt(key string, fallback int) int {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		slog.Warn("valor de configuración inválido, usando valor por defecto", "key", key, "value", v, "default", fallback)
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetDuration(t *testing.T) {
+	const fallback = time.Minute
+	tests := []struct {
+		name  string
+		value string
+		set   bool
+		want  time.Duration
+	}{
+		{name: "unset", want: fallback},
+		{name: "empty", set: true, value: "", want: fallback},
+		{name: "valid", set: true, value: "90s", want: 90 * time.Second},
+		{name: "zero", set: true, value: "0s", want: fallback},
+		{name: "bare zero", set: true, value: "0", want: fallback},
+		{name: "negative", set: true, value: "-5m", want: fallback},
+		{name: "missing unit", set: true, value: "10", want: fallback},
+		{name: "garbage", set: true, value: "soon", want: fallback},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.set {
+				t.Setenv("TEST_DURATION", tt.value)
+			}
+			if got := getDuration("TEST_DURATION", fallback); got != tt.want {
+				t.Fatalf("getDuration(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetInt(t *testing.T) {
+	const fallback = 7
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{name: "empty", value: "", want: fallback},
+		{name: "valid", value: "42", want: 42},
+		{name: "zero", value: "0", want: fallback},
+		{name: "negative", value: "-1", want: fallback},
+		{name: "garbage", value: "many", want: fallback},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_INT", tt.value)
+			if got := getInt("TEST_INT", fallback); got != tt.want {
+				t.Fatalf("getInt(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadIntervalsArePositive(t *testing.T) {
+	for _, key := range []string{"METRICS_REFRESH_INTERVAL", "SERIES_MATERIALIZE_INTERVAL", "SEARCH_REBUILD_INTERVAL", "PRICE_REFRESH_INTERVAL"} {
+		t.Setenv(key, "0s")
+	}
+	cfg := Load()
+	for name, d := range map[string]time.Duration{
+		"MetricsRefresh": cfg.MetricsRefresh,
+		"SeriesInterval": cfg.SeriesInterval,
+		"SearchRebuild":  cfg.SearchRebuild,
+		"PriceRefresh":   cfg.PriceRefresh,
+	} {
+		if d <= 0 {
+			t.Fatalf("%s = %v, want a positive interval", name, d)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+const (
+	APIKeysTable = "api_keys"
+	// APIKeysByTenantIndex is the GSI on api_keys keyed by tenant_id and
+	// sorted by created_at.
+	APIKeysByTenantIndex = "tenant_id-index"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrAPIKeyChanged is returned when a key was rotated or revoked since it
+	// was read.
+	ErrAPIKeyChanged = errors.New("api key changed concurrently")
+)
+
+func (d *DynamoClient) SaveAPIKey(ctx context.Context, key model.APIKey) error {
+	tenantID, err := scopedTenant(ctx, key.TenantID)
+	if err != nil {
+		return err
+	}
+
+	item := map[string]types.AttributeValue{
+		"id":         &types.AttributeValueMemberS{Value: key.ID.String()},
+		"tenant_id":  &types.AttributeValueMemberS{Value: tenantID},
+		"name":       &types.AttributeValueMemberS{Value: key.Name},
+		"owner_id":   &types.AttributeValueMemberS{Value: key.OwnerID},
+		"key_hash":   &types.AttributeValueMemberS{Value: key.KeyHash},
+		"scopes":     &types.AttributeValueMemberSS{Value: key.Scopes},
+		"created_at": &types.AttributeValueMemberS{Value: key.CreatedAt.Format(time.RFC3339)},
+		"updated_at": &types.AttributeValueMemberS{Value: key.UpdatedAt.Format(time.RFC3339)},
+	}
+	setOptionalTime(item, "expires_at", key.ExpiresAt)
+	setOptionalTime(item, "revoked_at", key.RevokedAt)
+	setOptionalTime(item, "last_used_at", key.LastUsedAt)
+
+	_, err = d.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                 aws.String(APIKeysTable),
+		Item:                      item,
+		ConditionExpression:       aws.String(sameTenantOrNewCondition),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+	})
+	if err != nil {
+		return fmt.Errorf("error saving API key: %w", err)
+	}
+	return nil
+}
+
+// GetAPIKey returns a key of the request's tenant.
+func (d *DynamoClient) GetAPIKey(ctx context.Context, id uuid.UUID) (*model.APIKey, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key, err := d.LookupAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.TenantID != tenantID {
+		return nil, ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+// LookupAPIKey fetches a key by ID regardless of tenant. It is only meant for
+// authentication, where the key itself determines the tenant; callers must
+// check the key's tenant against the request.
+func (d *DynamoClient) LookupAPIKey(ctx context.Context, id uuid.UUID) (*model.APIKey, error) {
+	result, err := d.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(APIKeysTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id.String()},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting API key: %w", err)
+	}
+	if result.Item == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	return unmarshalAPIKey(result.Item)
+}
+
+// ListAPIKeys lists the tenant's keys, newest first.
+func (d *DynamoClient) ListAPIKeys(ctx context.Context) ([]model.APIKey, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []model.APIKey
+	paginator := dynamodb.NewQueryPaginator(d.Client, &dynamodb.QueryInput{
+		TableName:                 aws.String(APIKeysTable),
+		IndexName:                 aws.String(APIKeysByTenantIndex),
+		KeyConditionExpression:    aws.String("#tenant_id = :tenant_id"),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+		ScanIndexForward:          aws.Bool(false),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing API keys: %w", err)
+		}
+		for _, item := range page.Items {
+			key, err := unmarshalAPIKey(item)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, *key)
+		}
+	}
+	return keys, nil
+}
+
+// TouchAPIKey records that the key was used at the given time.
+func (d *DynamoClient) TouchAPIKey(ctx context.Context, id uuid.UUID, at time.Time) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	names := tenantAttributeNames()
+	names["#last_used_at"] = "last_used_at"
+	values := tenantAttributeValues(tenantID)
+	values[":last_used_at"] = &types.AttributeValueMemberS{Value: at.UTC().Format(time.RFC3339)}
+
+	_, err = d.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(APIKeysTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id.String()},
+		},
+		UpdateExpression:          aws.String("SET #last_used_at = :last_used_at"),
+		ConditionExpression:       aws.String("#tenant_id = :tenant_id"),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	})
+	if err != nil {
+		return fmt.Errorf("error updating API key last use: %w", err)
+	}
+	return nil
+}
+
+// RotateAPIKey replaces the hash of a key still holding currentHash and not
+// revoked, returning the updated key. It fails with ErrAPIKeyChanged if a
+// concurrent rotate or revoke won.
+func (d *DynamoClient) RotateAPIKey(ctx context.Context, id uuid.UUID, currentHash, newHash string, at time.Time) (*model.APIKey, error) {
+	names := map[string]string{
+		"#key_hash":   "key_hash",
+		"#updated_at": "updated_at",
+	}
+	values := map[string]types.AttributeValue{
+		":current_hash": &types.AttributeValueMemberS{Value: currentHash},
+		":key_hash":     &types.AttributeValueMemberS{Value: newHash},
+		":updated_at":   &types.AttributeValueMemberS{Value: at.UTC().Format(time.RFC3339)},
+	}
+	key, err := d.updateActiveAPIKey(ctx, id,
+		"SET #key_hash = :key_hash, #updated_at = :updated_at",
+		"#key_hash = :current_hash", names, values)
+	if err != nil {
+		return nil, fmt.Errorf("error rotating API key: %w", err)
+	}
+	return key, nil
+}
+
+// RevokeAPIKey marks a key as revoked, returning the updated key. It fails
+// with ErrAPIKeyChanged if the key was already revoked.
+func (d *DynamoClient) RevokeAPIKey(ctx context.Context, id uuid.UUID, at time.Time) (*model.APIKey, error) {
+	names := map[string]string{
+		"#updated_at": "updated_at",
+	}
+	values := map[string]types.AttributeValue{
+		":revoked_at": &types.AttributeValueMemberS{Value: at.UTC().Format(time.RFC3339)},
+	}
+	key, err := d.updateActiveAPIKey(ctx, id,
+		"SET #revoked_at = :revoked_at, #updated_at = :revoked_at",
+		"", names, values)
+	if err != nil {
+		return nil, fmt.Errorf("error revoking API key: %w", err)
+	}
+	return key, nil
+}
+
+// updateActiveAPIKey applies update to a non-revoked key of the request's
+// tenant, with extraCondition ANDed in when set. Unlike SaveAPIKey it never
+// overwrites fields written concurrently, such as last_used_at.
+func (d *DynamoClient) updateActiveAPIKey(ctx context.Context, id uuid.UUID, update, extraCondition string, names map[string]string, values map[string]types.AttributeValue) (*model.APIKey, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names["#tenant_id"] = "tenant_id"
+	names["#revoked_at"] = "revoked_at"
+	values[":tenant_id"] = &types.AttributeValueMemberS{Value: tenantID}
+	condition := "#tenant_id = :tenant_id AND attribute_not_exists(#revoked_at)"
+	if extraCondition != "" {
+		condition += " AND " + extraCondition
+	}
+
+	result, err := d.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(APIKeysTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id.String()},
+		},
+		UpdateExpression:          aws.String(update),
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+		ReturnValues:              types.ReturnValueAllNew,
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return nil, ErrAPIKeyChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalAPIKey(result.Attributes)
+}
+
+func setOptionalTime(item map[string]types.AttributeValue, name string, t *time.Time) {
+	if t != nil {
+		item[name] = &types.AttributeValueMemberS{Value: t.UTC().Format(time.RFC3339)}
+	}
+}
+
+func optionalTime(item map[string]types.AttributeValue, name string) (*time.Time, error) {
+	val, ok := item[name].(*types.AttributeValueMemberS)
+	if !ok {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, val.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s time: %v", name, err)
+	}
+	return &t, nil
+}
+
+func unmarshalAPIKey(item map[string]types.AttributeValue) (*model.APIKey, error) {
+	key := &model.APIKey{}
+
+	if idVal, ok := item["id"].(*types.AttributeValueMemberS); ok {
+		id, err := uuid.Parse(idVal.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API key ID: %v", err)
+		}
+		key.ID = id
+	}
+	if tenantVal, ok := item["tenant_id"].(*types.AttributeValueMemberS); ok {
+		key.TenantID = tenantVal.Value
+	}
+	if nameVal, ok := item["name"].(*types.AttributeValueMemberS); ok {
+		key.Name = nameVal.Value
+	}
+	if ownerVal, ok := item["owner_id"].(*types.AttributeValueMemberS); ok {
+		key.OwnerID = ownerVal.Value
+	}
+	if hashVal, ok := item["key_hash"].(*types.AttributeValueMemberS); ok {
+		key.KeyHash = hashVal.Value
+	}
+	if scopesVal, ok := item["scopes"].(*types.AttributeValueMemberSS); ok {
+		key.Scopes = scopesVal.Value
+	}
+
+	var err error
+	if key.ExpiresAt, err = optionalTime(item, "expires_at"); err != nil {
+		return nil, err
+	}
+	if key.RevokedAt, err = optionalTime(item, "revoked_at"); err != nil {
+		return nil, err
+	}
+	if key.LastUsedAt, err = optionalTime(item, "last_used_at"); err != nil {
+		return nil, err
+	}
+	for name, dst := range map[string]*time.Time{"created_at": &key.CreatedAt, "updated_at": &key.UpdatedAt} {
+		t, err := optionalTime(item, name)
+		if err != nil {
+			return nil, err
+		}
+		if t != nil {
+			*dst = *t
+		}
+	}
+
+	return key, nil
+}
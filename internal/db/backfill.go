@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+// BackfillTenant assigns the tenant of ctx to the events and categories
+// stored before tenants existed. Without tenant_id they are left out of the
+// tenant_id-index and treated as another tenant's, so no request can reach
+// them. Events are saved again afterwards so the index keys derived from
+// the tenant (tenant_organizer, geo_cell) are set as well. It returns how
+// many items of each kind were assigned.
+func (d *DynamoClient) BackfillTenant(ctx context.Context) (events, categories int, err error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	categories, err = d.backfillTable(ctx, CategoriesTable, tenantID, nil)
+	if err != nil {
+		return 0, categories, err
+	}
+	events, err = d.backfillTable(ctx, EventsTable, tenantID, func(item map[string]types.AttributeValue) error {
+		event, err := d.unmarshalEvent(item)
+		if err != nil {
+			return err
+		}
+		event.TenantID = tenantID
+		return d.SaveEvent(ctx, *event)
+	})
+	return events, categories, err
+}
+
+// backfillTable sets tenant_id on the items of table that have none and
+// calls then, if set, with each of them once it succeeded.
+func (d *DynamoClient) backfillTable(ctx context.Context, table, tenantID string, then func(map[string]types.AttributeValue) error) (int, error) {
+	paginator := dynamodb.NewScanPaginator(d.Client, &dynamodb.ScanInput{
+		TableName:                aws.String(table),
+		FilterExpression:         aws.String("attribute_not_exists(#tenant_id)"),
+		ExpressionAttributeNames: tenantAttributeNames(),
+	})
+
+	count := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return count, fmt.Errorf("error scanning %s: %w", table, err)
+		}
+		for _, item := range page.Items {
+			_, err := d.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+				TableName:        aws.String(table),
+				Key:              map[string]types.AttributeValue{"id": item["id"]},
+				UpdateExpression: aws.String("SET #tenant_id = :tenant_id"),
+				// The item may have been deleted or claimed since the scan
+				ConditionExpression:       aws.String("attribute_exists(id) AND attribute_not_exists(#tenant_id)"),
+				ExpressionAttributeNames:  tenantAttributeNames(),
+				ExpressionAttributeValues: tenantAttributeValues(tenantID),
+			})
+			var conditionErr *types.ConditionalCheckFailedException
+			if errors.As(err, &conditionErr) {
+				continue
+			}
+			if err != nil {
+				return count, fmt.Errorf("error assigning tenant in %s: %w", table, err)
+			}
+			if then != nil {
+				if err := then(item); err != nil {
+					return count, err
+				}
+			}
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// maxBatchGet is the most keys DynamoDB accepts in one BatchGetItem.
+const maxBatchGet = 100
+
+// maxBatchGetRetries bounds how many times keys left unprocessed by a
+// BatchGetItem, usually because the table is throttled, are asked again.
+const maxBatchGetRetries = 8
+
+var (
+	// batchGetBackoff is the wait before the first retry of unprocessed
+	// keys; it doubles on each retry up to maxBatchGetBackoff.
+	batchGetBackoff    = 50 * time.Millisecond
+	maxBatchGetBackoff = 5 * time.Second
+)
+
+// ErrUnprocessedKeys is returned by batchGet when DynamoDB still leaves keys
+// unprocessed after maxBatchGetRetries retries.
+var ErrUnprocessedKeys = errors.New("keys left unprocessed by DynamoDB")
+
+// batchGet reads the items of table with the given keys, in batches of
+// maxBatchGet, and calls each with every item found. Keys DynamoDB does not
+// get to are retried with exponential backoff and jitter, as AWS
+// recommends, so a throttled table is not hammered.
+func (d *DynamoClient) batchGet(ctx context.Context, table string, keys []map[string]types.AttributeValue, each func(map[string]types.AttributeValue) error) error {
+	for start := 0; start < len(keys); start += maxBatchGet {
+		request := map[string]types.KeysAndAttributes{
+			table: {Keys: keys[start:min(start+maxBatchGet, len(keys))]},
+		}
+		for retry := 0; len(request) > 0; retry++ {
+			if retry > 0 {
+				if retry > maxBatchGetRetries {
+					return fmt.Errorf("error reading %s: %w", table, ErrUnprocessedKeys)
+				}
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(batchGetDelay(retry)):
+				}
+			}
+
+			result, err := d.Client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: request})
+			if err != nil {
+				return err
+			}
+			for _, item := range result.Responses[table] {
+				if err := each(item); err != nil {
+					return err
+				}
+			}
+			request = result.UnprocessedKeys
+		}
+	}
+	return nil
+}
+
+// batchGetDelay is the wait before the given retry: a random duration up to
+// batchGetBackoff doubled retry-1 times, capped at maxBatchGetBackoff.
+func batchGetDelay(retry int) time.Duration {
+	ceiling := maxBatchGetBackoff
+	if retry <= 32 {
+		ceiling = min(batchGetBackoff<<(retry-1), maxBatchGetBackoff)
+	}
+	return ceiling/2 + rand.N(ceiling/2+1)
+}
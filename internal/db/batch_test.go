@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeBatchGet answers BatchGetItem requests, returning the first
+// unprocessed keys of each request unprocessed while left is positive.
+type fakeBatchGet struct {
+	unprocessed int
+	left        int
+	requests    []int
+}
+
+func (f *fakeBatchGet) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	type key = map[string]map[string]string
+	var req struct {
+		RequestItems map[string]struct{ Keys []key }
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	keys := req.RequestItems["t"].Keys
+	f.requests = append(f.requests, len(keys))
+	resp := struct {
+		Responses       map[string][]key
+		UnprocessedKeys map[string]struct{ Keys []key } `json:",omitempty"`
+	}{Responses: map[string][]key{"t": {}}}
+	if f.left > 0 && len(keys) > 0 {
+		f.left--
+		n := min(f.unprocessed, len(keys))
+		resp.UnprocessedKeys = map[string]struct{ Keys []key }{"t": {Keys: keys[:n]}}
+		keys = keys[n:]
+	}
+	resp.Responses["t"] = keys
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// newTestClient returns a client talking to a fake DynamoDB at url.
+func newTestClient(url string) *DynamoClient {
+	return &DynamoClient{Client: dynamodb.New(dynamodb.Options{
+		BaseEndpoint:     aws.String(url),
+		Region:           "us-east-1",
+		Credentials:      credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		RetryMaxAttempts: 1,
+	})}
+}
+
+func TestBatchGet(t *testing.T) {
+	batchGetBackoff, maxBatchGetBackoff = time.Millisecond, 2*time.Millisecond
+	t.Cleanup(func() { batchGetBackoff, maxBatchGetBackoff = 50*time.Millisecond, 5*time.Second })
+
+	tests := []struct {
+		name         string
+		keys         int
+		unprocessed  int
+		throttled    int
+		wantRequests []int
+		wantErr      error
+	}{
+		{name: "no keys", keys: 0},
+		{name: "one batch", keys: 3, wantRequests: []int{3}},
+		{name: "split in batches", keys: 150, wantRequests: []int{100, 50}},
+		{name: "unprocessed keys retried", keys: 5, unprocessed: 2, throttled: 2, wantRequests: []int{5, 2, 2}},
+		{name: "retries per batch", keys: 101, unprocessed: 100, throttled: 1, wantRequests: []int{100, 100, 1}},
+		{
+			name:         "gives up when throttled for too long",
+			keys:         5,
+			unprocessed:  1,
+			throttled:    maxBatchGetRetries + 1,
+			wantRequests: []int{5, 1, 1, 1, 1, 1, 1, 1, 1},
+			wantErr:      ErrUnprocessedKeys,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeBatchGet{unprocessed: tt.unprocessed, left: tt.throttled}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+			d := newTestClient(server.URL)
+
+			keys := make([]map[string]types.AttributeValue, tt.keys)
+			for i := range keys {
+				keys[i] = map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: fmt.Sprint(i)}}
+			}
+			seen := make(map[string]bool)
+			err := d.batchGet(context.Background(), "t", keys, func(item map[string]types.AttributeValue) error {
+				seen[item["id"].(*types.AttributeValueMemberS).Value] = true
+				return nil
+			})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else if len(seen) != tt.keys {
+				t.Fatalf("got %d items, want %d", len(seen), tt.keys)
+			}
+			if fmt.Sprint(fake.requests) != fmt.Sprint(tt.wantRequests) {
+				t.Fatalf("requests = %v, want %v", fake.requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestBatchGetDelay(t *testing.T) {
+	for retry := 1; retry <= 40; retry++ {
+		ceiling := min(batchGetBackoff<<min(retry-1, 32), maxBatchGetBackoff)
+		for range 20 {
+			if d := batchGetDelay(retry); d < ceiling/2 || d > ceiling {
+				t.Fatalf("batchGetDelay(%d) = %v, want within [%v, %v]", retry, d, ceiling/2, ceiling)
+			}
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+const (
+	// TaxRatesTable is keyed by "<tenant>#<country>", so every tenant sets
+	// its own rates for a country.
+	TaxRatesTable = "tax_rates"
+	// TaxRatesByTenantIndex is the GSI on tax_rates keyed by tenant_id and
+	// sorted by country.
+	TaxRatesByTenantIndex = "tenant_id-index"
+	// CategoryFeesTable stores the service fees of each category, keyed by
+	// category ID.
+	CategoryFeesTable = "category_fees"
+)
+
+var (
+	ErrTaxJurisdictionNotFound = errors.New("tax jurisdiction not found")
+	ErrCategoryFeesNotFound    = errors.New("category fees not found")
+)
+
+// SaveTaxJurisdiction creates or replaces the taxes of a country.
+func (d *DynamoClient) SaveTaxJurisdiction(ctx context.Context, jurisdiction model.TaxJurisdiction) error {
+	tenantID, err := scopedTenant(ctx, jurisdiction.TenantID)
+	if err != nil {
+		return err
+	}
+
+	taxes := make([]types.AttributeValue, 0, len(jurisdiction.Taxes))
+	for _, tax := range jurisdiction.Taxes {
+		taxes = append(taxes, &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"name": &types.AttributeValueMemberS{Value: tax.Name},
+			"rate": &types.AttributeValueMemberN{Value: formatFloat(tax.Rate)},
+		}})
+	}
+
+	_, err = d.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(TaxRatesTable),
+		Item: map[string]types.AttributeValue{
+			"id":         &types.AttributeValueMemberS{Value: taxJurisdictionKey(tenantID, jurisdiction.Country)},
+			"tenant_id":  &types.AttributeValueMemberS{Value: tenantID},
+			"country":    &types.AttributeValueMemberS{Value: jurisdiction.Country},
+			"taxes":      &types.AttributeValueMemberL{Value: taxes},
+			"tax_fees":   &types.AttributeValueMemberBOOL{Value: jurisdiction.TaxFees},
+			"created_at": &types.AttributeValueMemberS{Value: jurisdiction.CreatedAt.Format(time.RFC3339)},
+			"updated_at": &types.AttributeValueMemberS{Value: jurisdiction.UpdatedAt.Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error saving tax jurisdiction: %w", err)
+	}
+	return nil
+}
+
+// GetTaxJurisdiction returns the taxes the request's tenant charges in
+// country, an ISO 3166-1 alpha-2 code in upper case.
+func (d *DynamoClient) GetTaxJurisdiction(ctx context.Context, country string) (*model.TaxJurisdiction, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(TaxRatesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: taxJurisdictionKey(tenantID, country)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting tax jurisdiction: %w", err)
+	}
+	if result.Item == nil {
+		return nil, ErrTaxJurisdictionNotFound
+	}
+	return unmarshalTaxJurisdiction(result.Item)
+}
+
+// ListTaxJurisdictions lists the tenant's jurisdictions by country.
+func (d *DynamoClient) ListTaxJurisdictions(ctx context.Context) ([]model.TaxJurisdiction, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jurisdictions := []model.TaxJurisdiction{}
+	paginator := dynamodb.NewQueryPaginator(d.Client, &dynamodb.QueryInput{
+		TableName:                 aws.String(TaxRatesTable),
+		IndexName:                 aws.String(TaxRatesByTenantIndex),
+		KeyConditionExpression:    aws.String("#tenant_id = :tenant_id"),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing tax jurisdictions: %w", err)
+		}
+		for _, item := range page.Items {
+			jurisdiction, err := unmarshalTaxJurisdiction(item)
+			if err != nil {
+				return nil, err
+			}
+			jurisdictions = append(jurisdictions, *jurisdiction)
+		}
+	}
+	return jurisdictions, nil
+}
+
+func (d *DynamoClient) DeleteTaxJurisdiction(ctx context.Context, country string) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(TaxRatesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: taxJurisdictionKey(tenantID, country)},
+		},
+		ConditionExpression: aws.String("attribute_exists(id)"),
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return ErrTaxJurisdictionNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting tax jurisdiction: %w", err)
+	}
+	return nil
+}
+
+// SaveCategoryFees creates or replaces the service fees of a category.
+func (d *DynamoClient) SaveCategoryFees(ctx context.Context, fees model.CategoryFees) error {
+	tenantID, err := scopedTenant(ctx, fees.TenantID)
+	if err != nil {
+		return err
+	}
+
+	list := make([]types.AttributeValue, 0, len(fees.Fees))
+	for _, fee := range fees.Fees {
+		list = append(list, &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"name":       &types.AttributeValueMemberS{Value: fee.Name},
+			"percent":    &types.AttributeValueMemberN{Value: formatFloat(fee.Percent)},
+			"per_ticket": &types.AttributeValueMemberN{Value: formatFloat(fee.PerTicket)},
+		}})
+	}
+
+	_, err = d.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(CategoryFeesTable),
+		Item: map[string]types.AttributeValue{
+			"id":         &types.AttributeValueMemberS{Value: fees.CategoryID.String()},
+			"tenant_id":  &types.AttributeValueMemberS{Value: tenantID},
+			"fees":       &types.AttributeValueMemberL{Value: list},
+			"created_at": &types.AttributeValueMemberS{Value: fees.CreatedAt.Format(time.RFC3339)},
+			"updated_at": &types.AttributeValueMemberS{Value: fees.UpdatedAt.Format(time.RFC3339)},
+		},
+		ConditionExpression:       aws.String(sameTenantOrNewCondition),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+	})
+	if err != nil {
+		return fmt.Errorf("error saving category fees: %w", err)
+	}
+	return nil
+}
+
+func (d *DynamoClient) GetCategoryFees(ctx context.Context, categoryID string) (*model.CategoryFees, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(CategoryFeesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: categoryID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting category fees: %w", err)
+	}
+	if result.Item == nil || !belongsTo(result.Item, tenantID) {
+		return nil, ErrCategoryFeesNotFound
+	}
+	return unmarshalCategoryFees(result.Item)
+}
+
+func (d *DynamoClient) DeleteCategoryFees(ctx context.Context, categoryID string) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(CategoryFeesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: categoryID},
+		},
+		ConditionExpression:       aws.String("#tenant_id = :tenant_id"),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return ErrCategoryFeesNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting category fees: %w", err)
+	}
+	return nil
+}
+
+func taxJurisdictionKey(tenantID, country string) string {
+	return tenantID + "#" + country
+}
+
+func unmarshalTaxJurisdiction(item map[string]types.AttributeValue) (*model.TaxJurisdiction, error) {
+	jurisdiction := &model.TaxJurisdiction{Taxes: []model.Tax{}}
+	var err error
+
+	if countryVal, ok := item["country"].(*types.AttributeValueMemberS); ok {
+		jurisdiction.Country = countryVal.Value
+	}
+	if tenantVal, ok := item["tenant_id"].(*types.AttributeValueMemberS); ok {
+		jurisdiction.TenantID = tenantVal.Value
+	}
+	if taxesVal, ok := item["taxes"].(*types.AttributeValueMemberL); ok {
+		for _, v := range taxesVal.Value {
+			fields, ok := v.(*types.AttributeValueMemberM)
+			if !ok {
+				continue
+			}
+			var tax model.Tax
+			if nameVal, ok := fields.Value["name"].(*types.AttributeValueMemberS); ok {
+				tax.Name = nameVal.Value
+			}
+			if rateVal, ok := fields.Value["rate"].(*types.AttributeValueMemberN); ok {
+				if tax.Rate, err = strconv.ParseFloat(rateVal.Value, 64); err != nil {
+					return nil, fmt.Errorf("invalid tax rate: %v", err)
+				}
+			}
+			jurisdiction.Taxes = append(jurisdiction.Taxes, tax)
+		}
+	}
+	if taxFeesVal, ok := item["tax_fees"].(*types.AttributeValueMemberBOOL); ok {
+		jurisdiction.TaxFees = taxFeesVal.Value
+	}
+
+	times := map[string]*time.Time{
+		"created_at": &jurisdiction.CreatedAt,
+		"updated_at": &jurisdiction.UpdatedAt,
+	}
+	for name, field := range times {
+		if val, ok := item[name].(*types.AttributeValueMemberS); ok {
+			if *field, err = time.Parse(time.RFC3339, val.Value); err != nil {
+				return nil, fmt.Errorf("invalid %s time: %v", name, err)
+			}
+		}
+	}
+
+	return jurisdiction, nil
+}
+
+func unmarshalCategoryFees(item map[string]types.AttributeValue) (*model.CategoryFees, error) {
+	fees := &model.CategoryFees{Fees: []model.ServiceFee{}}
+	var err error
+
+	if idVal, ok := item["id"].(*types.AttributeValueMemberS); ok {
+		if fees.CategoryID, err = uuid.Parse(idVal.Value); err != nil {
+			return nil, fmt.Errorf("invalid category ID: %v", err)
+		}
+	}
+	if tenantVal, ok := item["tenant_id"].(*types.AttributeValueMemberS); ok {
+		fees.TenantID = tenantVal.Value
+	}
+	if feesVal, ok := item["fees"].(*types.AttributeValueMemberL); ok {
+		for _, v := range feesVal.Value {
+			fields, ok := v.(*types.AttributeValueMemberM)
+			if !ok {
+				continue
+			}
+			var fee model.ServiceFee
+			if nameVal, ok := fields.Value["name"].(*types.AttributeValueMemberS); ok {
+				fee.Name = nameVal.Value
+			}
+			amounts := map[string]*float64{
+				"percent":    &fee.Percent,
+				"per_ticket": &fee.PerTicket,
+			}
+			for name, field := range amounts {
+				if val, ok := fields.Value[name].(*types.AttributeValueMemberN); ok {
+					if *field, err = strconv.ParseFloat(val.Value, 64); err != nil {
+						return nil, fmt.Errorf("invalid fee %s: %v", name, err)
+					}
+				}
+			}
+			fees.Fees = append(fees.Fees, fee)
+		}
+	}
+
+	times := map[string]*time.Time{
+		"created_at": &fees.CreatedAt,
+		"updated_at": &fees.UpdatedAt,
+	}
+	for name, field := range times {
+		if val, ok := item[name].(*types.AttributeValueMemberS); ok {
+			if *field, err = time.Parse(time.RFC3339, val.Value); err != nil {
+				return nil, fmt.Errorf("invalid %s time: %v", name, err)
+			}
+		}
+	}
+
+	return fees, nil
+}
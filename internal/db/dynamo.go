@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strconv"
 	"strings"
 	"time"
@@ -12,24 +13,56 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/geo"
 	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
 )
 
+const (
+	EventsTable     = "events"
+	CategoriesTable = "categories"
+
+	// EventsByOrganizerIndex is the GSI on events keyed by tenant_organizer
+	// (tenant_id#organizer_id) and sorted by created_at.
+	EventsByOrganizerIndex = "tenant_organizer-index"
+	// EventsByTenantIndex is the GSI on events keyed by tenant_id and sorted
+	// by created_at.
+	EventsByTenantIndex = "tenant_id-index"
+)
+
+// ErrTenantMismatch is returned when an item handed to the client belongs to
+// a different tenant than the request context.
+var ErrTenantMismatch = errors.New("item belongs to another tenant")
+
+// Every read and write is scoped to the tenant stored in the context: writes
+// stamp tenant_id and are conditioned on not overwriting another tenant's
+// item, and reads only return items whose tenant_id matches.
+
 type DynamoClient struct {
 	Client *dynamodb.Client
+	// indexer, if set, is told about event writes; see SetEventIndexer.
+	indexer EventIndexer
 }
 
-func (d *DynamoClient) SaveEvent(event model.Event) error {
-	fmt.Printf("Guardando evento: ID=%s, Name=%s, CategoryID=%s\n",
-		event.ID.String(), event.Name, event.CategoryID.String())
+func (d *DynamoClient) SaveEvent(ctx context.Context, event model.Event) error {
+	tenantID, err := scopedTenant(ctx, event.TenantID)
+	if err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "guardando evento",
+		"event_id", event.ID.String(), "name", event.Name, "category_id", event.CategoryID.String())
 
 	item := map[string]types.AttributeValue{
 		"id":          &types.AttributeValueMemberS{Value: event.ID.String()},
+		"tenant_id":   &types.AttributeValueMemberS{Value: tenantID},
 		"name":        &types.AttributeValueMemberS{Value: event.Name},
 		"description": &types.AttributeValueMemberS{Value: event.Description},
 		"category_id": &types.AttributeValueMemberS{Value: event.CategoryID.String()},
 		"location":    &types.AttributeValueMemberS{Value: event.Location},
-		"date":        &types.AttributeValueMemberS{Value: event.Date.Format(time.RFC3339)},
+		"starts_at":   &types.AttributeValueMemberS{Value: event.StartsAt.UTC().Format(time.RFC3339)},
+		"ends_at":     &types.AttributeValueMemberS{Value: event.EndsAt.UTC().Format(time.RFC3339)},
+		"time_zone":   &types.AttributeValueMemberS{Value: event.TimeZone},
 		"capacity":    &types.AttributeValueMemberN{Value: strconv.Itoa(event.Capacity)},
 		"price":       &types.AttributeValueMemberN{Value: fmt.Sprintf("%.2f", event.Price)},
 		"status":      &types.AttributeValueMemberS{Value: event.Status},
@@ -37,13 +70,40 @@ func (d *DynamoClient) SaveEvent(event model.Event) error {
 		"created_at":  &types.AttributeValueMemberS{Value: event.CreatedAt.Format(time.RFC3339)},
 		"updated_at":  &types.AttributeValueMemberS{Value: event.UpdatedAt.Format(time.RFC3339)},
 	}
+	// tenant_organizer is a GSI key, which DynamoDB does not allow to be empty
+	if event.OrganizerID != "" {
+		item["organizer_id"] = &types.AttributeValueMemberS{Value: event.OrganizerID}
+		item["tenant_organizer"] = &types.AttributeValueMemberS{Value: tenantOrganizer(tenantID, event.OrganizerID)}
+	}
+	setTranslations(item, event.Translations)
+	setImageVariants(item, event.ImageVariants)
+	setVenueID(item, event.VenueID)
+	// geo_cell and geohash are the keys of the geo index
+	if setCoordinates(item, event.Latitude, event.Longitude) {
+		item["geo_cell"] = &types.AttributeValueMemberS{Value: geoCell(tenantID, geo.Cell(*event.Latitude, *event.Longitude))}
+		item["geohash"] = &types.AttributeValueMemberS{Value: geo.Hash(*event.Latitude, *event.Longitude)}
+	}
+	// series_id is also a GSI key
+	if event.SeriesID != "" {
+		item["series_id"] = &types.AttributeValueMemberS{Value: event.SeriesID}
+	}
+	if event.RecurrenceID != nil {
+		item["recurrence_id"] = &types.AttributeValueMemberS{Value: event.RecurrenceID.UTC().Format(time.RFC3339)}
+	}
+	if event.Detached {
+		item["detached"] = &types.AttributeValueMemberBOOL{Value: true}
+	}
 
-	_, err := d.Client.PutItem(context.TODO(), &dynamodb.PutItemInput{
-		TableName: aws.String("events"),
-		Item:      item,
+	_, err = d.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                 aws.String(EventsTable),
+		Item:                      item,
+		ConditionExpression:       aws.String(sameTenantOrNewCondition),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
 	})
 
 	if err != nil {
+		slog.ErrorContext(ctx, "error guardando evento en DynamoDB", "event_id", event.ID.String(), "error", err)
 		var errorMsg string
 		switch {
 		case strings.Contains(err.Error(), "ResourceNotFoundException"):
@@ -55,15 +115,21 @@ func (d *DynamoClient) SaveEvent(event model.Event) error {
 		default:
 			errorMsg = fmt.Sprintf("Error guardando evento en DynamoDB: %v", err)
 		}
-		return fmt.Errorf(errorMsg)
+		return errors.New(errorMsg)
 	}
 
+	d.indexEvent(tenantID, event)
 	return nil
 }
 
-func (d *DynamoClient) GetEventByID(eventID string) (*model.Event, error) {
-	result, err := d.Client.GetItem(context.TODO(), &dynamodb.GetItemInput{
-		TableName: aws.String("events"),
+func (d *DynamoClient) GetEventByID(ctx context.Context, eventID string) (*model.Event, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(EventsTable),
 		Key: map[string]types.AttributeValue{
 			"id": &types.AttributeValueMemberS{Value: eventID},
 		},
@@ -72,7 +138,8 @@ func (d *DynamoClient) GetEventByID(eventID string) (*model.Event, error) {
 		return nil, err
 	}
 
-	if result.Item == nil {
+	// Items of other tenants are indistinguishable from missing ones
+	if result.Item == nil || !belongsTo(result.Item, tenantID) {
 		return nil, errors.New("event not found")
 	}
 
@@ -84,27 +151,71 @@ func (d *DynamoClient) GetEventByID(eventID string) (*model.Event, error) {
 	return event, nil
 }
 
-func (d *DynamoClient) GetEvents(categoryID string, limit int) ([]model.Event, error) {
-	scanInput := &dynamodb.ScanInput{
-		TableName: aws.String("events"),
-		Limit:     aws.Int32(int32(limit)),
+// GetEvents lists the tenant's events, newest first, optionally filtered by
+// category.
+func (d *DynamoClient) GetEvents(ctx context.Context, categoryID string, limit int) ([]model.Event, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		TableName:                 aws.String(EventsTable),
+		IndexName:                 aws.String(EventsByTenantIndex),
+		KeyConditionExpression:    aws.String("#tenant_id = :tenant_id"),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     aws.Int32(int32(limit)),
 	}
 
 	if categoryID != "" {
-		scanInput.FilterExpression = aws.String("#category_id = :category_id")
-		scanInput.ExpressionAttributeNames = map[string]string{
-			"#category_id": "category_id",
-		}
+		queryInput.FilterExpression = aws.String("#category_id = :category_id")
+		queryInput.ExpressionAttributeNames["#category_id"] = "category_id"
 		categoryUUID, err := uuid.Parse(categoryID)
 		if err != nil {
 			return nil, fmt.Errorf("invalid category ID format: %v", err)
 		}
-		scanInput.ExpressionAttributeValues = map[string]types.AttributeValue{
-			":category_id": &types.AttributeValueMemberS{Value: categoryUUID.String()},
+		queryInput.ExpressionAttributeValues[":category_id"] = &types.AttributeValueMemberS{Value: categoryUUID.String()}
+	}
+
+	result, err := d.Client.Query(ctx, queryInput)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []model.Event
+	for _, item := range result.Items {
+		event, err := d.unmarshalEvent(item)
+		if err != nil {
+			return nil, err
 		}
+		events = append(events, *event)
+	}
+
+	return events, nil
+}
+
+// GetEventsByOrganizer returns the events owned by organizerID, newest first.
+func (d *DynamoClient) GetEventsByOrganizer(ctx context.Context, organizerID string, limit int) ([]model.Event, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	result, err := d.Client.Scan(context.TODO(), scanInput)
+	result, err := d.Client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(EventsTable),
+		IndexName:              aws.String(EventsByOrganizerIndex),
+		KeyConditionExpression: aws.String("#tenant_organizer = :tenant_organizer"),
+		ExpressionAttributeNames: map[string]string{
+			"#tenant_organizer": "tenant_organizer",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":tenant_organizer": &types.AttributeValueMemberS{Value: tenantOrganizer(tenantID, organizerID)},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(int32(limit)),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -121,33 +232,61 @@ func (d *DynamoClient) GetEvents(categoryID string, limit int) ([]model.Event, e
 	return events, nil
 }
 
-func (d *DynamoClient) DeleteEvent(eventID string) error {
-	_, err := d.Client.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
-		TableName: aws.String("events"),
+func (d *DynamoClient) DeleteEvent(ctx context.Context, eventID string) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(EventsTable),
 		Key: map[string]types.AttributeValue{
 			"id": &types.AttributeValueMemberS{Value: eventID},
 		},
+		ConditionExpression:       aws.String("#tenant_id = :tenant_id"),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
 	})
-	return err
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return errors.New("event not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	d.unindexEvent(tenantID, eventID)
+	return nil
 }
 
-func (d *DynamoClient) SaveCategory(category model.Category) error {
-	fmt.Printf("Guardando categoría: ID=%s, Name=%s\n", category.ID.String(), category.Name)
+func (d *DynamoClient) SaveCategory(ctx context.Context, category model.Category) error {
+	tenantID, err := scopedTenant(ctx, category.TenantID)
+	if err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "guardando categoría", "category_id", category.ID.String(), "name", category.Name)
 
 	item := map[string]types.AttributeValue{
 		"id":          &types.AttributeValueMemberS{Value: category.ID.String()},
+		"tenant_id":   &types.AttributeValueMemberS{Value: tenantID},
 		"name":        &types.AttributeValueMemberS{Value: category.Name},
 		"description": &types.AttributeValueMemberS{Value: category.Description},
 		"created_at":  &types.AttributeValueMemberS{Value: category.CreatedAt.Format(time.RFC3339)},
 		"updated_at":  &types.AttributeValueMemberS{Value: category.UpdatedAt.Format(time.RFC3339)},
 	}
-
-	_, err := d.Client.PutItem(context.TODO(), &dynamodb.PutItemInput{
-		TableName: aws.String("categories"),
-		Item:      item,
+	setTranslations(item, category.Translations)
+
+	_, err = d.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                 aws.String(CategoriesTable),
+		Item:                      item,
+		ConditionExpression:       aws.String(sameTenantOrNewCondition),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
 	})
 
 	if err != nil {
+		slog.ErrorContext(ctx, "error guardando categoría en DynamoDB", "category_id", category.ID.String(), "error", err)
 		var errorMsg string
 		switch {
 		case strings.Contains(err.Error(), "ResourceNotFoundException"):
@@ -159,12 +298,116 @@ func (d *DynamoClient) SaveCategory(category model.Category) error {
 		default:
 			errorMsg = fmt.Sprintf("Error guardando categoría en DynamoDB: %v", err)
 		}
-		return fmt.Errorf(errorMsg)
+		return errors.New(errorMsg)
+	}
+
+	return nil
+}
+
+func (d *DynamoClient) GetCategoryByID(ctx context.Context, categoryID string) (*model.Category, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(CategoriesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: categoryID},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Item == nil || !belongsTo(result.Item, tenantID) {
+		return nil, errors.New("category not found")
+	}
+
+	return d.unmarshalCategory(result.Item)
+}
+
+// CountEventsByStatus scans the events table projecting only the tenant and
+// status and returns how many events each tenant has in each status. It is
+// meant for internal metrics and deliberately spans all tenants.
+func (d *DynamoClient) CountEventsByStatus(ctx context.Context) (map[string]map[string]int, error) {
+	counts := make(map[string]map[string]int)
+	paginator := dynamodb.NewScanPaginator(d.Client, &dynamodb.ScanInput{
+		TableName:                aws.String(EventsTable),
+		ProjectionExpression:     aws.String("#tenant_id, #status"),
+		ExpressionAttributeNames: map[string]string{"#tenant_id": "tenant_id", "#status": "status"},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error counting events by status: %w", err)
+		}
+		for _, item := range page.Items {
+			tenantID := "unknown"
+			if tenantVal, ok := item["tenant_id"].(*types.AttributeValueMemberS); ok {
+				tenantID = tenantVal.Value
+			}
+			if counts[tenantID] == nil {
+				counts[tenantID] = make(map[string]int)
+			}
+			if statusVal, ok := item["status"].(*types.AttributeValueMemberS); ok {
+				counts[tenantID][statusVal.Value]++
+			}
+		}
 	}
+	return counts, nil
+}
 
+// PingTable verifies that the table exists and is reachable.
+func (d *DynamoClient) PingTable(ctx context.Context, table string) error {
+	_, err := d.Client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(table),
+	})
+	if err != nil {
+		return fmt.Errorf("error describing table %s: %w", table, err)
+	}
 	return nil
 }
 
+// tenantOrganizer is the tenant_organizer key of events of organizerID in
+// tenantID. The tenant is part of the key so the same subject in two tenants
+// gets separate partitions and a query needs no tenant filter.
+func tenantOrganizer(tenantID, organizerID string) string {
+	return tenantID + "#" + organizerID
+}
+
+// sameTenantOrNewCondition lets a put create an item or overwrite one of the
+// same tenant, never another tenant's item with a colliding key.
+const sameTenantOrNewCondition = "attribute_not_exists(id) OR #tenant_id = :tenant_id"
+
+// scopedTenant returns the tenant of ctx, rejecting items already stamped
+// with a different tenant.
+func scopedTenant(ctx context.Context, itemTenant string) (string, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return "", err
+	}
+	if itemTenant != "" && itemTenant != tenantID {
+		return "", ErrTenantMismatch
+	}
+	return tenantID, nil
+}
+
+func tenantAttributeNames() map[string]string {
+	return map[string]string{"#tenant_id": "tenant_id"}
+}
+
+func tenantAttributeValues(tenantID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		":tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+	}
+}
+
+func belongsTo(item map[string]types.AttributeValue, tenantID string) bool {
+	tenantVal, ok := item["tenant_id"].(*types.AttributeValueMemberS)
+	return ok && tenantVal.Value == tenantID
+}
+
 func (d *DynamoClient) unmarshalEvent(item map[string]types.AttributeValue) (*model.Event, error) {
 	event := &model.Event{}
 
@@ -176,6 +419,10 @@ func (d *DynamoClient) unmarshalEvent(item map[string]types.AttributeValue) (*mo
 		event.ID = id
 	}
 
+	if tenantVal, ok := item["tenant_id"].(*types.AttributeValueMemberS); ok {
+		event.TenantID = tenantVal.Value
+	}
+
 	if nameVal, ok := item["name"].(*types.AttributeValueMemberS); ok {
 		event.Name = nameVal.Value
 	}
@@ -192,16 +439,43 @@ func (d *DynamoClient) unmarshalEvent(item map[string]types.AttributeValue) (*mo
 		event.CategoryID = categoryID
 	}
 
+	if organizerIDVal, ok := item["organizer_id"].(*types.AttributeValueMemberS); ok {
+		event.OrganizerID = organizerIDVal.Value
+	}
+
 	if locationVal, ok := item["location"].(*types.AttributeValueMemberS); ok {
 		event.Location = locationVal.Value
 	}
 
-	if dateVal, ok := item["date"].(*types.AttributeValueMemberS); ok {
+	if startsAtVal, ok := item["starts_at"].(*types.AttributeValueMemberS); ok {
+		startsAt, err := time.Parse(time.RFC3339, startsAtVal.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid starts_at time: %v", err)
+		}
+		event.StartsAt = startsAt.UTC()
+	} else if dateVal, ok := item["date"].(*types.AttributeValueMemberS); ok {
+		// Events created before starts_at existed only have a date
 		date, err := time.Parse(time.RFC3339, dateVal.Value)
 		if err != nil {
 			return nil, fmt.Errorf("invalid date: %v", err)
 		}
-		event.Date = date
+		event.StartsAt = date.UTC()
+	}
+
+	if endsAtVal, ok := item["ends_at"].(*types.AttributeValueMemberS); ok {
+		endsAt, err := time.Parse(time.RFC3339, endsAtVal.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ends_at time: %v", err)
+		}
+		event.EndsAt = endsAt.UTC()
+	} else {
+		event.EndsAt = event.StartsAt
+	}
+
+	if timeZoneVal, ok := item["time_zone"].(*types.AttributeValueMemberS); ok {
+		event.TimeZone = timeZoneVal.Value
+	} else {
+		event.TimeZone = "UTC"
 	}
 
 	if capacityVal, ok := item["capacity"].(*types.AttributeValueMemberN); ok {
@@ -244,5 +518,79 @@ func (d *DynamoClient) unmarshalEvent(item map[string]types.AttributeValue) (*mo
 		event.UpdatedAt = updatedAt
 	}
 
+	event.Translations = unmarshalTranslations(item)
+	event.ImageVariants = unmarshalImageVariants(item)
+
+	venueID, err := unmarshalVenueID(item)
+	if err != nil {
+		return nil, err
+	}
+	event.VenueID = venueID
+
+	if event.Latitude, event.Longitude, err = unmarshalCoordinates(item); err != nil {
+		return nil, err
+	}
+
+	if seriesIDVal, ok := item["series_id"].(*types.AttributeValueMemberS); ok {
+		event.SeriesID = seriesIDVal.Value
+	}
+
+	if recurrenceIDVal, ok := item["recurrence_id"].(*types.AttributeValueMemberS); ok {
+		recurrenceID, err := time.Parse(time.RFC3339, recurrenceIDVal.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recurrence_id time: %v", err)
+		}
+		recurrenceID = recurrenceID.UTC()
+		event.RecurrenceID = &recurrenceID
+	}
+
+	if detachedVal, ok := item["detached"].(*types.AttributeValueMemberBOOL); ok {
+		event.Detached = detachedVal.Value
+	}
+
 	return event, nil
+}
+
+func (d *DynamoClient) unmarshalCategory(item map[string]types.AttributeValue) (*model.Category, error) {
+	category := &model.Category{}
+
+	if idVal, ok := item["id"].(*types.AttributeValueMemberS); ok {
+		id, err := uuid.Parse(idVal.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid category ID: %v", err)
+		}
+		category.ID = id
+	}
+
+	if tenantVal, ok := item["tenant_id"].(*types.AttributeValueMemberS); ok {
+		category.TenantID = tenantVal.Value
+	}
+
+	if nameVal, ok := item["name"].(*types.AttributeValueMemberS); ok {
+		category.Name = nameVal.Value
+	}
+
+	if descriptionVal, ok := item["description"].(*types.AttributeValueMemberS); ok {
+		category.Description = descriptionVal.Value
+	}
+
+	if createdAtVal, ok := item["created_at"].(*types.AttributeValueMemberS); ok {
+		createdAt, err := time.Parse(time.RFC3339, createdAtVal.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid created_at time: %v", err)
+		}
+		category.CreatedAt = createdAt
+	}
+
+	if updatedAtVal, ok := item["updated_at"].(*types.AttributeValueMemberS); ok {
+		updatedAt, err := time.Parse(time.RFC3339, updatedAtVal.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid updated_at time: %v", err)
+		}
+		category.UpdatedAt = updatedAt
+	}
+
+	category.Translations = unmarshalTranslations(item)
+
+	return category, nil
 } 
\ No newline at end of file
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/jhonathanssegura/ticket-events/internal/geo"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+// EventsByGeoIndex is the GSI on events keyed by geo_cell (tenant and
+// geohash cell) and sorted by the event's full geohash. Only events with
+// coordinates are in it.
+const EventsByGeoIndex = "geo_cell-index"
+
+// GetEventsNear returns the tenant's events within radiusKm of lat, lng,
+// nearest first, with DistanceKm set. categoryID optionally filters them.
+func (d *DynamoClient) GetEventsNear(ctx context.Context, lat, lng, radiusKm float64, categoryID string) ([]model.Event, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cells, err := geo.Cells(lat, lng, radiusKm)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []model.Event
+	for _, cell := range cells {
+		names := tenantAttributeNames()
+		names["#geo_cell"] = "geo_cell"
+		values := tenantAttributeValues(tenantID)
+		values[":geo_cell"] = &types.AttributeValueMemberS{Value: geoCell(tenantID, cell)}
+		filter := "#tenant_id = :tenant_id"
+		if categoryID != "" {
+			filter += " AND #category_id = :category_id"
+			names["#category_id"] = "category_id"
+			values[":category_id"] = &types.AttributeValueMemberS{Value: categoryID}
+		}
+
+		paginator := dynamodb.NewQueryPaginator(d.Client, &dynamodb.QueryInput{
+			TableName:                 aws.String(EventsTable),
+			IndexName:                 aws.String(EventsByGeoIndex),
+			KeyConditionExpression:    aws.String("#geo_cell = :geo_cell"),
+			FilterExpression:          aws.String(filter),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("error querying events near point: %w", err)
+			}
+			for _, item := range page.Items {
+				event, err := d.unmarshalEvent(item)
+				if err != nil {
+					return nil, err
+				}
+				if event.Latitude == nil || event.Longitude == nil {
+					continue
+				}
+				// Cells are coarser than the circle
+				distance := geo.DistanceKm(lat, lng, *event.Latitude, *event.Longitude)
+				if distance > radiusKm {
+					continue
+				}
+				event.DistanceKm = &distance
+				events = append(events, *event)
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return *events[i].DistanceKm < *events[j].DistanceKm
+	})
+	return events, nil
+}
+
+// setCoordinates stores the coordinates of an event or series, if any.
+func setCoordinates(item map[string]types.AttributeValue, lat, lng *float64) bool {
+	if lat == nil || lng == nil {
+		return false
+	}
+	item["latitude"] = &types.AttributeValueMemberN{Value: strconv.FormatFloat(*lat, 'f', -1, 64)}
+	item["longitude"] = &types.AttributeValueMemberN{Value: strconv.FormatFloat(*lng, 'f', -1, 64)}
+	return true
+}
+
+func unmarshalCoordinates(item map[string]types.AttributeValue) (lat, lng *float64, err error) {
+	latVal, ok := item["latitude"].(*types.AttributeValueMemberN)
+	if !ok {
+		return nil, nil, nil
+	}
+	lngVal, ok := item["longitude"].(*types.AttributeValueMemberN)
+	if !ok {
+		return nil, nil, nil
+	}
+	latitude, err := strconv.ParseFloat(latVal.Value, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid latitude: %v", err)
+	}
+	longitude, err := strconv.ParseFloat(lngVal.Value, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid longitude: %v", err)
+	}
+	return &latitude, &longitude, nil
+}
+
+// geoCell is the geo_cell key of events of tenantID in cell. The tenant is
+// part of the key so a query never reads other tenants' events.
+func geoCell(tenantID, cell string) string {
+	return tenantID + "#" + cell
+}
@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+// IdempotencyTable stores the outcome of requests sent with an
+// Idempotency-Key. Items expire through the DynamoDB TTL on expires_at.
+const IdempotencyTable = "idempotency_keys"
+
+const (
+	IdempotencyInProgress = "in_progress"
+	IdempotencyCompleted  = "completed"
+)
+
+// IdempotencyRecord is a stored Idempotency-Key. Response fields are only set
+// once the record is completed.
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+	Status      string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	LockedUntil time.Time
+	ExpiresAt   time.Time
+}
+
+// ClaimIdempotencyKey reserves key for the current tenant while the request
+// is processed. If it is already held, by a completed response or by a
+// request whose lock has not expired, the existing record is returned and
+// claimed is false.
+func (d *DynamoClient) ClaimIdempotencyKey(ctx context.Context, key, requestHash string, lock, ttl time.Duration) (existing *IdempotencyRecord, claimed bool, err error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	_, err = d.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(IdempotencyTable),
+		Item: map[string]types.AttributeValue{
+			"id":           &types.AttributeValueMemberS{Value: idempotencyID(tenantID, key)},
+			"tenant_id":    &types.AttributeValueMemberS{Value: tenantID},
+			"request_hash": &types.AttributeValueMemberS{Value: requestHash},
+			"status":       &types.AttributeValueMemberS{Value: IdempotencyInProgress},
+			"locked_until": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(lock).UnixMilli(), 10)},
+			"expires_at":   &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(ttl).Unix(), 10)},
+		},
+		// Expired items may linger until the TTL sweeper removes them, and
+		// a stale lock means the original request died mid-flight.
+		ConditionExpression: aws.String("attribute_not_exists(id) OR expires_at < :now OR (#status = :in_progress AND locked_until < :now_ms)"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now":         &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+			":now_ms":      &types.AttributeValueMemberN{Value: strconv.FormatInt(now.UnixMilli(), 10)},
+			":in_progress": &types.AttributeValueMemberS{Value: IdempotencyInProgress},
+		},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		record, err := unmarshalIdempotencyRecord(conditionErr.Item)
+		if err != nil {
+			return nil, false, err
+		}
+		return record, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error claiming idempotency key: %w", err)
+	}
+	return nil, true, nil
+}
+
+// CompleteIdempotencyKey stores the response for a claimed key so later
+// requests with the same key replay it.
+func (d *DynamoClient) CompleteIdempotencyKey(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(IdempotencyTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: idempotencyID(tenantID, key)},
+		},
+		UpdateExpression: aws.String("SET #status = :completed, status_code = :status_code, content_type = :content_type, body = :body REMOVE locked_until"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":completed":    &types.AttributeValueMemberS{Value: IdempotencyCompleted},
+			":status_code":  &types.AttributeValueMemberN{Value: strconv.Itoa(statusCode)},
+			":content_type": &types.AttributeValueMemberS{Value: contentType},
+			":body":         &types.AttributeValueMemberB{Value: body},
+		},
+		ConditionExpression: aws.String("attribute_exists(id)"),
+	})
+	if err != nil {
+		return fmt.Errorf("error completing idempotency key: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey drops an in-progress claim so the request can be
+// retried with the same key, e.g. after a server error.
+func (d *DynamoClient) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(IdempotencyTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: idempotencyID(tenantID, key)},
+		},
+		ConditionExpression: aws.String("#status = :in_progress"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":in_progress": &types.AttributeValueMemberS{Value: IdempotencyInProgress},
+		},
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if err != nil && !errors.As(err, &conditionErr) {
+		return fmt.Errorf("error releasing idempotency key: %w", err)
+	}
+	return nil
+}
+
+func idempotencyID(tenantID, key string) string {
+	return tenantID + "#" + key
+}
+
+func unmarshalIdempotencyRecord(item map[string]types.AttributeValue) (*IdempotencyRecord, error) {
+	record := &IdempotencyRecord{}
+	if idVal, ok := item["id"].(*types.AttributeValueMemberS); ok {
+		record.Key = idVal.Value
+	}
+	if hashVal, ok := item["request_hash"].(*types.AttributeValueMemberS); ok {
+		record.RequestHash = hashVal.Value
+	}
+	if statusVal, ok := item["status"].(*types.AttributeValueMemberS); ok {
+		record.Status = statusVal.Value
+	}
+	if codeVal, ok := item["status_code"].(*types.AttributeValueMemberN); ok {
+		code, err := strconv.Atoi(codeVal.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status_code: %v", err)
+		}
+		record.StatusCode = code
+	}
+	if typeVal, ok := item["content_type"].(*types.AttributeValueMemberS); ok {
+		record.ContentType = typeVal.Value
+	}
+	if bodyVal, ok := item["body"].(*types.AttributeValueMemberB); ok {
+		record.Body = bodyVal.Value
+	}
+	if lockedVal, ok := item["locked_until"].(*types.AttributeValueMemberN); ok {
+		ms, err := strconv.ParseInt(lockedVal.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid locked_until: %v", err)
+		}
+		record.LockedUntil = time.UnixMilli(ms)
+	}
+	if expiresVal, ok := item["expires_at"].(*types.AttributeValueMemberN); ok {
+		s, err := strconv.ParseInt(expiresVal.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expires_at: %v", err)
+		}
+		record.ExpiresAt = time.Unix(s, 0)
+	}
+	return record, nil
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// setImageVariants stores the URLs of the variants of an event image, if
+// any.
+func setImageVariants(item map[string]types.AttributeValue, variants map[string]string) {
+	if len(variants) == 0 {
+		return
+	}
+	value := make(map[string]types.AttributeValue, len(variants))
+	for name, url := range variants {
+		value[name] = &types.AttributeValueMemberS{Value: url}
+	}
+	item["image_variants"] = &types.AttributeValueMemberM{Value: value}
+}
+
+func unmarshalImageVariants(item map[string]types.AttributeValue) map[string]string {
+	variantsVal, ok := item["image_variants"].(*types.AttributeValueMemberM)
+	if !ok {
+		return nil
+	}
+	variants := make(map[string]string, len(variantsVal.Value))
+	for name, v := range variantsVal.Value {
+		if url, ok := v.(*types.AttributeValueMemberS); ok {
+			variants[name] = url.Value
+		}
+	}
+	return variants
+}
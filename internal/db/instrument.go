@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/jhonathanssegura/ticket-events/internal/metrics"
+	"github.com/jhonathanssegura/ticket-events/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewDynamoClient builds a DynamoClient whose calls are instrumented.
+func NewDynamoClient(cfg aws.Config) *DynamoClient {
+	return &DynamoClient{
+		Client: dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+			o.APIOptions = append(o.APIOptions, addInstrumentation)
+		}),
+	}
+}
+
+// addInstrumentation registers a middleware that records metrics and a client
+// span for every DynamoDB operation, so new calls on DynamoClient are covered
+// without extra code.
+func addInstrumentation(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("TicketEventsInstrumentation",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			operation := middleware.GetOperationName(ctx)
+			table := tableName(in.Parameters)
+			requestConsumedCapacity(in.Parameters)
+
+			ctx, span := tracing.Tracer().Start(ctx, "DynamoDB."+operation,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.DBSystemDynamoDB,
+					semconv.RPCSystemKey.String("aws-api"),
+					semconv.RPCService("DynamoDB"),
+					semconv.RPCMethod(operation),
+					semconv.AWSDynamoDBTableNames(table),
+				))
+
+			start := time.Now()
+			out, md, err := next.HandleInitialize(ctx, in)
+			capacity := consumedCapacity(out.Result)
+			metrics.ObserveDynamo(operation, table, time.Since(start), capacity, err)
+
+			span.SetAttributes(attribute.Float64("aws.dynamodb.consumed_capacity_units", capacity))
+			tracing.End(span, err)
+			return out, md, err
+		}), middleware.Before)
+}
+
+// The helpers below use reflection because every operation has its own
+// input/output struct; the field names are stable across the SDK.
+
+func tableName(params any) string {
+	if f := structField(params, "TableName"); f.IsValid() {
+		if name, ok := f.Interface().(*string); ok && name != nil {
+			return *name
+		}
+	}
+	return "none"
+}
+
+func requestConsumedCapacity(params any) {
+	f := structField(params, "ReturnConsumedCapacity")
+	if f.IsValid() && f.CanSet() && f.String() == "" {
+		f.Set(reflect.ValueOf(types.ReturnConsumedCapacityTotal))
+	}
+}
+
+func consumedCapacity(result any) float64 {
+	f := structField(result, "ConsumedCapacity")
+	if !f.IsValid() {
+		return 0
+	}
+	switch cc := f.Interface().(type) {
+	case *types.ConsumedCapacity:
+		if cc != nil {
+			return aws.ToFloat64(cc.CapacityUnits)
+		}
+	case []types.ConsumedCapacity:
+		var total float64
+		for _, c := range cc {
+			total += aws.ToFloat64(c.CapacityUnits)
+		}
+		return total
+	}
+	return 0
+}
+
+func structField(v any, name string) reflect.Value {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return reflect.Value{}
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return rv.FieldByName(name)
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// LeasesTable holds the leases that let a single replica run a background
+// job. Items expire through the DynamoDB TTL on expires_at. Leases are
+// global, not scoped to a tenant.
+const LeasesTable = "leases"
+
+// AcquireLease takes the lease called name for owner until now plus ttl, or
+// renews it if owner already holds it. It returns false while another owner
+// holds a lease that has not expired.
+func (d *DynamoClient) AcquireLease(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	_, err := d.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(LeasesTable),
+		Item: map[string]types.AttributeValue{
+			"id":         &types.AttributeValueMemberS{Value: name},
+			"owner":      &types.AttributeValueMemberS{Value: owner},
+			"expires_at": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(ttl).Unix(), 10)},
+		},
+		// Expired items may linger until the TTL sweeper removes them
+		ConditionExpression: aws.String("attribute_not_exists(id) OR #owner = :owner OR expires_at < :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#owner": "owner",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":owner": &types.AttributeValueMemberS{Value: owner},
+			":now":   &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+		},
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error acquiring lease: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,440 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+const (
+	// PricingRulesTable stores the pricing rules of each event, keyed by
+	// event ID.
+	PricingRulesTable = "pricing_rules"
+	// PriceHistoryTable holds the price changes of each event (event_id),
+	// sorted by when they happened (id).
+	PriceHistoryTable = "price_history"
+)
+
+// priceChangeKeyFormat is a fixed-width UTC time, so history keys sort in
+// time order.
+const priceChangeKeyFormat = "2006-01-02T15:04:05.000000000Z"
+
+var (
+	ErrPricingRulesNotFound = errors.New("pricing rules not found")
+	// ErrPriceChangeConflict is returned by RecordPriceChange when the
+	// current price is no longer the one the change starts from, usually
+	// because a concurrent read already recorded it.
+	ErrPriceChangeConflict = errors.New("price change already recorded")
+)
+
+// SavePricingRules stores rules, and change in the price history if not nil,
+// all or nothing.
+func (d *DynamoClient) SavePricingRules(ctx context.Context, rules model.PricingRules, change *model.PriceChange) error {
+	tenantID, err := scopedTenant(ctx, rules.TenantID)
+	if err != nil {
+		return err
+	}
+
+	timeSteps := make([]types.AttributeValue, 0, len(rules.TimeSteps))
+	for _, step := range rules.TimeSteps {
+		timeSteps = append(timeSteps, &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"hours_before": &types.AttributeValueMemberN{Value: strconv.Itoa(step.HoursBefore)},
+			"percent":      &types.AttributeValueMemberN{Value: formatFloat(step.Percent)},
+		}})
+	}
+	sellSteps := make([]types.AttributeValue, 0, len(rules.SellThroughSteps))
+	for _, step := range rules.SellThroughSteps {
+		sellSteps = append(sellSteps, &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"sold_percent": &types.AttributeValueMemberN{Value: formatFloat(step.SoldPercent)},
+			"percent":      &types.AttributeValueMemberN{Value: formatFloat(step.Percent)},
+		}})
+	}
+
+	item := map[string]types.AttributeValue{
+		"id":                 &types.AttributeValueMemberS{Value: rules.EventID.String()},
+		"tenant_id":          &types.AttributeValueMemberS{Value: tenantID},
+		"time_steps":         &types.AttributeValueMemberL{Value: timeSteps},
+		"sell_through_steps": &types.AttributeValueMemberL{Value: sellSteps},
+		"created_at":         &types.AttributeValueMemberS{Value: rules.CreatedAt.Format(time.RFC3339)},
+		"updated_at":         &types.AttributeValueMemberS{Value: rules.UpdatedAt.Format(time.RFC3339)},
+	}
+	setOptionalFloat(item, "floor", rules.Floor)
+	setOptionalFloat(item, "ceiling", rules.Ceiling)
+	setOptionalFloat(item, "current_price", rules.CurrentPrice)
+
+	items := []types.TransactWriteItem{{Put: &types.Put{
+		TableName:                 aws.String(PricingRulesTable),
+		Item:                      item,
+		ConditionExpression:       aws.String(sameTenantOrNewCondition),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+	}}}
+	if change != nil {
+		items = append(items, types.TransactWriteItem{Put: priceChangePut(tenantID, *change)})
+	}
+
+	_, err = d.Client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	if err != nil {
+		return fmt.Errorf("error saving pricing rules: %w", err)
+	}
+	return nil
+}
+
+func (d *DynamoClient) GetPricingRules(ctx context.Context, eventID string) (*model.PricingRules, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(PricingRulesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: eventID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting pricing rules: %w", err)
+	}
+	if result.Item == nil || !belongsTo(result.Item, tenantID) {
+		return nil, ErrPricingRulesNotFound
+	}
+	return unmarshalPricingRules(result.Item)
+}
+
+// GetPricingRulesByEvents returns the pricing rules of the tenant's events
+// with the given IDs, keyed by event ID. Events without rules are left out.
+func (d *DynamoClient) GetPricingRulesByEvents(ctx context.Context, eventIDs []string) (map[string]*model.PricingRules, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]map[string]types.AttributeValue, 0, len(eventIDs))
+	for _, id := range eventIDs {
+		keys = append(keys, map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		})
+	}
+
+	found := make(map[string]*model.PricingRules)
+	err = d.batchGet(ctx, PricingRulesTable, keys, func(item map[string]types.AttributeValue) error {
+		if !belongsTo(item, tenantID) {
+			return nil
+		}
+		rules, err := unmarshalPricingRules(item)
+		if err != nil {
+			return err
+		}
+		found[rules.EventID.String()] = rules
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting pricing rules: %w", err)
+	}
+	return found, nil
+}
+
+// ScanPricingRules returns the pricing rules of every tenant. It is meant
+// for the background job recording price changes, which then works within
+// each event's tenant.
+func (d *DynamoClient) ScanPricingRules(ctx context.Context) ([]model.PricingRules, error) {
+	var all []model.PricingRules
+	paginator := dynamodb.NewScanPaginator(d.Client, &dynamodb.ScanInput{
+		TableName: aws.String(PricingRulesTable),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning pricing rules: %w", err)
+		}
+		for _, item := range page.Items {
+			rules, err := unmarshalPricingRules(item)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, *rules)
+		}
+	}
+	return all, nil
+}
+
+// DeletePricingRules removes the rules of an event, recording change in the
+// price history if not nil, all or nothing.
+func (d *DynamoClient) DeletePricingRules(ctx context.Context, eventID string, change *model.PriceChange) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	items := []types.TransactWriteItem{{Delete: &types.Delete{
+		TableName: aws.String(PricingRulesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: eventID},
+		},
+		ConditionExpression:       aws.String("#tenant_id = :tenant_id"),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+	}}}
+	if change != nil {
+		items = append(items, types.TransactWriteItem{Put: priceChangePut(tenantID, *change)})
+	}
+
+	_, err = d.Client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	var canceledErr *types.TransactionCanceledException
+	if errors.As(err, &canceledErr) && len(canceledErr.CancellationReasons) > 0 &&
+		aws.ToString(canceledErr.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+		return ErrPricingRulesNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting pricing rules: %w", err)
+	}
+	return nil
+}
+
+// RecordPriceChange appends change to the price history and makes its price
+// the current one of the event's rules, provided the current price is still
+// change.PreviousPrice. Otherwise it returns ErrPriceChangeConflict and
+// records nothing, so concurrent sales record each change once.
+func (d *DynamoClient) RecordPriceChange(ctx context.Context, change model.PriceChange) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	condition := "#tenant_id = :tenant_id AND attribute_not_exists(current_price)"
+	values := tenantAttributeValues(tenantID)
+	values[":price"] = &types.AttributeValueMemberN{Value: formatFloat(change.Price)}
+	if change.PreviousPrice != nil {
+		condition = "#tenant_id = :tenant_id AND current_price = :previous"
+		values[":previous"] = &types.AttributeValueMemberN{Value: formatFloat(*change.PreviousPrice)}
+	}
+
+	_, err = d.Client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Update: &types.Update{
+				TableName: aws.String(PricingRulesTable),
+				Key: map[string]types.AttributeValue{
+					"id": &types.AttributeValueMemberS{Value: change.EventID.String()},
+				},
+				UpdateExpression:          aws.String("SET current_price = :price"),
+				ConditionExpression:       aws.String(condition),
+				ExpressionAttributeNames:  tenantAttributeNames(),
+				ExpressionAttributeValues: values,
+			}},
+			{Put: priceChangePut(tenantID, change)},
+		},
+	})
+	var canceledErr *types.TransactionCanceledException
+	if errors.As(err, &canceledErr) {
+		return ErrPriceChangeConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error recording price change: %w", err)
+	}
+	return nil
+}
+
+// ListPriceChanges returns the latest limit price changes of an event,
+// newest first.
+func (d *DynamoClient) ListPriceChanges(ctx context.Context, eventID string, limit int) ([]model.PriceChange, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.Client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(PriceHistoryTable),
+		KeyConditionExpression: aws.String("event_id = :event_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":event_id": &types.AttributeValueMemberS{Value: eventID},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing price changes: %w", err)
+	}
+
+	changes := make([]model.PriceChange, 0, len(result.Items))
+	for _, item := range result.Items {
+		if !belongsTo(item, tenantID) {
+			continue
+		}
+		change, err := unmarshalPriceChange(item)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, *change)
+	}
+	return changes, nil
+}
+
+func priceChangePut(tenantID string, change model.PriceChange) *types.Put {
+	item := map[string]types.AttributeValue{
+		"event_id":     &types.AttributeValueMemberS{Value: change.EventID.String()},
+		"id":           &types.AttributeValueMemberS{Value: change.ChangedAt.UTC().Format(priceChangeKeyFormat)},
+		"tenant_id":    &types.AttributeValueMemberS{Value: tenantID},
+		"price":        &types.AttributeValueMemberN{Value: formatFloat(change.Price)},
+		"base_price":   &types.AttributeValueMemberN{Value: formatFloat(change.BasePrice)},
+		"percent":      &types.AttributeValueMemberN{Value: formatFloat(change.Percent)},
+		"sold_percent": &types.AttributeValueMemberN{Value: formatFloat(change.SoldPercent)},
+		"reason":       &types.AttributeValueMemberS{Value: change.Reason},
+		"changed_at":   &types.AttributeValueMemberS{Value: change.ChangedAt.UTC().Format(time.RFC3339Nano)},
+	}
+	setOptionalFloat(item, "previous_price", change.PreviousPrice)
+	return &types.Put{
+		TableName:           aws.String(PriceHistoryTable),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func setOptionalFloat(item map[string]types.AttributeValue, name string, f *float64) {
+	if f != nil {
+		item[name] = &types.AttributeValueMemberN{Value: formatFloat(*f)}
+	}
+}
+
+func optionalFloat(item map[string]types.AttributeValue, name string) (*float64, error) {
+	val, ok := item[name].(*types.AttributeValueMemberN)
+	if !ok {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(val.Value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return &f, nil
+}
+
+func unmarshalPricingRules(item map[string]types.AttributeValue) (*model.PricingRules, error) {
+	rules := &model.PricingRules{
+		TimeSteps:        []model.TimeStep{},
+		SellThroughSteps: []model.SellThroughStep{},
+	}
+	var err error
+
+	if idVal, ok := item["id"].(*types.AttributeValueMemberS); ok {
+		if rules.EventID, err = uuid.Parse(idVal.Value); err != nil {
+			return nil, fmt.Errorf("invalid event ID: %v", err)
+		}
+	}
+	if tenantVal, ok := item["tenant_id"].(*types.AttributeValueMemberS); ok {
+		rules.TenantID = tenantVal.Value
+	}
+
+	if stepsVal, ok := item["time_steps"].(*types.AttributeValueMemberL); ok {
+		for _, v := range stepsVal.Value {
+			fields, ok := v.(*types.AttributeValueMemberM)
+			if !ok {
+				continue
+			}
+			var step model.TimeStep
+			if hoursVal, ok := fields.Value["hours_before"].(*types.AttributeValueMemberN); ok {
+				if step.HoursBefore, err = strconv.Atoi(hoursVal.Value); err != nil {
+					return nil, fmt.Errorf("invalid time step hours: %v", err)
+				}
+			}
+			if percentVal, ok := fields.Value["percent"].(*types.AttributeValueMemberN); ok {
+				if step.Percent, err = strconv.ParseFloat(percentVal.Value, 64); err != nil {
+					return nil, fmt.Errorf("invalid time step percent: %v", err)
+				}
+			}
+			rules.TimeSteps = append(rules.TimeSteps, step)
+		}
+	}
+	if stepsVal, ok := item["sell_through_steps"].(*types.AttributeValueMemberL); ok {
+		for _, v := range stepsVal.Value {
+			fields, ok := v.(*types.AttributeValueMemberM)
+			if !ok {
+				continue
+			}
+			var step model.SellThroughStep
+			if soldVal, ok := fields.Value["sold_percent"].(*types.AttributeValueMemberN); ok {
+				if step.SoldPercent, err = strconv.ParseFloat(soldVal.Value, 64); err != nil {
+					return nil, fmt.Errorf("invalid sell-through step: %v", err)
+				}
+			}
+			if percentVal, ok := fields.Value["percent"].(*types.AttributeValueMemberN); ok {
+				if step.Percent, err = strconv.ParseFloat(percentVal.Value, 64); err != nil {
+					return nil, fmt.Errorf("invalid sell-through step percent: %v", err)
+				}
+			}
+			rules.SellThroughSteps = append(rules.SellThroughSteps, step)
+		}
+	}
+
+	if rules.Floor, err = optionalFloat(item, "floor"); err != nil {
+		return nil, err
+	}
+	if rules.Ceiling, err = optionalFloat(item, "ceiling"); err != nil {
+		return nil, err
+	}
+	if rules.CurrentPrice, err = optionalFloat(item, "current_price"); err != nil {
+		return nil, err
+	}
+
+	times := map[string]*time.Time{
+		"created_at": &rules.CreatedAt,
+		"updated_at": &rules.UpdatedAt,
+	}
+	for name, field := range times {
+		if val, ok := item[name].(*types.AttributeValueMemberS); ok {
+			if *field, err = time.Parse(time.RFC3339, val.Value); err != nil {
+				return nil, fmt.Errorf("invalid %s time: %v", name, err)
+			}
+		}
+	}
+
+	return rules, nil
+}
+
+func unmarshalPriceChange(item map[string]types.AttributeValue) (*model.PriceChange, error) {
+	change := &model.PriceChange{}
+	var err error
+
+	if eventVal, ok := item["event_id"].(*types.AttributeValueMemberS); ok {
+		if change.EventID, err = uuid.Parse(eventVal.Value); err != nil {
+			return nil, fmt.Errorf("invalid event ID: %v", err)
+		}
+	}
+	amounts := map[string]*float64{
+		"price":        &change.Price,
+		"base_price":   &change.BasePrice,
+		"percent":      &change.Percent,
+		"sold_percent": &change.SoldPercent,
+	}
+	for name, field := range amounts {
+		if val, ok := item[name].(*types.AttributeValueMemberN); ok {
+			if *field, err = strconv.ParseFloat(val.Value, 64); err != nil {
+				return nil, fmt.Errorf("invalid %s: %v", name, err)
+			}
+		}
+	}
+	if change.PreviousPrice, err = optionalFloat(item, "previous_price"); err != nil {
+		return nil, err
+	}
+	if reasonVal, ok := item["reason"].(*types.AttributeValueMemberS); ok {
+		change.Reason = reasonVal.Value
+	}
+	if changedVal, ok := item["changed_at"].(*types.AttributeValueMemberS); ok {
+		if change.ChangedAt, err = time.Parse(time.RFC3339Nano, changedVal.Value); err != nil {
+			return nil, fmt.Errorf("invalid changed_at time: %v", err)
+		}
+	}
+
+	return change, nil
+}
@@ -0,0 +1,475 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+const (
+	// PromoCodesTable is keyed by "<tenant>#<code>", so codes are unique per
+	// tenant.
+	PromoCodesTable = "promo_codes"
+	// PromoCodesByTenantIndex is the GSI on promo_codes keyed by tenant_id
+	// and sorted by created_at.
+	PromoCodesByTenantIndex = "tenant_id-index"
+	// PromoRedemptionsTable holds, per code (code_id), a usage counter for
+	// every customer ("customer#<subject>") and a record of every redemption
+	// ("redemption#<id>"). Counters outlive the code, so a code deleted and
+	// created again keeps counting past uses per customer.
+	PromoRedemptionsTable = "promo_redemptions"
+)
+
+// redeemAttempts bounds the retries of a redemption that lost a race with
+// another redemption of the same code.
+const redeemAttempts = 3
+
+var (
+	ErrPromoCodeNotFound = errors.New("promo code not found")
+	ErrPromoCodeExists   = errors.New("promo code already exists")
+	// ErrPromoCodeUnavailable is returned by RedeemPromoCode when the code
+	// ran out of uses, was deactivated or is outside its validity window.
+	ErrPromoCodeUnavailable = errors.New("promo code not redeemable")
+	// ErrPromoCustomerLimit is returned by RedeemPromoCode when the customer
+	// already used the code as many times as allowed.
+	ErrPromoCustomerLimit = errors.New("promo code customer limit reached")
+)
+
+// CreatePromoCode stores a new code, failing with ErrPromoCodeExists if the
+// tenant already has it.
+func (d *DynamoClient) CreatePromoCode(ctx context.Context, promo model.PromoCode) error {
+	tenantID, err := scopedTenant(ctx, promo.TenantID)
+	if err != nil {
+		return err
+	}
+
+	item := map[string]types.AttributeValue{
+		"id":                    &types.AttributeValueMemberS{Value: promoKey(tenantID, promo.Code)},
+		"tenant_id":             &types.AttributeValueMemberS{Value: tenantID},
+		"code":                  &types.AttributeValueMemberS{Value: promo.Code},
+		"kind":                  &types.AttributeValueMemberS{Value: promo.Kind},
+		"value":                 &types.AttributeValueMemberN{Value: strconv.FormatFloat(promo.Value, 'f', -1, 64)},
+		"max_uses":              &types.AttributeValueMemberN{Value: strconv.Itoa(promo.MaxUses)},
+		"max_uses_per_customer": &types.AttributeValueMemberN{Value: strconv.Itoa(promo.MaxUsesPerCustomer)},
+		"uses":                  &types.AttributeValueMemberN{Value: "0"},
+		"active":                &types.AttributeValueMemberBOOL{Value: promo.Active},
+		"created_by":            &types.AttributeValueMemberS{Value: promo.CreatedBy},
+		"created_at":            &types.AttributeValueMemberS{Value: promo.CreatedAt.Format(time.RFC3339)},
+		"updated_at":            &types.AttributeValueMemberS{Value: promo.UpdatedAt.Format(time.RFC3339)},
+	}
+	if promo.EventID != nil {
+		item["event_id"] = &types.AttributeValueMemberS{Value: promo.EventID.String()}
+	}
+	if promo.CategoryID != nil {
+		item["category_id"] = &types.AttributeValueMemberS{Value: promo.CategoryID.String()}
+	}
+	setOptionalTime(item, "starts_at", promo.StartsAt)
+	setOptionalTime(item, "ends_at", promo.EndsAt)
+
+	_, err = d.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(PromoCodesTable),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return ErrPromoCodeExists
+	}
+	if err != nil {
+		return fmt.Errorf("error creating promo code: %w", err)
+	}
+	return nil
+}
+
+// UpdatePromoCode saves the editable fields of promo. Uses is left alone, so
+// redemptions made meanwhile are not lost.
+func (d *DynamoClient) UpdatePromoCode(ctx context.Context, promo model.PromoCode) error {
+	tenantID, err := scopedTenant(ctx, promo.TenantID)
+	if err != nil {
+		return err
+	}
+
+	set := "SET #value = :value, max_uses = :max_uses, max_uses_per_customer = :max_uses_per_customer, #active = :active, updated_at = :updated_at"
+	remove := ""
+	values := map[string]types.AttributeValue{
+		":value":                 &types.AttributeValueMemberN{Value: strconv.FormatFloat(promo.Value, 'f', -1, 64)},
+		":max_uses":              &types.AttributeValueMemberN{Value: strconv.Itoa(promo.MaxUses)},
+		":max_uses_per_customer": &types.AttributeValueMemberN{Value: strconv.Itoa(promo.MaxUsesPerCustomer)},
+		":active":                &types.AttributeValueMemberBOOL{Value: promo.Active},
+		":updated_at":            &types.AttributeValueMemberS{Value: promo.UpdatedAt.Format(time.RFC3339)},
+	}
+	window := map[string]*time.Time{"starts_at": promo.StartsAt, "ends_at": promo.EndsAt}
+	for _, name := range []string{"starts_at", "ends_at"} {
+		if t := window[name]; t != nil {
+			set += ", " + name + " = :" + name
+			values[":"+name] = &types.AttributeValueMemberS{Value: t.UTC().Format(time.RFC3339)}
+		} else if remove == "" {
+			remove = " REMOVE " + name
+		} else {
+			remove += ", " + name
+		}
+	}
+
+	_, err = d.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(PromoCodesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: promoKey(tenantID, promo.Code)},
+		},
+		UpdateExpression:          aws.String(set + remove),
+		ConditionExpression:       aws.String("attribute_exists(id)"),
+		ExpressionAttributeNames:  map[string]string{"#value": "value", "#active": "active"},
+		ExpressionAttributeValues: values,
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return ErrPromoCodeNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error updating promo code: %w", err)
+	}
+	return nil
+}
+
+// GetPromoCode returns a code of the request's tenant. code must be
+// normalized.
+func (d *DynamoClient) GetPromoCode(ctx context.Context, code string) (*model.PromoCode, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(PromoCodesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: promoKey(tenantID, code)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting promo code: %w", err)
+	}
+	if result.Item == nil {
+		return nil, ErrPromoCodeNotFound
+	}
+	return unmarshalPromoCode(result.Item)
+}
+
+// ListPromoCodes lists the tenant's codes, newest first. With eventID set,
+// only the codes of that event are returned.
+func (d *DynamoClient) ListPromoCodes(ctx context.Context, eventID string) ([]model.PromoCode, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(PromoCodesTable),
+		IndexName:                 aws.String(PromoCodesByTenantIndex),
+		KeyConditionExpression:    aws.String("#tenant_id = :tenant_id"),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+		ScanIndexForward:          aws.Bool(false),
+	}
+	if eventID != "" {
+		input.FilterExpression = aws.String("event_id = :event_id")
+		input.ExpressionAttributeValues[":event_id"] = &types.AttributeValueMemberS{Value: eventID}
+	}
+
+	var promos []model.PromoCode
+	paginator := dynamodb.NewQueryPaginator(d.Client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing promo codes: %w", err)
+		}
+		for _, item := range page.Items {
+			promo, err := unmarshalPromoCode(item)
+			if err != nil {
+				return nil, err
+			}
+			promos = append(promos, *promo)
+		}
+	}
+	return promos, nil
+}
+
+func (d *DynamoClient) DeletePromoCode(ctx context.Context, code string) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(PromoCodesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: promoKey(tenantID, code)},
+		},
+		ConditionExpression: aws.String("attribute_exists(id)"),
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return ErrPromoCodeNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting promo code: %w", err)
+	}
+	return nil
+}
+
+// GetCustomerUses returns how many times customer redeemed code.
+func (d *DynamoClient) GetCustomerUses(ctx context.Context, code, customer string) (int, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := d.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(PromoRedemptionsTable),
+		Key: map[string]types.AttributeValue{
+			"code_id": &types.AttributeValueMemberS{Value: promoKey(tenantID, code)},
+			"id":      &types.AttributeValueMemberS{Value: customerKey(customer)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error getting promo code uses: %w", err)
+	}
+	usesVal, ok := result.Item["uses"].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, nil
+	}
+	uses, err := strconv.Atoi(usesVal.Value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid promo code uses: %v", err)
+	}
+	return uses, nil
+}
+
+// RedeemPromoCode records redemption and counts it against the global and
+// the per-customer limits of promo, all or nothing. The limits, the active
+// flag and the validity window are checked against the stored code, so
+// concurrent redemptions never exceed them. The per-customer limit lives on
+// the code but is counted on the customer's item, so the limit checked there
+// must still be the stored one; if the code was edited meanwhile, the
+// redemption is retried with the new limit.
+func (d *DynamoClient) RedeemPromoCode(ctx context.Context, promo model.PromoCode, redemption model.Redemption) error {
+	tenantID, err := scopedTenant(ctx, promo.TenantID)
+	if err != nil {
+		return err
+	}
+
+	codeID := promoKey(tenantID, promo.Code)
+	one := &types.AttributeValueMemberN{Value: "1"}
+	zero := &types.AttributeValueMemberN{Value: "0"}
+	customerLimit := promo.MaxUsesPerCustomer
+
+	redemptionItems := func(customerLimit int) []types.TransactWriteItem {
+		limit := &types.AttributeValueMemberN{Value: strconv.Itoa(customerLimit)}
+		customer := &types.Update{
+			TableName: aws.String(PromoRedemptionsTable),
+			Key: map[string]types.AttributeValue{
+				"code_id": &types.AttributeValueMemberS{Value: codeID},
+				"id":      &types.AttributeValueMemberS{Value: customerKey(redemption.Customer)},
+			},
+			UpdateExpression:         aws.String("SET #uses = if_not_exists(#uses, :zero) + :one, tenant_id = :tenant_id"),
+			ExpressionAttributeNames: map[string]string{"#uses": "uses"},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":one":       one,
+				":zero":      zero,
+				":tenant_id": &types.AttributeValueMemberS{Value: tenantID},
+			},
+		}
+		if customerLimit > 0 {
+			customer.ConditionExpression = aws.String("attribute_not_exists(#uses) OR #uses < :max_uses_per_customer")
+			customer.ExpressionAttributeValues[":max_uses_per_customer"] = limit
+		}
+
+		return []types.TransactWriteItem{
+			{Update: &types.Update{
+				TableName: aws.String(PromoCodesTable),
+				Key: map[string]types.AttributeValue{
+					"id": &types.AttributeValueMemberS{Value: codeID},
+				},
+				UpdateExpression: aws.String("SET #uses = #uses + :one"),
+				// Times are stored as RFC 3339 in UTC, which sort as strings
+				ConditionExpression: aws.String("#active = :true AND (max_uses = :zero OR #uses < max_uses)" +
+					" AND max_uses_per_customer = :max_uses_per_customer" +
+					" AND (attribute_not_exists(starts_at) OR starts_at <= :now)" +
+					" AND (attribute_not_exists(ends_at) OR ends_at > :now)"),
+				ExpressionAttributeNames: map[string]string{"#uses": "uses", "#active": "active"},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":one":                   one,
+					":zero":                  zero,
+					":true":                  &types.AttributeValueMemberBOOL{Value: true},
+					":max_uses_per_customer": limit,
+					":now":                   &types.AttributeValueMemberS{Value: redemption.CreatedAt.UTC().Format(time.RFC3339)},
+				},
+				ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+			}},
+			{Update: customer},
+			{Put: &types.Put{
+				TableName: aws.String(PromoRedemptionsTable),
+				Item: map[string]types.AttributeValue{
+					"code_id":    &types.AttributeValueMemberS{Value: codeID},
+					"id":         &types.AttributeValueMemberS{Value: redemptionKey(redemption.ID.String())},
+					"tenant_id":  &types.AttributeValueMemberS{Value: tenantID},
+					"code":       &types.AttributeValueMemberS{Value: promo.Code},
+					"event_id":   &types.AttributeValueMemberS{Value: redemption.EventID.String()},
+					"customer":   &types.AttributeValueMemberS{Value: redemption.Customer},
+					"quantity":   &types.AttributeValueMemberN{Value: strconv.Itoa(redemption.Quantity)},
+					"discount":   &types.AttributeValueMemberN{Value: strconv.FormatFloat(redemption.Discount, 'f', -1, 64)},
+					"total":      &types.AttributeValueMemberN{Value: strconv.FormatFloat(redemption.Total, 'f', -1, 64)},
+					"created_at": &types.AttributeValueMemberS{Value: redemption.CreatedAt.Format(time.RFC3339)},
+				},
+				ConditionExpression: aws.String("attribute_not_exists(id)"),
+			}},
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		_, err = d.Client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: redemptionItems(customerLimit)})
+		var canceledErr *types.TransactionCanceledException
+		if !errors.As(err, &canceledErr) {
+			break
+		}
+		// Reasons are in the order of the items: the code, the customer's
+		// counter and the redemption
+		reasons := canceledErr.CancellationReasons
+		failed := func(i int) bool {
+			return i < len(reasons) && aws.ToString(reasons[i].Code) == "ConditionalCheckFailed"
+		}
+		retry := false
+		switch {
+		case failed(0):
+			// A stale limit may also be why the customer's check failed
+			stored, changed := changedCustomerLimit(reasons[0].Item, customerLimit)
+			if !changed {
+				return ErrPromoCodeUnavailable
+			}
+			customerLimit = stored
+			retry = true
+		case failed(1):
+			return ErrPromoCustomerLimit
+		}
+		for _, reason := range reasons {
+			// Another redemption of the same code was writing the counter
+			if aws.ToString(reason.Code) == "TransactionConflict" {
+				retry = true
+			}
+		}
+		if !retry || attempt == redeemAttempts {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("error redeeming promo code: %w", err)
+	}
+	return nil
+}
+
+// changedCustomerLimit returns the per-customer limit stored in item, the
+// promo code as it was when a redemption failed, and whether it differs
+// from the limit the redemption checked.
+func changedCustomerLimit(item map[string]types.AttributeValue, checked int) (int, bool) {
+	limitVal, ok := item["max_uses_per_customer"].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, false
+	}
+	limit, err := strconv.Atoi(limitVal.Value)
+	if err != nil || limit == checked {
+		return 0, false
+	}
+	return limit, true
+}
+
+func promoKey(tenantID, code string) string {
+	return tenantID + "#" + code
+}
+
+func customerKey(subject string) string {
+	return "customer#" + subject
+}
+
+func redemptionKey(id string) string {
+	return "redemption#" + id
+}
+
+func unmarshalPromoCode(item map[string]types.AttributeValue) (*model.PromoCode, error) {
+	promo := &model.PromoCode{}
+	var err error
+
+	if codeVal, ok := item["code"].(*types.AttributeValueMemberS); ok {
+		promo.Code = codeVal.Value
+	}
+	if tenantVal, ok := item["tenant_id"].(*types.AttributeValueMemberS); ok {
+		promo.TenantID = tenantVal.Value
+	}
+	if kindVal, ok := item["kind"].(*types.AttributeValueMemberS); ok {
+		promo.Kind = kindVal.Value
+	}
+	if valueVal, ok := item["value"].(*types.AttributeValueMemberN); ok {
+		if promo.Value, err = strconv.ParseFloat(valueVal.Value, 64); err != nil {
+			return nil, fmt.Errorf("invalid promo code value: %v", err)
+		}
+	}
+	ids := map[string]**uuid.UUID{
+		"event_id":    &promo.EventID,
+		"category_id": &promo.CategoryID,
+	}
+	for name, field := range ids {
+		if val, ok := item[name].(*types.AttributeValueMemberS); ok {
+			id, err := uuid.Parse(val.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %v", name, err)
+			}
+			*field = &id
+		}
+	}
+	counts := map[string]*int{
+		"max_uses":              &promo.MaxUses,
+		"max_uses_per_customer": &promo.MaxUsesPerCustomer,
+		"uses":                  &promo.Uses,
+	}
+	for name, field := range counts {
+		if val, ok := item[name].(*types.AttributeValueMemberN); ok {
+			if *field, err = strconv.Atoi(val.Value); err != nil {
+				return nil, fmt.Errorf("invalid %s: %v", name, err)
+			}
+		}
+	}
+	if activeVal, ok := item["active"].(*types.AttributeValueMemberBOOL); ok {
+		promo.Active = activeVal.Value
+	}
+	if createdByVal, ok := item["created_by"].(*types.AttributeValueMemberS); ok {
+		promo.CreatedBy = createdByVal.Value
+	}
+
+	if promo.StartsAt, err = optionalTime(item, "starts_at"); err != nil {
+		return nil, err
+	}
+	if promo.EndsAt, err = optionalTime(item, "ends_at"); err != nil {
+		return nil, err
+	}
+	times := map[string]*time.Time{
+		"created_at": &promo.CreatedAt,
+		"updated_at": &promo.UpdatedAt,
+	}
+	for name, field := range times {
+		if val, ok := item[name].(*types.AttributeValueMemberS); ok {
+			if *field, err = time.Parse(time.RFC3339, val.Value); err != nil {
+				return nil, fmt.Errorf("invalid %s time: %v", name, err)
+			}
+		}
+	}
+
+	return promo, nil
+}
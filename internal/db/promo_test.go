@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+// fakeTransactions answers TransactWriteItems requests with the next of
+// outcomes, recording the per-customer limit each one checked. An outcome
+// lists the cancellation reason of every item, or is nil for success.
+type fakeTransactions struct {
+	outcomes [][]map[string]any
+	limits   []string
+}
+
+func (f *fakeTransactions) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TransactItems []struct {
+			Update *struct {
+				ExpressionAttributeValues map[string]map[string]any
+			}
+		}
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit, _ := req.TransactItems[0].Update.ExpressionAttributeValues[":max_uses_per_customer"]["N"].(string)
+	f.limits = append(f.limits, limit)
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	outcome := f.outcomes[0]
+	f.outcomes = f.outcomes[1:]
+	if outcome == nil {
+		w.Write([]byte(`{}`))
+		return
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]any{
+		"__type":              "com.amazonaws.dynamodb.v20120810#TransactionCanceledException",
+		"message":             "Transaction cancelled",
+		"CancellationReasons": outcome,
+	})
+}
+
+func TestRedeemPromoCodeCustomerLimit(t *testing.T) {
+	none := map[string]any{"Code": "None"}
+	failed := map[string]any{"Code": "ConditionalCheckFailed"}
+	conflict := map[string]any{"Code": "TransactionConflict"}
+	codeFailed := func(storedLimit string) map[string]any {
+		return map[string]any{"Code": "ConditionalCheckFailed", "Item": map[string]any{
+			"max_uses_per_customer": map[string]string{"N": storedLimit},
+		}}
+	}
+
+	tests := []struct {
+		name       string
+		outcomes   [][]map[string]any
+		wantLimits []string
+		wantErr    error
+		// wantFailure is set when some other error is expected
+		wantFailure bool
+	}{
+		{
+			name:       "redeemed",
+			outcomes:   [][]map[string]any{nil},
+			wantLimits: []string{"2"},
+		},
+		{
+			name:       "customer limit reached",
+			outcomes:   [][]map[string]any{{none, failed, none}},
+			wantLimits: []string{"2"},
+			wantErr:    ErrPromoCustomerLimit,
+		},
+		{
+			name:       "code unavailable",
+			outcomes:   [][]map[string]any{{codeFailed("2"), none, none}},
+			wantLimits: []string{"2"},
+			wantErr:    ErrPromoCodeUnavailable,
+		},
+		{
+			name:       "limit lowered meanwhile and reached",
+			outcomes:   [][]map[string]any{{codeFailed("1"), none, none}, {none, failed, none}},
+			wantLimits: []string{"2", "1"},
+			wantErr:    ErrPromoCustomerLimit,
+		},
+		{
+			name:       "limit raised meanwhile",
+			outcomes:   [][]map[string]any{{codeFailed("5"), failed, none}, nil},
+			wantLimits: []string{"2", "5"},
+		},
+		{
+			name:        "conflicts with other redemptions",
+			outcomes:    [][]map[string]any{{conflict, none, none}, {conflict, none, none}, {conflict, none, none}},
+			wantLimits:  []string{"2", "2", "2"},
+			wantFailure: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeTransactions{outcomes: tt.outcomes}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			ctx := tenant.WithTenant(context.Background(), "acme")
+			promo := model.PromoCode{Code: "SUMMER", MaxUsesPerCustomer: 2}
+			redemption := model.Redemption{ID: uuid.New(), EventID: uuid.New(), Customer: "user-1", Quantity: 1, CreatedAt: time.Now()}
+			err := newTestClient(server.URL).RedeemPromoCode(ctx, promo, redemption)
+
+			switch {
+			case tt.wantFailure:
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(fake.limits) != len(tt.wantLimits) {
+				t.Fatalf("checked limits %v, want %v", fake.limits, tt.wantLimits)
+			}
+			for i := range fake.limits {
+				if fake.limits[i] != tt.wantLimits[i] {
+					t.Fatalf("checked limits %v, want %v", fake.limits, tt.wantLimits)
+				}
+			}
+		})
+	}
+}
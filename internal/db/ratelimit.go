@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// RateLimitsTable holds token buckets shared by all replicas. Items expire
+// through the DynamoDB TTL on expires_at. Bucket keys embed the tenant, so
+// the table is not scoped through the request context.
+const RateLimitsTable = "rate_limits"
+
+// ErrRateLimitConflict is returned when another writer updated the bucket
+// since it was read.
+var ErrRateLimitConflict = errors.New("rate limit bucket modified concurrently")
+
+type RateLimitBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// GetRateLimitBucket returns the stored bucket for key, or nil if none.
+func (d *DynamoClient) GetRateLimitBucket(ctx context.Context, key string) (*RateLimitBucket, error) {
+	result, err := d.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(RateLimitsTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: key},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting rate limit bucket: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	b := &RateLimitBucket{}
+	if tokensVal, ok := result.Item["tokens"].(*types.AttributeValueMemberN); ok {
+		if b.Tokens, err = strconv.ParseFloat(tokensVal.Value, 64); err != nil {
+			return nil, fmt.Errorf("invalid tokens: %v", err)
+		}
+	}
+	if updatedVal, ok := result.Item["updated_at"].(*types.AttributeValueMemberN); ok {
+		nanos, err := strconv.ParseInt(updatedVal.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid updated_at: %v", err)
+		}
+		b.UpdatedAt = time.Unix(0, nanos)
+	}
+	return b, nil
+}
+
+// PutRateLimitBucket stores b for key if the stored bucket still matches
+// prev (nil meaning absent), otherwise returns ErrRateLimitConflict.
+func (d *DynamoClient) PutRateLimitBucket(ctx context.Context, key string, b RateLimitBucket, prev *RateLimitBucket) error {
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(RateLimitsTable),
+		Item: map[string]types.AttributeValue{
+			"id":         &types.AttributeValueMemberS{Value: key},
+			"tokens":     &types.AttributeValueMemberN{Value: strconv.FormatFloat(b.Tokens, 'f', -1, 64)},
+			"updated_at": &types.AttributeValueMemberN{Value: strconv.FormatInt(b.UpdatedAt.UnixNano(), 10)},
+			"expires_at": &types.AttributeValueMemberN{Value: strconv.FormatInt(b.ExpiresAt.Unix(), 10)},
+		},
+	}
+	if prev == nil {
+		input.ConditionExpression = aws.String("attribute_not_exists(id)")
+	} else {
+		input.ConditionExpression = aws.String("updated_at = :prev")
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":prev": &types.AttributeValueMemberN{Value: strconv.FormatInt(prev.UpdatedAt.UnixNano(), 10)},
+		}
+	}
+
+	_, err := d.Client.PutItem(ctx, input)
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return ErrRateLimitConflict
+	}
+	if err != nil {
+		return fmt.Errorf("error saving rate limit bucket: %w", err)
+	}
+	return nil
+}
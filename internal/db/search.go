@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+// EventIndexer is told about every event saved or deleted through the
+// client, to keep a search index in sync.
+type EventIndexer interface {
+	Put(event model.Event)
+	Remove(tenantID, eventID string)
+}
+
+// SetEventIndexer makes the client report event writes to indexer.
+func (d *DynamoClient) SetEventIndexer(indexer EventIndexer) {
+	d.indexer = indexer
+}
+
+func (d *DynamoClient) indexEvent(tenantID string, event model.Event) {
+	if d.indexer != nil {
+		event.TenantID = tenantID
+		d.indexer.Put(event)
+	}
+}
+
+func (d *DynamoClient) unindexEvent(tenantID, eventID string) {
+	if d.indexer != nil {
+		d.indexer.Remove(tenantID, eventID)
+	}
+}
+
+// ScanEvents returns the events of every tenant. It is meant for rebuilding
+// the search index.
+func (d *DynamoClient) ScanEvents(ctx context.Context) ([]model.Event, error) {
+	var events []model.Event
+	paginator := dynamodb.NewScanPaginator(d.Client, &dynamodb.ScanInput{
+		TableName: aws.String(EventsTable),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning events: %w", err)
+		}
+		for _, item := range page.Items {
+			event, err := d.unmarshalEvent(item)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, *event)
+		}
+	}
+	return events, nil
+}
+
+// GetEventsByIDs returns the tenant's events with the given IDs, in the same
+// order. IDs that do not exist or belong to another tenant are skipped.
+func (d *DynamoClient) GetEventsByIDs(ctx context.Context, eventIDs []string) ([]model.Event, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]map[string]types.AttributeValue, 0, len(eventIDs))
+	for _, id := range eventIDs {
+		keys = append(keys, map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		})
+	}
+
+	found := make(map[string]model.Event, len(eventIDs))
+	err = d.batchGet(ctx, EventsTable, keys, func(item map[string]types.AttributeValue) error {
+		if !belongsTo(item, tenantID) {
+			return nil
+		}
+		event, err := d.unmarshalEvent(item)
+		if err != nil {
+			return err
+		}
+		found[event.ID.String()] = *event
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting events: %w", err)
+	}
+
+	events := make([]model.Event, 0, len(found))
+	for _, id := range eventIDs {
+		if event, ok := found[id]; ok {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
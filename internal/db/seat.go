@@ -0,0 +1,618 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+const (
+	// SeatMapsTable stores the seat map of each event, keyed by event ID.
+	SeatMapsTable = "seat_maps"
+	// EventSeatsTable holds, per event (event_id), an item for every seat
+	// that is held or sold ("seat#<seat id>") and one for every hold
+	// ("hold#<hold id>"), plus a counter of sold seats ("count#sold"). Seats
+	// without an item are available. Held items expire through the DynamoDB
+	// TTL on expires_at.
+	EventSeatsTable = "event_seats"
+)
+
+var (
+	ErrSeatMapNotFound = errors.New("seat map not found")
+	ErrHoldNotFound    = errors.New("seat hold not found")
+	// ErrHoldNotActive is returned when confirming or releasing a hold that
+	// expired, was released or was already confirmed.
+	ErrHoldNotActive = errors.New("seat hold not active")
+)
+
+// SeatsUnavailableError is returned by HoldSeats when some of the seats are
+// already held or sold. No seat is held then.
+type SeatsUnavailableError struct {
+	Seats []string
+}
+
+func (e *SeatsUnavailableError) Error() string {
+	return "seats unavailable: " + strings.Join(e.Seats, ", ")
+}
+
+func (d *DynamoClient) SaveSeatMap(ctx context.Context, seatMap model.SeatMap) error {
+	tenantID, err := scopedTenant(ctx, seatMap.TenantID)
+	if err != nil {
+		return err
+	}
+
+	zones := make([]types.AttributeValue, 0, len(seatMap.Zones))
+	for _, zone := range seatMap.Zones {
+		zones = append(zones, &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"code":  &types.AttributeValueMemberS{Value: zone.Code},
+			"name":  &types.AttributeValueMemberS{Value: zone.Name},
+			"price": &types.AttributeValueMemberN{Value: strconv.FormatFloat(zone.Price, 'f', -1, 64)},
+		}})
+	}
+
+	sections := make([]types.AttributeValue, 0, len(seatMap.Sections))
+	for _, section := range seatMap.Sections {
+		rows := make([]types.AttributeValue, 0, len(section.Rows))
+		for _, row := range section.Rows {
+			value := map[string]types.AttributeValue{
+				"name":  &types.AttributeValueMemberS{Value: row.Name},
+				"seats": &types.AttributeValueMemberN{Value: strconv.Itoa(row.Seats)},
+			}
+			if row.Zone != "" {
+				value["zone"] = &types.AttributeValueMemberS{Value: row.Zone}
+			}
+			// Number sets cannot be empty
+			if len(row.Accessible) > 0 {
+				numbers := make([]string, len(row.Accessible))
+				for i, n := range row.Accessible {
+					numbers[i] = strconv.Itoa(n)
+				}
+				value["accessible"] = &types.AttributeValueMemberNS{Value: numbers}
+			}
+			rows = append(rows, &types.AttributeValueMemberM{Value: value})
+		}
+		sections = append(sections, &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"name": &types.AttributeValueMemberS{Value: section.Name},
+			"zone": &types.AttributeValueMemberS{Value: section.Zone},
+			"rows": &types.AttributeValueMemberL{Value: rows},
+		}})
+	}
+
+	_, err = d.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(SeatMapsTable),
+		Item: map[string]types.AttributeValue{
+			"id":         &types.AttributeValueMemberS{Value: seatMap.EventID.String()},
+			"tenant_id":  &types.AttributeValueMemberS{Value: tenantID},
+			"zones":      &types.AttributeValueMemberL{Value: zones},
+			"sections":   &types.AttributeValueMemberL{Value: sections},
+			"created_at": &types.AttributeValueMemberS{Value: seatMap.CreatedAt.Format(time.RFC3339)},
+			"updated_at": &types.AttributeValueMemberS{Value: seatMap.UpdatedAt.Format(time.RFC3339)},
+		},
+		ConditionExpression:       aws.String(sameTenantOrNewCondition),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+	})
+	if err != nil {
+		return fmt.Errorf("error saving seat map: %w", err)
+	}
+	return nil
+}
+
+func (d *DynamoClient) GetSeatMap(ctx context.Context, eventID string) (*model.SeatMap, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(SeatMapsTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: eventID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting seat map: %w", err)
+	}
+	if result.Item == nil || !belongsTo(result.Item, tenantID) {
+		return nil, ErrSeatMapNotFound
+	}
+	return unmarshalSeatMap(result.Item)
+}
+
+// GetTakenSeats returns the status (held or sold) of the seats of an event
+// that are not available, keyed by seat ID. With section set, only the seats
+// of that section are returned. Lapsed holds count as available.
+func (d *DynamoClient) GetTakenSeats(ctx context.Context, eventID, section string, now time.Time) (map[string]string, error) {
+	if _, err := tenant.Require(ctx); err != nil {
+		return nil, err
+	}
+
+	prefix := seatKey("")
+	if section != "" {
+		prefix = seatKey(section + ":")
+	}
+
+	taken := make(map[string]string)
+	paginator := dynamodb.NewQueryPaginator(d.Client, &dynamodb.QueryInput{
+		TableName:              aws.String(EventSeatsTable),
+		KeyConditionExpression: aws.String("event_id = :event_id AND begins_with(id, :prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":event_id": &types.AttributeValueMemberS{Value: eventID},
+			":prefix":   &types.AttributeValueMemberS{Value: prefix},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting taken seats: %w", err)
+		}
+		for _, item := range page.Items {
+			idVal, ok := item["id"].(*types.AttributeValueMemberS)
+			if !ok {
+				continue
+			}
+			statusVal, ok := item["status"].(*types.AttributeValueMemberS)
+			if !ok {
+				continue
+			}
+			// Lapsed holds linger until the TTL sweeper removes them
+			if statusVal.Value == model.SeatHeld && expired(item, now) {
+				continue
+			}
+			taken[strings.TrimPrefix(idVal.Value, seatKey(""))] = statusVal.Value
+		}
+	}
+	return taken, nil
+}
+
+// GetSoldCount returns how many seats of an event are sold, as counted by
+// ConfirmHold.
+func (d *DynamoClient) GetSoldCount(ctx context.Context, eventID string) (int, error) {
+	counts, err := d.GetSoldCounts(ctx, []string{eventID})
+	if err != nil {
+		return 0, err
+	}
+	return counts[eventID], nil
+}
+
+// GetSoldCounts returns how many seats of each event are sold. Events
+// without sales are left out.
+func (d *DynamoClient) GetSoldCounts(ctx context.Context, eventIDs []string) (map[string]int, error) {
+	if _, err := tenant.Require(ctx); err != nil {
+		return nil, err
+	}
+
+	keys := make([]map[string]types.AttributeValue, 0, len(eventIDs))
+	for _, id := range eventIDs {
+		keys = append(keys, map[string]types.AttributeValue{
+			"event_id": &types.AttributeValueMemberS{Value: id},
+			"id":       &types.AttributeValueMemberS{Value: soldCountKey},
+		})
+	}
+
+	counts := make(map[string]int, len(eventIDs))
+	err := d.batchGet(ctx, EventSeatsTable, keys, func(item map[string]types.AttributeValue) error {
+		eventVal, ok := item["event_id"].(*types.AttributeValueMemberS)
+		if !ok {
+			return nil
+		}
+		soldVal, ok := item["sold"].(*types.AttributeValueMemberN)
+		if !ok {
+			return nil
+		}
+		sold, err := strconv.Atoi(soldVal.Value)
+		if err != nil {
+			return fmt.Errorf("invalid sold count: %v", err)
+		}
+		counts[eventVal.Value] = sold
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting sold seat counts: %w", err)
+	}
+	return counts, nil
+}
+
+// HoldSeats stores hold and marks its seats as held, all or nothing. Seats
+// held by a lapsed hold are taken over. If any seat is held or sold it
+// returns a *SeatsUnavailableError listing them.
+func (d *DynamoClient) HoldSeats(ctx context.Context, hold model.SeatHold) error {
+	tenantID, err := scopedTenant(ctx, hold.TenantID)
+	if err != nil {
+		return err
+	}
+	if hold.ExpiresAt == nil {
+		return errors.New("seat hold without expiry")
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	expiresAt := strconv.FormatInt(hold.ExpiresAt.Unix(), 10)
+	eventID := hold.EventID.String()
+
+	items := make([]types.TransactWriteItem, 0, len(hold.Seats)+1)
+	for _, seatID := range hold.Seats {
+		items = append(items, types.TransactWriteItem{Put: &types.Put{
+			TableName: aws.String(EventSeatsTable),
+			Item: map[string]types.AttributeValue{
+				"event_id":   &types.AttributeValueMemberS{Value: eventID},
+				"id":         &types.AttributeValueMemberS{Value: seatKey(seatID)},
+				"tenant_id":  &types.AttributeValueMemberS{Value: tenantID},
+				"status":     &types.AttributeValueMemberS{Value: model.SeatHeld},
+				"hold_id":    &types.AttributeValueMemberS{Value: hold.ID.String()},
+				"expires_at": &types.AttributeValueMemberN{Value: expiresAt},
+			},
+			ConditionExpression: aws.String("attribute_not_exists(id) OR (#status = :held AND expires_at <= :now)"),
+			ExpressionAttributeNames: map[string]string{
+				"#status": "status",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":held": &types.AttributeValueMemberS{Value: model.SeatHeld},
+				":now":  &types.AttributeValueMemberN{Value: now},
+			},
+		}})
+	}
+	items = append(items, types.TransactWriteItem{Put: &types.Put{
+		TableName: aws.String(EventSeatsTable),
+		Item: map[string]types.AttributeValue{
+			"event_id":   &types.AttributeValueMemberS{Value: eventID},
+			"id":         &types.AttributeValueMemberS{Value: holdKey(hold.ID.String())},
+			"tenant_id":  &types.AttributeValueMemberS{Value: tenantID},
+			"holder":     &types.AttributeValueMemberS{Value: hold.Holder},
+			"seats":      &types.AttributeValueMemberSS{Value: hold.Seats},
+			"status":     &types.AttributeValueMemberS{Value: model.SeatHeld},
+			"expires_at": &types.AttributeValueMemberN{Value: expiresAt},
+			"created_at": &types.AttributeValueMemberS{Value: hold.CreatedAt.Format(time.RFC3339)},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	}})
+
+	_, err = d.Client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	var canceledErr *types.TransactionCanceledException
+	if errors.As(err, &canceledErr) {
+		// Reasons are in the order of the items; a conflict means another
+		// transaction was writing the same seat at the same time
+		unavailable := &SeatsUnavailableError{}
+		for i, reason := range canceledErr.CancellationReasons {
+			code := aws.ToString(reason.Code)
+			if i < len(hold.Seats) && (code == "ConditionalCheckFailed" || code == "TransactionConflict") {
+				unavailable.Seats = append(unavailable.Seats, hold.Seats[i])
+			}
+		}
+		if len(unavailable.Seats) > 0 {
+			return unavailable
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("error holding seats: %w", err)
+	}
+	return nil
+}
+
+func (d *DynamoClient) GetHold(ctx context.Context, eventID, holdID string) (*model.SeatHold, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(EventSeatsTable),
+		Key: map[string]types.AttributeValue{
+			"event_id": &types.AttributeValueMemberS{Value: eventID},
+			"id":       &types.AttributeValueMemberS{Value: holdKey(holdID)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting seat hold: %w", err)
+	}
+	if result.Item == nil || !belongsTo(result.Item, tenantID) {
+		return nil, ErrHoldNotFound
+	}
+	return unmarshalHold(result.Item)
+}
+
+// ConfirmHold turns the seats of an active hold into sold seats and adds them
+// to the event's sold count, all or nothing. It returns ErrHoldNotActive if the hold lapsed or is no longer
+// held.
+func (d *DynamoClient) ConfirmHold(ctx context.Context, hold model.SeatHold) error {
+	if _, err := scopedTenant(ctx, hold.TenantID); err != nil {
+		return err
+	}
+
+	eventID := hold.EventID.String()
+	values := map[string]types.AttributeValue{
+		":held":    &types.AttributeValueMemberS{Value: model.SeatHeld},
+		":sold":    &types.AttributeValueMemberS{Value: model.SeatSold},
+		":hold_id": &types.AttributeValueMemberS{Value: hold.ID.String()},
+		":now":     &types.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Unix(), 10)},
+	}
+
+	items := make([]types.TransactWriteItem, 0, len(hold.Seats)+1)
+	for _, seatID := range hold.Seats {
+		items = append(items, types.TransactWriteItem{Update: &types.Update{
+			TableName: aws.String(EventSeatsTable),
+			Key: map[string]types.AttributeValue{
+				"event_id": &types.AttributeValueMemberS{Value: eventID},
+				"id":       &types.AttributeValueMemberS{Value: seatKey(seatID)},
+			},
+			UpdateExpression:          aws.String("SET #status = :sold REMOVE expires_at"),
+			ConditionExpression:       aws.String("hold_id = :hold_id AND #status = :held AND expires_at > :now"),
+			ExpressionAttributeNames:  map[string]string{"#status": "status"},
+			ExpressionAttributeValues: values,
+		}})
+	}
+	items = append(items, types.TransactWriteItem{Update: &types.Update{
+		TableName: aws.String(EventSeatsTable),
+		Key: map[string]types.AttributeValue{
+			"event_id": &types.AttributeValueMemberS{Value: eventID},
+			"id":       &types.AttributeValueMemberS{Value: holdKey(hold.ID.String())},
+		},
+		UpdateExpression:         aws.String("SET #status = :sold REMOVE expires_at"),
+		ConditionExpression:      aws.String("#status = :held AND expires_at > :now"),
+		ExpressionAttributeNames: map[string]string{"#status": "status"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":held": values[":held"],
+			":sold": values[":sold"],
+			":now":  values[":now"],
+		},
+	}})
+
+	items = append(items, types.TransactWriteItem{Update: &types.Update{
+		TableName: aws.String(EventSeatsTable),
+		Key: map[string]types.AttributeValue{
+			"event_id": &types.AttributeValueMemberS{Value: eventID},
+			"id":       &types.AttributeValueMemberS{Value: soldCountKey},
+		},
+		UpdateExpression: aws.String("ADD sold :seats"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":seats": &types.AttributeValueMemberN{Value: strconv.Itoa(len(hold.Seats))},
+		},
+	}})
+
+	_, err := d.Client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	var canceledErr *types.TransactionCanceledException
+	if errors.As(err, &canceledErr) {
+		return ErrHoldNotActive
+	}
+	if err != nil {
+		return fmt.Errorf("error confirming seat hold: %w", err)
+	}
+	return nil
+}
+
+// ReleaseHold drops a hold that was not confirmed and frees the seats it
+// still holds. It returns ErrHoldNotActive if the hold was confirmed.
+func (d *DynamoClient) ReleaseHold(ctx context.Context, hold model.SeatHold) error {
+	if _, err := scopedTenant(ctx, hold.TenantID); err != nil {
+		return err
+	}
+
+	eventID := hold.EventID.String()
+	_, err := d.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(EventSeatsTable),
+		Key: map[string]types.AttributeValue{
+			"event_id": &types.AttributeValueMemberS{Value: eventID},
+			"id":       &types.AttributeValueMemberS{Value: holdKey(hold.ID.String())},
+		},
+		ConditionExpression:      aws.String("#status = :held"),
+		ExpressionAttributeNames: map[string]string{"#status": "status"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":held": &types.AttributeValueMemberS{Value: model.SeatHeld},
+		},
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return ErrHoldNotActive
+	}
+	if err != nil {
+		return fmt.Errorf("error releasing seat hold: %w", err)
+	}
+
+	// A lapsed hold may have lost some seats to another buyer already
+	for _, seatID := range hold.Seats {
+		_, err := d.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(EventSeatsTable),
+			Key: map[string]types.AttributeValue{
+				"event_id": &types.AttributeValueMemberS{Value: eventID},
+				"id":       &types.AttributeValueMemberS{Value: seatKey(seatID)},
+			},
+			ConditionExpression:      aws.String("hold_id = :hold_id AND #status = :held"),
+			ExpressionAttributeNames: map[string]string{"#status": "status"},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":hold_id": &types.AttributeValueMemberS{Value: hold.ID.String()},
+				":held":    &types.AttributeValueMemberS{Value: model.SeatHeld},
+			},
+		})
+		if err != nil && !errors.As(err, &conditionErr) {
+			return fmt.Errorf("error releasing seat %s: %w", seatID, err)
+		}
+	}
+	return nil
+}
+
+// soldCountKey is the item counting the sold seats of an event, so reads do
+// not have to page through the partition.
+const soldCountKey = "count#sold"
+
+func seatKey(seatID string) string {
+	return "seat#" + seatID
+}
+
+func holdKey(holdID string) string {
+	return "hold#" + holdID
+}
+
+// expired reports whether the expires_at of item is not after now.
+func expired(item map[string]types.AttributeValue, now time.Time) bool {
+	expiresVal, ok := item["expires_at"].(*types.AttributeValueMemberN)
+	if !ok {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(expiresVal.Value, 10, 64)
+	return err == nil && expiresAt <= now.Unix()
+}
+
+func unmarshalSeatMap(item map[string]types.AttributeValue) (*model.SeatMap, error) {
+	seatMap := &model.SeatMap{}
+	var err error
+
+	if idVal, ok := item["id"].(*types.AttributeValueMemberS); ok {
+		if seatMap.EventID, err = uuid.Parse(idVal.Value); err != nil {
+			return nil, fmt.Errorf("invalid event ID: %v", err)
+		}
+	}
+	if tenantVal, ok := item["tenant_id"].(*types.AttributeValueMemberS); ok {
+		seatMap.TenantID = tenantVal.Value
+	}
+
+	if zonesVal, ok := item["zones"].(*types.AttributeValueMemberL); ok {
+		for _, v := range zonesVal.Value {
+			fields, ok := v.(*types.AttributeValueMemberM)
+			if !ok {
+				continue
+			}
+			var zone model.PriceZone
+			if codeVal, ok := fields.Value["code"].(*types.AttributeValueMemberS); ok {
+				zone.Code = codeVal.Value
+			}
+			if nameVal, ok := fields.Value["name"].(*types.AttributeValueMemberS); ok {
+				zone.Name = nameVal.Value
+			}
+			if priceVal, ok := fields.Value["price"].(*types.AttributeValueMemberN); ok {
+				if zone.Price, err = strconv.ParseFloat(priceVal.Value, 64); err != nil {
+					return nil, fmt.Errorf("invalid zone price: %v", err)
+				}
+			}
+			seatMap.Zones = append(seatMap.Zones, zone)
+		}
+	}
+
+	if sectionsVal, ok := item["sections"].(*types.AttributeValueMemberL); ok {
+		for _, v := range sectionsVal.Value {
+			fields, ok := v.(*types.AttributeValueMemberM)
+			if !ok {
+				continue
+			}
+			var section model.SeatSection
+			if nameVal, ok := fields.Value["name"].(*types.AttributeValueMemberS); ok {
+				section.Name = nameVal.Value
+			}
+			if zoneVal, ok := fields.Value["zone"].(*types.AttributeValueMemberS); ok {
+				section.Zone = zoneVal.Value
+			}
+			if rowsVal, ok := fields.Value["rows"].(*types.AttributeValueMemberL); ok {
+				for _, v := range rowsVal.Value {
+					row, err := unmarshalSeatRow(v)
+					if err != nil {
+						return nil, err
+					}
+					section.Rows = append(section.Rows, row)
+				}
+			}
+			seatMap.Sections = append(seatMap.Sections, section)
+		}
+	}
+
+	times := map[string]*time.Time{
+		"created_at": &seatMap.CreatedAt,
+		"updated_at": &seatMap.UpdatedAt,
+	}
+	for name, field := range times {
+		if val, ok := item[name].(*types.AttributeValueMemberS); ok {
+			if *field, err = time.Parse(time.RFC3339, val.Value); err != nil {
+				return nil, fmt.Errorf("invalid %s time: %v", name, err)
+			}
+		}
+	}
+
+	return seatMap, nil
+}
+
+func unmarshalSeatRow(v types.AttributeValue) (model.SeatRow, error) {
+	var row model.SeatRow
+	fields, ok := v.(*types.AttributeValueMemberM)
+	if !ok {
+		return row, errors.New("invalid seat row")
+	}
+	if nameVal, ok := fields.Value["name"].(*types.AttributeValueMemberS); ok {
+		row.Name = nameVal.Value
+	}
+	if zoneVal, ok := fields.Value["zone"].(*types.AttributeValueMemberS); ok {
+		row.Zone = zoneVal.Value
+	}
+	if seatsVal, ok := fields.Value["seats"].(*types.AttributeValueMemberN); ok {
+		seats, err := strconv.Atoi(seatsVal.Value)
+		if err != nil {
+			return row, fmt.Errorf("invalid row seats: %v", err)
+		}
+		row.Seats = seats
+	}
+	if accessibleVal, ok := fields.Value["accessible"].(*types.AttributeValueMemberNS); ok {
+		for _, s := range accessibleVal.Value {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return row, fmt.Errorf("invalid accessible seat: %v", err)
+			}
+			row.Accessible = append(row.Accessible, n)
+		}
+		// Sets are unordered
+		slices.Sort(row.Accessible)
+	}
+	return row, nil
+}
+
+func unmarshalHold(item map[string]types.AttributeValue) (*model.SeatHold, error) {
+	hold := &model.SeatHold{}
+	var err error
+
+	if idVal, ok := item["id"].(*types.AttributeValueMemberS); ok {
+		if hold.ID, err = uuid.Parse(strings.TrimPrefix(idVal.Value, holdKey(""))); err != nil {
+			return nil, fmt.Errorf("invalid seat hold ID: %v", err)
+		}
+	}
+	if eventVal, ok := item["event_id"].(*types.AttributeValueMemberS); ok {
+		if hold.EventID, err = uuid.Parse(eventVal.Value); err != nil {
+			return nil, fmt.Errorf("invalid event ID: %v", err)
+		}
+	}
+	if tenantVal, ok := item["tenant_id"].(*types.AttributeValueMemberS); ok {
+		hold.TenantID = tenantVal.Value
+	}
+	if holderVal, ok := item["holder"].(*types.AttributeValueMemberS); ok {
+		hold.Holder = holderVal.Value
+	}
+	if seatsVal, ok := item["seats"].(*types.AttributeValueMemberSS); ok {
+		hold.Seats = seatsVal.Value
+		slices.Sort(hold.Seats)
+	}
+	if statusVal, ok := item["status"].(*types.AttributeValueMemberS); ok {
+		hold.Status = statusVal.Value
+	}
+	if expiresVal, ok := item["expires_at"].(*types.AttributeValueMemberN); ok {
+		seconds, err := strconv.ParseInt(expiresVal.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expires_at: %v", err)
+		}
+		expiresAt := time.Unix(seconds, 0).UTC()
+		hold.ExpiresAt = &expiresAt
+	}
+	if createdVal, ok := item["created_at"].(*types.AttributeValueMemberS); ok {
+		if hold.CreatedAt, err = time.Parse(time.RFC3339, createdVal.Value); err != nil {
+			return nil, fmt.Errorf("invalid created_at time: %v", err)
+		}
+	}
+
+	return hold, nil
+}
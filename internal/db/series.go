@@ -0,0 +1,314 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+const (
+	SeriesTable = "event_series"
+	// EventsBySeriesIndex is the GSI on events keyed by series_id and sorted
+	// by starts_at.
+	EventsBySeriesIndex = "series_id-index"
+)
+
+var (
+	ErrSeriesNotFound = errors.New("event series not found")
+	// ErrSeriesChanged is returned when a series was updated since it was
+	// read.
+	ErrSeriesChanged = errors.New("event series modified concurrently")
+)
+
+func (d *DynamoClient) SaveSeries(ctx context.Context, series model.EventSeries) error {
+	tenantID, err := scopedTenant(ctx, series.TenantID)
+	if err != nil {
+		return err
+	}
+
+	exdates := make([]types.AttributeValue, 0, len(series.ExDates))
+	for _, exdate := range series.ExDates {
+		exdates = append(exdates, &types.AttributeValueMemberS{Value: exdate.UTC().Format(time.RFC3339)})
+	}
+
+	item := map[string]types.AttributeValue{
+		"id":                 &types.AttributeValueMemberS{Value: series.ID.String()},
+		"tenant_id":          &types.AttributeValueMemberS{Value: tenantID},
+		"organizer_id":       &types.AttributeValueMemberS{Value: series.OrganizerID},
+		"name":               &types.AttributeValueMemberS{Value: series.Name},
+		"description":        &types.AttributeValueMemberS{Value: series.Description},
+		"category_id":        &types.AttributeValueMemberS{Value: series.CategoryID.String()},
+		"location":           &types.AttributeValueMemberS{Value: series.Location},
+		"capacity":           &types.AttributeValueMemberN{Value: strconv.Itoa(series.Capacity)},
+		"price":              &types.AttributeValueMemberN{Value: fmt.Sprintf("%.2f", series.Price)},
+		"image_url":          &types.AttributeValueMemberS{Value: series.ImageURL},
+		"status":             &types.AttributeValueMemberS{Value: series.Status},
+		"starts_at":          &types.AttributeValueMemberS{Value: series.StartsAt.UTC().Format(time.RFC3339)},
+		"ends_at":            &types.AttributeValueMemberS{Value: series.EndsAt.UTC().Format(time.RFC3339)},
+		"time_zone":          &types.AttributeValueMemberS{Value: series.TimeZone},
+		"rrule":              &types.AttributeValueMemberS{Value: series.RRule},
+		"exdates":            &types.AttributeValueMemberL{Value: exdates},
+		"materialized_until": &types.AttributeValueMemberS{Value: series.MaterializedUntil.UTC().Format(time.RFC3339)},
+		"created_at":         &types.AttributeValueMemberS{Value: series.CreatedAt.UTC().Format(time.RFC3339)},
+		"updated_at":         &types.AttributeValueMemberS{Value: series.UpdatedAt.UTC().Format(time.RFC3339)},
+	}
+
+	setVenueID(item, series.VenueID)
+	setCoordinates(item, series.Latitude, series.Longitude)
+
+	_, err = d.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                 aws.String(SeriesTable),
+		Item:                      item,
+		ConditionExpression:       aws.String(sameTenantOrNewCondition),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+	})
+	if err != nil {
+		return fmt.Errorf("error saving event series: %w", err)
+	}
+	return nil
+}
+
+// SetSeriesMaterializedUntil records how far the occurrences of series
+// exist, leaving every other attribute alone. It only succeeds if the stored
+// series is still the one read, so a stale copy never undoes an update:
+// otherwise it returns ErrSeriesNotFound if the series was deleted and
+// ErrSeriesChanged if it was updated.
+func (d *DynamoClient) SetSeriesMaterializedUntil(ctx context.Context, series model.EventSeries) error {
+	tenantID, err := scopedTenant(ctx, series.TenantID)
+	if err != nil {
+		return err
+	}
+
+	names := tenantAttributeNames()
+	names["#materialized_until"] = "materialized_until"
+	names["#updated_at"] = "updated_at"
+	values := tenantAttributeValues(tenantID)
+	values[":materialized_until"] = &types.AttributeValueMemberS{Value: series.MaterializedUntil.UTC().Format(time.RFC3339)}
+	values[":updated_at"] = &types.AttributeValueMemberS{Value: series.UpdatedAt.UTC().Format(time.RFC3339)}
+
+	_, err = d.Client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(SeriesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: series.ID.String()},
+		},
+		UpdateExpression:                    aws.String("SET #materialized_until = :materialized_until"),
+		ConditionExpression:                 aws.String("attribute_exists(id) AND #tenant_id = :tenant_id AND #updated_at = :updated_at"),
+		ExpressionAttributeNames:            names,
+		ExpressionAttributeValues:           values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		if conditionErr.Item == nil || !belongsTo(conditionErr.Item, tenantID) {
+			return ErrSeriesNotFound
+		}
+		return ErrSeriesChanged
+	}
+	if err != nil {
+		return fmt.Errorf("error updating event series: %w", err)
+	}
+	return nil
+}
+
+func (d *DynamoClient) GetSeriesByID(ctx context.Context, id string) (*model.EventSeries, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(SeriesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting event series: %w", err)
+	}
+	if result.Item == nil || !belongsTo(result.Item, tenantID) {
+		return nil, ErrSeriesNotFound
+	}
+	return unmarshalSeries(result.Item)
+}
+
+func (d *DynamoClient) DeleteSeries(ctx context.Context, id string) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(SeriesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		ConditionExpression:       aws.String("#tenant_id = :tenant_id"),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return ErrSeriesNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting event series: %w", err)
+	}
+	return nil
+}
+
+// ScanSeries returns the series of every tenant. It is meant for the
+// background materializer, which then works within each series' tenant.
+func (d *DynamoClient) ScanSeries(ctx context.Context) ([]model.EventSeries, error) {
+	var series []model.EventSeries
+	paginator := dynamodb.NewScanPaginator(d.Client, &dynamodb.ScanInput{
+		TableName: aws.String(SeriesTable),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning event series: %w", err)
+		}
+		for _, item := range page.Items {
+			s, err := unmarshalSeries(item)
+			if err != nil {
+				return nil, err
+			}
+			series = append(series, *s)
+		}
+	}
+	return series, nil
+}
+
+// GetEventsBySeries returns the occurrences of a series starting at or after
+// from, in chronological order.
+func (d *DynamoClient) GetEventsBySeries(ctx context.Context, seriesID string, from time.Time) ([]model.Event, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := tenantAttributeNames()
+	names["#series_id"] = "series_id"
+	names["#starts_at"] = "starts_at"
+	values := tenantAttributeValues(tenantID)
+	values[":series_id"] = &types.AttributeValueMemberS{Value: seriesID}
+	values[":from"] = &types.AttributeValueMemberS{Value: from.UTC().Format(time.RFC3339)}
+
+	var events []model.Event
+	paginator := dynamodb.NewQueryPaginator(d.Client, &dynamodb.QueryInput{
+		TableName:                 aws.String(EventsTable),
+		IndexName:                 aws.String(EventsBySeriesIndex),
+		KeyConditionExpression:    aws.String("#series_id = :series_id AND #starts_at >= :from"),
+		FilterExpression:          aws.String("#tenant_id = :tenant_id"),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error querying series events: %w", err)
+		}
+		for _, item := range page.Items {
+			event, err := d.unmarshalEvent(item)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, *event)
+		}
+	}
+	return events, nil
+}
+
+func unmarshalSeries(item map[string]types.AttributeValue) (*model.EventSeries, error) {
+	series := &model.EventSeries{}
+	var err error
+
+	if idVal, ok := item["id"].(*types.AttributeValueMemberS); ok {
+		if series.ID, err = uuid.Parse(idVal.Value); err != nil {
+			return nil, fmt.Errorf("invalid series ID: %v", err)
+		}
+	}
+	if categoryIDVal, ok := item["category_id"].(*types.AttributeValueMemberS); ok {
+		if series.CategoryID, err = uuid.Parse(categoryIDVal.Value); err != nil {
+			return nil, fmt.Errorf("invalid category ID: %v", err)
+		}
+	}
+
+	if series.VenueID, err = unmarshalVenueID(item); err != nil {
+		return nil, err
+	}
+	if series.Latitude, series.Longitude, err = unmarshalCoordinates(item); err != nil {
+		return nil, err
+	}
+
+	texts := map[string]*string{
+		"tenant_id":    &series.TenantID,
+		"organizer_id": &series.OrganizerID,
+		"name":         &series.Name,
+		"description":  &series.Description,
+		"location":     &series.Location,
+		"image_url":    &series.ImageURL,
+		"time_zone":    &series.TimeZone,
+		"rrule":        &series.RRule,
+		"status":       &series.Status,
+	}
+	for name, field := range texts {
+		if val, ok := item[name].(*types.AttributeValueMemberS); ok {
+			*field = val.Value
+		}
+	}
+
+	times := map[string]*time.Time{
+		"starts_at":          &series.StartsAt,
+		"ends_at":            &series.EndsAt,
+		"materialized_until": &series.MaterializedUntil,
+		"created_at":         &series.CreatedAt,
+		"updated_at":         &series.UpdatedAt,
+	}
+	for name, field := range times {
+		if val, ok := item[name].(*types.AttributeValueMemberS); ok {
+			t, err := time.Parse(time.RFC3339, val.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s time: %v", name, err)
+			}
+			*field = t.UTC()
+		}
+	}
+
+	if capacityVal, ok := item["capacity"].(*types.AttributeValueMemberN); ok {
+		if series.Capacity, err = strconv.Atoi(capacityVal.Value); err != nil {
+			return nil, fmt.Errorf("invalid capacity: %v", err)
+		}
+	}
+	if priceVal, ok := item["price"].(*types.AttributeValueMemberN); ok {
+		if series.Price, err = strconv.ParseFloat(priceVal.Value, 64); err != nil {
+			return nil, fmt.Errorf("invalid price: %v", err)
+		}
+	}
+
+	if exdatesVal, ok := item["exdates"].(*types.AttributeValueMemberL); ok {
+		for _, v := range exdatesVal.Value {
+			s, ok := v.(*types.AttributeValueMemberS)
+			if !ok {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, s.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exdate: %v", err)
+			}
+			series.ExDates = append(series.ExDates, t.UTC())
+		}
+	}
+
+	return series, nil
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+)
+
+// Translations are stored as a map attribute keyed by locale, each value a
+// map with name and description.
+
+func setTranslations(item map[string]types.AttributeValue, translations model.Translations) {
+	if len(translations) == 0 {
+		return
+	}
+	value := make(map[string]types.AttributeValue, len(translations))
+	for locale, t := range translations {
+		value[locale] = &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"name":        &types.AttributeValueMemberS{Value: t.Name},
+			"description": &types.AttributeValueMemberS{Value: t.Description},
+		}}
+	}
+	item["translations"] = &types.AttributeValueMemberM{Value: value}
+}
+
+func unmarshalTranslations(item map[string]types.AttributeValue) model.Translations {
+	translationsVal, ok := item["translations"].(*types.AttributeValueMemberM)
+	if !ok {
+		return nil
+	}
+	translations := make(model.Translations, len(translationsVal.Value))
+	for locale, v := range translationsVal.Value {
+		fields, ok := v.(*types.AttributeValueMemberM)
+		if !ok {
+			continue
+		}
+		var t model.Translation
+		if nameVal, ok := fields.Value["name"].(*types.AttributeValueMemberS); ok {
+			t.Name = nameVal.Value
+		}
+		if descriptionVal, ok := fields.Value["description"].(*types.AttributeValueMemberS); ok {
+			t.Description = descriptionVal.Value
+		}
+		translations[locale] = t
+	}
+	return translations
+}
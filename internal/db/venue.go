@@ -0,0 +1,240 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+const (
+	VenuesTable = "venues"
+	// VenuesByTenantIndex is the GSI on venues keyed by tenant_id and sorted
+	// by created_at.
+	VenuesByTenantIndex = "tenant_id-index"
+)
+
+var ErrVenueNotFound = errors.New("venue not found")
+
+func (d *DynamoClient) SaveVenue(ctx context.Context, venue model.Venue) error {
+	tenantID, err := scopedTenant(ctx, venue.TenantID)
+	if err != nil {
+		return err
+	}
+
+	sections := make([]types.AttributeValue, 0, len(venue.Sections))
+	for _, section := range venue.Sections {
+		sections = append(sections, &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"name":     &types.AttributeValueMemberS{Value: section.Name},
+			"capacity": &types.AttributeValueMemberN{Value: strconv.Itoa(section.Capacity)},
+		}})
+	}
+
+	item := map[string]types.AttributeValue{
+		"id":         &types.AttributeValueMemberS{Value: venue.ID.String()},
+		"tenant_id":  &types.AttributeValueMemberS{Value: tenantID},
+		"name":       &types.AttributeValueMemberS{Value: venue.Name},
+		"address":    &types.AttributeValueMemberS{Value: venue.Address},
+		"city":       &types.AttributeValueMemberS{Value: venue.City},
+		"country":    &types.AttributeValueMemberS{Value: venue.Country},
+		"latitude":   &types.AttributeValueMemberN{Value: strconv.FormatFloat(venue.Latitude, 'f', -1, 64)},
+		"longitude":  &types.AttributeValueMemberN{Value: strconv.FormatFloat(venue.Longitude, 'f', -1, 64)},
+		"time_zone":  &types.AttributeValueMemberS{Value: venue.TimeZone},
+		"capacity":   &types.AttributeValueMemberN{Value: strconv.Itoa(venue.Capacity)},
+		"sections":   &types.AttributeValueMemberL{Value: sections},
+		"created_at": &types.AttributeValueMemberS{Value: venue.CreatedAt.Format(time.RFC3339)},
+		"updated_at": &types.AttributeValueMemberS{Value: venue.UpdatedAt.Format(time.RFC3339)},
+	}
+
+	_, err = d.Client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                 aws.String(VenuesTable),
+		Item:                      item,
+		ConditionExpression:       aws.String(sameTenantOrNewCondition),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+	})
+	if err != nil {
+		return fmt.Errorf("error saving venue: %w", err)
+	}
+	return nil
+}
+
+func (d *DynamoClient) GetVenueByID(ctx context.Context, id string) (*model.Venue, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.Client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(VenuesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting venue: %w", err)
+	}
+	if result.Item == nil || !belongsTo(result.Item, tenantID) {
+		return nil, ErrVenueNotFound
+	}
+	return unmarshalVenue(result.Item)
+}
+
+// ListVenues lists the tenant's venues, oldest first.
+func (d *DynamoClient) ListVenues(ctx context.Context) ([]model.Venue, error) {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var venues []model.Venue
+	paginator := dynamodb.NewQueryPaginator(d.Client, &dynamodb.QueryInput{
+		TableName:                 aws.String(VenuesTable),
+		IndexName:                 aws.String(VenuesByTenantIndex),
+		KeyConditionExpression:    aws.String("#tenant_id = :tenant_id"),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing venues: %w", err)
+		}
+		for _, item := range page.Items {
+			venue, err := unmarshalVenue(item)
+			if err != nil {
+				return nil, err
+			}
+			venues = append(venues, *venue)
+		}
+	}
+	return venues, nil
+}
+
+func (d *DynamoClient) DeleteVenue(ctx context.Context, id string) error {
+	tenantID, err := tenant.Require(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.Client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(VenuesTable),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		ConditionExpression:       aws.String("#tenant_id = :tenant_id"),
+		ExpressionAttributeNames:  tenantAttributeNames(),
+		ExpressionAttributeValues: tenantAttributeValues(tenantID),
+	})
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return ErrVenueNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error deleting venue: %w", err)
+	}
+	return nil
+}
+
+// setVenueID stores the venue an event or series is held at, if any.
+func setVenueID(item map[string]types.AttributeValue, venueID *uuid.UUID) {
+	if venueID != nil {
+		item["venue_id"] = &types.AttributeValueMemberS{Value: venueID.String()}
+	}
+}
+
+func unmarshalVenueID(item map[string]types.AttributeValue) (*uuid.UUID, error) {
+	venueIDVal, ok := item["venue_id"].(*types.AttributeValueMemberS)
+	if !ok {
+		return nil, nil
+	}
+	venueID, err := uuid.Parse(venueIDVal.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid venue ID: %v", err)
+	}
+	return &venueID, nil
+}
+
+func unmarshalVenue(item map[string]types.AttributeValue) (*model.Venue, error) {
+	venue := &model.Venue{}
+	var err error
+
+	if idVal, ok := item["id"].(*types.AttributeValueMemberS); ok {
+		if venue.ID, err = uuid.Parse(idVal.Value); err != nil {
+			return nil, fmt.Errorf("invalid venue ID: %v", err)
+		}
+	}
+
+	texts := map[string]*string{
+		"tenant_id": &venue.TenantID,
+		"name":      &venue.Name,
+		"address":   &venue.Address,
+		"city":      &venue.City,
+		"country":   &venue.Country,
+		"time_zone": &venue.TimeZone,
+	}
+	for name, field := range texts {
+		if val, ok := item[name].(*types.AttributeValueMemberS); ok {
+			*field = val.Value
+		}
+	}
+
+	coordinates := map[string]*float64{
+		"latitude":  &venue.Latitude,
+		"longitude": &venue.Longitude,
+	}
+	for name, field := range coordinates {
+		if val, ok := item[name].(*types.AttributeValueMemberN); ok {
+			if *field, err = strconv.ParseFloat(val.Value, 64); err != nil {
+				return nil, fmt.Errorf("invalid %s: %v", name, err)
+			}
+		}
+	}
+
+	if capacityVal, ok := item["capacity"].(*types.AttributeValueMemberN); ok {
+		if venue.Capacity, err = strconv.Atoi(capacityVal.Value); err != nil {
+			return nil, fmt.Errorf("invalid capacity: %v", err)
+		}
+	}
+
+	if sectionsVal, ok := item["sections"].(*types.AttributeValueMemberL); ok {
+		for _, v := range sectionsVal.Value {
+			m, ok := v.(*types.AttributeValueMemberM)
+			if !ok {
+				continue
+			}
+			var section model.Section
+			if nameVal, ok := m.Value["name"].(*types.AttributeValueMemberS); ok {
+				section.Name = nameVal.Value
+			}
+			if capacityVal, ok := m.Value["capacity"].(*types.AttributeValueMemberN); ok {
+				if section.Capacity, err = strconv.Atoi(capacityVal.Value); err != nil {
+					return nil, fmt.Errorf("invalid section capacity: %v", err)
+				}
+			}
+			venue.Sections = append(venue.Sections, section)
+		}
+	}
+
+	times := map[string]*time.Time{
+		"created_at": &venue.CreatedAt,
+		"updated_at": &venue.UpdatedAt,
+	}
+	for name, field := range times {
+		if val, ok := item[name].(*types.AttributeValueMemberS); ok {
+			if *field, err = time.Parse(time.RFC3339, val.Value); err != nil {
+				return nil, fmt.Errorf("invalid %s time: %v", name, err)
+			}
+		}
+	}
+
+	return venue, nil
+}
@@ -0,0 +1,117 @@
+// Package geo locates events: it parses points, measures distances and maps
+// coordinates to the geohash cells the events table is indexed by.
+package geo
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/mmcloughlin/geohash"
+)
+
+const (
+	// CellPrecision is the geohash length of the cells events are indexed
+	// by, about 39 x 19.5 km at the equator.
+	CellPrecision = 4
+	// HashPrecision is the geohash length stored with each event, about
+	// 5 x 5 m.
+	HashPrecision = 9
+	// MaxCells bounds how many cells a single search may cover, which only
+	// matters near the poles where cells get narrow.
+	MaxCells = 256
+
+	earthRadiusKm = 6371.0088
+	kmPerDegree   = math.Pi * earthRadiusKm / 180
+)
+
+var (
+	ErrInvalidPoint = errors.New("point must be latitude,longitude")
+	ErrAreaTooLarge = errors.New("search area covers too many cells")
+)
+
+// ParsePoint parses "lat,lng" in decimal degrees.
+func ParsePoint(s string) (lat, lng float64, err error) {
+	latStr, lngStr, ok := strings.Cut(s, ",")
+	if !ok {
+		return 0, 0, ErrInvalidPoint
+	}
+	lat, err = strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, ErrInvalidPoint
+	}
+	lng, err = strconv.ParseFloat(strings.TrimSpace(lngStr), 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return 0, 0, ErrInvalidPoint
+	}
+	return lat, lng, nil
+}
+
+// Hash is the geohash stored with an event at lat, lng.
+func Hash(lat, lng float64) string {
+	return geohash.EncodeWithPrecision(lat, lng, HashPrecision)
+}
+
+// Cell is the index cell containing lat, lng.
+func Cell(lat, lng float64) string {
+	return geohash.EncodeWithPrecision(lat, lng, CellPrecision)
+}
+
+// DistanceKm is the great-circle distance between two points.
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat, dLng := radians(lat2-lat1), radians(lng2-lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Cells returns the index cells that cover the circle of radiusKm around
+// lat, lng. It walks the circle's bounding box one cell at a time, so every
+// point within the radius falls in one of them.
+func Cells(lat, lng, radiusKm float64) ([]string, error) {
+	box := geohash.BoundingBox(Cell(lat, lng))
+	cellHeight, cellWidth := box.MaxLat-box.MinLat, box.MaxLng-box.MinLng
+
+	dLat := radiusKm / kmPerDegree
+	minLat, maxLat := math.Max(lat-dLat, -90), math.Min(lat+dLat, 90)
+	// Past a pole, or when the box spans every meridian, search them all
+	minLng, maxLng := -180.0, 180.0
+	if cos := math.Cos(radians(math.Max(math.Abs(minLat), math.Abs(maxLat)))); cos > 0 {
+		if dLng := radiusKm / (kmPerDegree * cos); dLng < 180 {
+			minLng, maxLng = lng-dLng, lng+dLng
+		}
+	}
+
+	seen := make(map[string]bool)
+	var cells []string
+	for y := minLat; y < maxLat+cellHeight; y += cellHeight {
+		for x := minLng; x < maxLng+cellWidth; x += cellWidth {
+			cell := Cell(math.Min(y, maxLat), wrap(math.Min(x, maxLng)))
+			if seen[cell] {
+				continue
+			}
+			if len(cells) == MaxCells {
+				return nil, ErrAreaTooLarge
+			}
+			seen[cell] = true
+			cells = append(cells, cell)
+		}
+	}
+	return cells, nil
+}
+
+// wrap brings a longitude that crossed the antimeridian back into range.
+func wrap(lng float64) float64 {
+	switch {
+	case lng < -180:
+		return lng + 360
+	case lng > 180:
+		return lng - 360
+	}
+	return lng
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
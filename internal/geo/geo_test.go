@@ -0,0 +1,138 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestParsePoint(t *testing.T) {
+	tests := []struct {
+		in       string
+		lat, lng float64
+		wantErr  bool
+	}{
+		{in: "40.4168,-3.7038", lat: 40.4168, lng: -3.7038},
+		{in: " -33.86 , 151.21 ", lat: -33.86, lng: 151.21},
+		{in: "90,180", lat: 90, lng: 180},
+		{in: "40.4168", wantErr: true},
+		{in: "91,0", wantErr: true},
+		{in: "0,-181", wantErr: true},
+		{in: "north,west", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			lat, lng, err := ParsePoint(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPoint) {
+					t.Fatalf("err = %v, want ErrInvalidPoint", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if lat != tt.lat || lng != tt.lng {
+				t.Fatalf("got %v,%v, want %v,%v", lat, lng, tt.lat, tt.lng)
+			}
+		})
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lng1, lat2, lng2 float64
+		want                   float64
+	}{
+		{name: "same point", lat1: 40.4168, lng1: -3.7038, lat2: 40.4168, lng2: -3.7038, want: 0},
+		{name: "madrid to barcelona", lat1: 40.4168, lng1: -3.7038, lat2: 41.3874, lng2: 2.1686, want: 505},
+		{name: "one degree on the equator", lat1: 0, lng1: 0, lat2: 0, lng2: 1, want: kmPerDegree},
+		{name: "across the antimeridian", lat1: 0, lng1: 179.5, lat2: 0, lng2: -179.5, want: kmPerDegree},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistanceKm(tt.lat1, tt.lng1, tt.lat2, tt.lng2)
+			if math.Abs(got-tt.want) > 1 {
+				t.Fatalf("DistanceKm = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCellsCoverRadius(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lng float64
+		radiusKm float64
+	}{
+		{name: "inside one cell", lat: 40.4168, lng: -3.7038, radiusKm: 1},
+		{name: "city", lat: 40.4168, lng: -3.7038, radiusKm: 50},
+		{name: "equator", lat: 0, lng: 0, radiusKm: 100},
+		{name: "antimeridian", lat: -17.7, lng: 179.9, radiusKm: 60},
+		{name: "high latitude", lat: 69.65, lng: 18.96, radiusKm: 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells, err := Cells(tt.lat, tt.lng, tt.radiusKm)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// Points on the circle are the ones most likely to be missed
+			for bearing := 0; bearing < 360; bearing += 5 {
+				for _, d := range []float64{0, tt.radiusKm / 2, tt.radiusKm * 0.999} {
+					lat, lng := destination(tt.lat, tt.lng, float64(bearing), d)
+					if !slices.Contains(cells, Cell(lat, lng)) {
+						t.Fatalf("point %.4f,%.4f at %.1f km, bearing %d, not covered by %v", lat, lng, d, bearing, cells)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCellsUnique(t *testing.T) {
+	cells, err := Cells(40.4168, -3.7038, 80)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, cell := range cells {
+		if seen[cell] {
+			t.Fatalf("cell %s returned twice", cell)
+		}
+		if len(cell) != CellPrecision {
+			t.Fatalf("cell %s has precision %d, want %d", cell, len(cell), CellPrecision)
+		}
+		seen[cell] = true
+	}
+}
+
+func TestCellsAreaTooLarge(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lng float64
+		radiusKm float64
+	}{
+		{name: "wide radius", lat: 40.4168, lng: -3.7038, radiusKm: 1000},
+		{name: "near a pole", lat: 89.5, lng: 0, radiusKm: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Cells(tt.lat, tt.lng, tt.radiusKm); !errors.Is(err, ErrAreaTooLarge) {
+				t.Fatalf("err = %v, want ErrAreaTooLarge", err)
+			}
+		})
+	}
+}
+
+// destination is the point distanceKm away from lat, lng along bearing.
+func destination(lat, lng, bearing, distanceKm float64) (float64, float64) {
+	phi, lambda, theta := radians(lat), radians(lng), radians(bearing)
+	delta := distanceKm / earthRadiusKm
+	phi2 := math.Asin(math.Sin(phi)*math.Cos(delta) + math.Cos(phi)*math.Sin(delta)*math.Cos(theta))
+	lambda2 := lambda + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(phi), math.Cos(delta)-math.Sin(phi)*math.Sin(phi2))
+	return phi2 * 180 / math.Pi, wrap(lambda2 * 180 / math.Pi)
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/auth"
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+type APIKeyHandler struct {
+	DB *db.DynamoClient
+}
+
+func NewAPIKeyHandler(db *db.DynamoClient) *APIKeyHandler {
+	return &APIKeyHandler{DB: db}
+}
+
+// CreateAPIKey issues a new key. The plaintext key is only returned here.
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req model.CreateAPIKeyRequest
+	if !bindJSON(c, &req, i18n.APIKeyInvalidData) {
+		return
+	}
+
+	ownerID := req.OwnerID
+	if ownerID == "" {
+		ownerID = auth.FromContext(c.Request.Context()).Subject
+	}
+
+	keyID := uuid.New()
+	plaintext, hash, err := auth.GenerateAPIKey(keyID)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error generando API key", "error", err)
+		problem.Internal(c, i18n.APIKeyGenerateFailed)
+		return
+	}
+
+	now := time.Now()
+	key := &model.APIKey{
+		ID:        keyID,
+		TenantID:  tenant.FromContext(c.Request.Context()),
+		Name:      req.Name,
+		OwnerID:   ownerID,
+		KeyHash:   hash,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := h.DB.SaveAPIKey(c.Request.Context(), *key); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error creando API key", "error", err)
+		problem.Internal(c, i18n.APIKeyCreateFailed)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.APIKeyCreated),
+		"api_key": key,
+		"key":     plaintext,
+	})
+}
+
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.DB.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error obteniendo API keys", "error", err)
+		problem.Internal(c, i18n.APIKeyListFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys": keys,
+		"count":    len(keys),
+	})
+}
+
+// RotateAPIKey replaces the secret of a key, keeping its ID and scopes. The
+// previous secret stops working immediately.
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	key, ok := h.loadKey(c)
+	if !ok {
+		return
+	}
+	if key.RevokedAt != nil {
+		problem.Conflict(c, i18n.APIKeyAlreadyRevoked)
+		return
+	}
+
+	plaintext, hash, err := auth.GenerateAPIKey(key.ID)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error generando API key", "error", err)
+		problem.Internal(c, i18n.APIKeyGenerateFailed)
+		return
+	}
+	key, err = h.DB.RotateAPIKey(c.Request.Context(), key.ID, key.KeyHash, hash, time.Now())
+	if err != nil {
+		if errors.Is(err, db.ErrAPIKeyChanged) {
+			problem.Conflict(c, i18n.APIKeyChanged)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error rotando API key", "error", err)
+		problem.Internal(c, i18n.APIKeyRotateFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.APIKeyRotated),
+		"api_key": key,
+		"key":     plaintext,
+	})
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	key, ok := h.loadKey(c)
+	if !ok {
+		return
+	}
+
+	if key.RevokedAt == nil {
+		revoked, err := h.DB.RevokeAPIKey(c.Request.Context(), key.ID, time.Now())
+		switch {
+		case errors.Is(err, db.ErrAPIKeyChanged):
+			// Revoked concurrently; report the stored revocation.
+			if key, ok = h.loadKey(c); !ok {
+				return
+			}
+		case err != nil:
+			slog.ErrorContext(c.Request.Context(), "error revocando API key", "error", err)
+			problem.Internal(c, i18n.APIKeyRevokeFailed)
+			return
+		default:
+			key = revoked
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.APIKeyRevoked),
+		"api_key": key,
+	})
+}
+
+// loadKey fetches the key named by the :id parameter, writing the error
+// response itself when it cannot.
+func (h *APIKeyHandler) loadKey(c *gin.Context) (*model.APIKey, bool) {
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		problem.BadRequest(c, i18n.APIKeyInvalidID)
+		return nil, false
+	}
+
+	key, err := h.DB.GetAPIKey(c.Request.Context(), keyID)
+	if err != nil {
+		if errors.Is(err, db.ErrAPIKeyNotFound) {
+			problem.NotFound(c, i18n.APIKeyNotFound)
+			return nil, false
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo API key", "error", err)
+		problem.Internal(c, i18n.APIKeyGetFailed)
+		return nil, false
+	}
+	return key, true
+}
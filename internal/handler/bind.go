@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/middleware"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/validation"
+)
+
+// bindJSON decodes and validates the request body into obj. On failure it
+// responds with a validation problem listing the invalid fields, or 413 if
+// the body exceeds the limit of middleware.BodyLimit, and returns false.
+func bindJSON(c *gin.Context, obj any, detail i18n.Key) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		if middleware.IsBodyTooLarge(err) {
+			problem.TooLarge(c, i18n.RequestTooLarge)
+			return false
+		}
+		problem.Validation(c, detail, validation.Errors(c.Request.Context(), err))
+		return false
+	}
+	return true
+}
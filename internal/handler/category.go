@@ -1,13 +1,18 @@
 package handler
 
 import (
+	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
 	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
 )
 
 type CategoryHandler struct {
@@ -20,9 +25,7 @@ func NewCategoryHandler(db *db.DynamoClient) *CategoryHandler {
 
 func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	var req model.CreateCategoryRequest
-
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos de categoría inválidos", "details": err.Error()})
+	if !bindJSON(c, &req, i18n.CategoryInvalidData) {
 		return
 	}
 
@@ -31,19 +34,114 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 
 	category := &model.Category{
 		ID:          categoryID,
+		TenantID:    tenant.FromContext(c.Request.Context()),
 		Name:        req.Name,
 		Description: req.Description,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 
-	if err := h.DB.SaveCategory(*category); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creando categoría", "details": err.Error()})
+	if err := h.DB.SaveCategory(c.Request.Context(), *category); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error creando categoría", "error", err)
+		problem.Internal(c, i18n.CategoryCreateFailed)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message":  "Categoría creada con éxito",
+		"message":  i18n.T(c.Request.Context(), i18n.CategoryCreated),
 		"category": category,
 	})
-} 
\ No newline at end of file
+}
+
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	category, ok := h.loadCategory(c)
+	if !ok {
+		return
+	}
+
+	localizeCategory(c, category)
+
+	c.JSON(http.StatusOK, gin.H{"category": category})
+}
+
+// PutCategoryTranslation adds or replaces the translation of a category for
+// the locale in the path.
+func (h *CategoryHandler) PutCategoryTranslation(c *gin.Context) {
+	locale, ok := translationLocale(c)
+	if !ok {
+		return
+	}
+
+	var req model.CategoryTranslationRequest
+	if !bindJSON(c, &req, i18n.TranslationInvalidData) {
+		return
+	}
+
+	category, ok := h.loadCategory(c)
+	if !ok {
+		return
+	}
+
+	if category.Translations == nil {
+		category.Translations = make(model.Translations)
+	}
+	category.Translations[locale] = model.Translation{Name: req.Name, Description: req.Description}
+	category.UpdatedAt = time.Now()
+
+	if err := h.DB.SaveCategory(c.Request.Context(), *category); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error guardando traducción de la categoría", "error", err)
+		problem.Internal(c, i18n.CategoryUpdateFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  i18n.T(c.Request.Context(), i18n.TranslationSaved),
+		"category": category,
+	})
+}
+
+// DeleteCategoryTranslation removes the translation of a category for the
+// locale in the path.
+func (h *CategoryHandler) DeleteCategoryTranslation(c *gin.Context) {
+	locale, ok := translationLocale(c)
+	if !ok {
+		return
+	}
+
+	category, ok := h.loadCategory(c)
+	if !ok {
+		return
+	}
+
+	if _, exists := category.Translations[locale]; !exists {
+		problem.NotFound(c, i18n.TranslationNotFound)
+		return
+	}
+	delete(category.Translations, locale)
+	category.UpdatedAt = time.Now()
+
+	if err := h.DB.SaveCategory(c.Request.Context(), *category); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error eliminando traducción de la categoría", "error", err)
+		problem.Internal(c, i18n.CategoryUpdateFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  i18n.T(c.Request.Context(), i18n.TranslationDeleted),
+		"category": category,
+	})
+}
+
+func (h *CategoryHandler) loadCategory(c *gin.Context) (*model.Category, bool) {
+	category, err := h.DB.GetCategoryByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			problem.NotFound(c, i18n.CategoryNotFound)
+			return nil, false
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo categoría", "error", err)
+		problem.Internal(c, i18n.CategoryGetFailed)
+		return nil, false
+	}
+	return category, true
+}
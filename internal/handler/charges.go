@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/pricing"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+	"github.com/jhonathanssegura/ticket-events/internal/validation"
+)
+
+type TaxHandler struct {
+	DB *db.DynamoClient
+}
+
+func NewTaxHandler(db *db.DynamoClient) *TaxHandler {
+	return &TaxHandler{DB: db}
+}
+
+func (h *TaxHandler) ListTaxJurisdictions(c *gin.Context) {
+	jurisdictions, err := h.DB.ListTaxJurisdictions(c.Request.Context())
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error listando impuestos", "error", err)
+		problem.Internal(c, i18n.TaxJurisdictionListFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tax_rates": jurisdictions,
+		"count":     len(jurisdictions),
+	})
+}
+
+// PutTaxJurisdiction defines or replaces the taxes charged on events held in
+// the country in the path.
+func (h *TaxHandler) PutTaxJurisdiction(c *gin.Context) {
+	country, ok := taxCountry(c)
+	if !ok {
+		return
+	}
+
+	var req model.PutTaxJurisdictionRequest
+	if !bindJSON(c, &req, i18n.TaxJurisdictionInvalidData) {
+		return
+	}
+
+	existing, err := h.DB.GetTaxJurisdiction(c.Request.Context(), country)
+	if err != nil && !errors.Is(err, db.ErrTaxJurisdictionNotFound) {
+		slog.ErrorContext(c.Request.Context(), "error obteniendo impuestos", "error", err)
+		problem.Internal(c, i18n.TaxJurisdictionGetFailed)
+		return
+	}
+
+	now := time.Now()
+	jurisdiction := &model.TaxJurisdiction{
+		Country:   country,
+		TenantID:  tenant.FromContext(c.Request.Context()),
+		Taxes:     req.Taxes,
+		TaxFees:   req.TaxFees,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if existing != nil {
+		jurisdiction.CreatedAt = existing.CreatedAt
+	}
+
+	if err := h.DB.SaveTaxJurisdiction(c.Request.Context(), *jurisdiction); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error guardando impuestos", "error", err)
+		problem.Internal(c, i18n.TaxJurisdictionSaveFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  i18n.T(c.Request.Context(), i18n.TaxJurisdictionSaved),
+		"tax_rate": jurisdiction,
+	})
+}
+
+func (h *TaxHandler) GetTaxJurisdiction(c *gin.Context) {
+	country, ok := taxCountry(c)
+	if !ok {
+		return
+	}
+
+	jurisdiction, err := h.DB.GetTaxJurisdiction(c.Request.Context(), country)
+	if err != nil {
+		if errors.Is(err, db.ErrTaxJurisdictionNotFound) {
+			problem.NotFound(c, i18n.TaxJurisdictionNotFound)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo impuestos", "error", err)
+		problem.Internal(c, i18n.TaxJurisdictionGetFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tax_rate": jurisdiction})
+}
+
+// DeleteTaxJurisdiction removes the taxes of the country in the path; its
+// events are priced without taxes from then on.
+func (h *TaxHandler) DeleteTaxJurisdiction(c *gin.Context) {
+	country, ok := taxCountry(c)
+	if !ok {
+		return
+	}
+
+	if err := h.DB.DeleteTaxJurisdiction(c.Request.Context(), country); err != nil {
+		if errors.Is(err, db.ErrTaxJurisdictionNotFound) {
+			problem.NotFound(c, i18n.TaxJurisdictionNotFound)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error eliminando impuestos", "error", err)
+		problem.Internal(c, i18n.TaxJurisdictionDeleteFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.TaxJurisdictionDeleted),
+	})
+}
+
+// PutCategoryFees defines or replaces the service fees charged on tickets
+// of events of the category.
+func (h *CategoryHandler) PutCategoryFees(c *gin.Context) {
+	var req model.PutCategoryFeesRequest
+	if !bindJSON(c, &req, i18n.CategoryFeesInvalidData) {
+		return
+	}
+
+	category, ok := h.loadCategory(c)
+	if !ok {
+		return
+	}
+
+	existing, err := h.DB.GetCategoryFees(c.Request.Context(), category.ID.String())
+	if err != nil && !errors.Is(err, db.ErrCategoryFeesNotFound) {
+		slog.ErrorContext(c.Request.Context(), "error obteniendo cargos por servicio", "error", err)
+		problem.Internal(c, i18n.CategoryFeesGetFailed)
+		return
+	}
+
+	now := time.Now()
+	fees := &model.CategoryFees{
+		CategoryID: category.ID,
+		TenantID:   category.TenantID,
+		Fees:       req.Fees,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if existing != nil {
+		fees.CreatedAt = existing.CreatedAt
+	}
+
+	if err := h.DB.SaveCategoryFees(c.Request.Context(), *fees); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error guardando cargos por servicio", "error", err)
+		problem.Internal(c, i18n.CategoryFeesSaveFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       i18n.T(c.Request.Context(), i18n.CategoryFeesSaved),
+		"category_fees": fees,
+	})
+}
+
+func (h *CategoryHandler) GetCategoryFees(c *gin.Context) {
+	fees, err := h.DB.GetCategoryFees(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, db.ErrCategoryFeesNotFound) {
+			problem.NotFound(c, i18n.CategoryFeesNotFound)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo cargos por servicio", "error", err)
+		problem.Internal(c, i18n.CategoryFeesGetFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"category_fees": fees})
+}
+
+func (h *CategoryHandler) DeleteCategoryFees(c *gin.Context) {
+	if err := h.DB.DeleteCategoryFees(c.Request.Context(), c.Param("id")); err != nil {
+		if errors.Is(err, db.ErrCategoryFeesNotFound) {
+			problem.NotFound(c, i18n.CategoryFeesNotFound)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error eliminando cargos por servicio", "error", err)
+		problem.Internal(c, i18n.CategoryFeesDeleteFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.CategoryFeesDeleted),
+	})
+}
+
+// taxCountry returns the :country parameter in upper case, responding with
+// a problem if it is not an ISO 3166-1 alpha-2 code.
+func taxCountry(c *gin.Context) (string, bool) {
+	country := strings.ToUpper(c.Param("country"))
+	if !validation.Valid(country, "iso3166_1_alpha2") {
+		problem.BadRequest(c, i18n.TaxCountryInvalid)
+		return "", false
+	}
+	return country, true
+}
+
+// loadCharges returns the fees of the event's category and the taxes of the
+// country of its venue. Events without a venue, or in a country without
+// taxes, are charged no taxes and their charges say why, so the breakdown
+// is never mistaken for a taxed one. It responds with a problem and returns
+// false if they cannot be read.
+func loadCharges(c *gin.Context, store *db.DynamoClient, event *model.Event) (pricing.Charges, bool) {
+	ctx := c.Request.Context()
+	charges := pricing.Charges{TaxStatus: pricing.TaxStatusNoJurisdiction}
+
+	fees, err := store.GetCategoryFees(ctx, event.CategoryID.String())
+	switch {
+	case err == nil:
+		charges.Fees = fees.Fees
+	case !errors.Is(err, db.ErrCategoryFeesNotFound):
+		slog.ErrorContext(ctx, "error obteniendo cargos por servicio", "error", err)
+		problem.Internal(c, i18n.ChargesLoadFailed)
+		return pricing.Charges{}, false
+	}
+
+	if event.VenueID == nil {
+		return charges, true
+	}
+	venue, err := store.GetVenueByID(ctx, event.VenueID.String())
+	if errors.Is(err, db.ErrVenueNotFound) {
+		return charges, true
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "error obteniendo recinto", "error", err)
+		problem.Internal(c, i18n.ChargesLoadFailed)
+		return pricing.Charges{}, false
+	}
+
+	charges.Country = venue.Country
+	jurisdiction, err := store.GetTaxJurisdiction(ctx, venue.Country)
+	switch {
+	case err == nil:
+		charges.Taxes = jurisdiction.Taxes
+		charges.TaxFees = jurisdiction.TaxFees
+		charges.TaxStatus = pricing.TaxStatusTaxed
+	case errors.Is(err, db.ErrTaxJurisdictionNotFound):
+		charges.TaxStatus = pricing.TaxStatusNoRates
+	default:
+		slog.ErrorContext(ctx, "error obteniendo impuestos", "error", err)
+		problem.Internal(c, i18n.ChargesLoadFailed)
+		return pricing.Charges{}, false
+	}
+	return charges, true
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -9,20 +11,33 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/auth"
 	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/geo"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
 	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/pricing"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
 	"github.com/jhonathanssegura/ticket-events/internal/queue"
+	"github.com/jhonathanssegura/ticket-events/internal/storage"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+	"github.com/jhonathanssegura/ticket-events/internal/validation"
 )
 
 type EventHandler struct {
-	DB  *db.DynamoClient
-	SQS *queue.SQSClient
+	DB      *db.DynamoClient
+	SQS     *queue.SQSClient
+	Storage *storage.S3Client
 }
 
-func NewEventHandler(sqs *queue.SQSClient, db *db.DynamoClient) *EventHandler {
-	return &EventHandler{DB: db, SQS: sqs}
+func NewEventHandler(sqs *queue.SQSClient, db *db.DynamoClient, storage *storage.S3Client) *EventHandler {
+	return &EventHandler{DB: db, SQS: sqs, Storage: storage}
 }
 
+// maxRadiusKm bounds ?radius_km so a search touches a bounded number of
+// geo index cells.
+const maxRadiusKm = 100
+
 func (h *EventHandler) ListEvents(c *gin.Context) {
 	categoryID := c.Query("category_id")
 	limitStr := c.Query("limit")
@@ -33,12 +48,23 @@ func (h *EventHandler) ListEvents(c *gin.Context) {
 		}
 	}
 
-	events, err := h.DB.GetEvents(categoryID, limit)
+	if near := c.Query("near"); near != "" {
+		h.listEventsNear(c, near, categoryID, limit)
+		return
+	}
+
+	events, err := h.DB.GetEvents(c.Request.Context(), categoryID, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo eventos", "details": err.Error()})
+		slog.ErrorContext(c.Request.Context(), "error obteniendo eventos", "error", err)
+		problem.Internal(c, i18n.EventListFailed)
 		return
 	}
 
+	if !applyPricingRulesToEvents(c, h.DB, events) {
+		return
+	}
+	localizeEvents(c, events)
+
 	c.JSON(http.StatusOK, gin.H{
 		"events": events,
 		"count":  len(events),
@@ -46,31 +72,92 @@ func (h *EventHandler) ListEvents(c *gin.Context) {
 	})
 }
 
+// listEventsNear lists the events within ?radius_km (default 10) of the
+// near point, nearest first.
+func (h *EventHandler) listEventsNear(c *gin.Context, near, categoryID string, limit int) {
+	lat, lng, err := geo.ParsePoint(near)
+	if err != nil {
+		problem.BadRequest(c, i18n.EventInvalidNear)
+		return
+	}
+	radiusKm := 10.0
+	if radiusStr := c.Query("radius_km"); radiusStr != "" {
+		radiusKm, err = strconv.ParseFloat(radiusStr, 64)
+		if err != nil || radiusKm <= 0 || radiusKm > maxRadiusKm {
+			problem.BadRequest(c, i18n.EventInvalidRadius)
+			return
+		}
+	}
+
+	events, err := h.DB.GetEventsNear(c.Request.Context(), lat, lng, radiusKm, categoryID)
+	if errors.Is(err, geo.ErrAreaTooLarge) {
+		problem.BadRequest(c, i18n.EventAreaTooLarge)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error buscando eventos cercanos", "error", err)
+		problem.Internal(c, i18n.EventListFailed)
+		return
+	}
+	if len(events) > limit {
+		events = events[:limit]
+	}
+
+	if !applyPricingRulesToEvents(c, h.DB, events) {
+		return
+	}
+	localizeEvents(c, events)
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"count":  len(events),
+		"limit":  limit,
+		"near": gin.H{
+			"latitude":  lat,
+			"longitude": lng,
+			"radius_km": radiusKm,
+		},
+	})
+}
+
 func (h *EventHandler) GetEvent(c *gin.Context) {
 	eventID := c.Param("id")
 	if eventID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de evento requerido"})
+		problem.BadRequest(c, i18n.EventIDRequired)
 		return
 	}
 
-	event, err := h.DB.GetEventByID(eventID)
+	event, err := h.DB.GetEventByID(c.Request.Context(), eventID)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Evento no encontrado"})
+			problem.NotFound(c, i18n.EventNotFound)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo evento", "details": err.Error()})
+		slog.ErrorContext(c.Request.Context(), "error obteniendo evento", "error", err)
+		problem.Internal(c, i18n.EventGetFailed)
+		return
+	}
+	if !applyPricingRules(c, h.DB, event) {
 		return
 	}
+	charges, ok := loadCharges(c, h.DB, event)
+	if !ok {
+		return
+	}
+	breakdown := pricing.New(event, 1)
+	breakdown.ApplyCharges(charges)
+
+	localizeEvent(c, event)
 
-	c.JSON(http.StatusOK, gin.H{"event": event})
+	c.JSON(http.StatusOK, gin.H{
+		"event":           event,
+		"price_breakdown": breakdown,
+	})
 }
 
 func (h *EventHandler) CreateEvent(c *gin.Context) {
 	var req model.CreateEventRequest
-
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos de evento inválidos", "details": err.Error()})
+	if !bindJSON(c, &req, i18n.EventInvalidData) {
 		return
 	}
 
@@ -79,30 +166,42 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 
 	event := &model.Event{
 		ID:          eventID,
+		TenantID:    tenant.FromContext(c.Request.Context()),
 		Name:        req.Name,
 		Description: req.Description,
 		CategoryID:  req.CategoryID,
+		OrganizerID: auth.FromContext(c.Request.Context()).Subject,
 		Location:    req.Location,
-		Date:        req.Date,
+		Latitude:    req.Latitude,
+		Longitude:   req.Longitude,
 		Capacity:    req.Capacity,
-		Price:       req.Price,
+		Price:       *req.Price,
 		Status:      model.EventStatusDraft,
 		ImageURL:    req.ImageURL,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
+	event.Schedule(req.StartsAt, req.EndsAt, req.TimeZone)
+	if req.VenueID != nil {
+		venue, ok := referencedVenue(c, h.DB, *req.VenueID, i18n.EventInvalidData)
+		if !ok {
+			return
+		}
+		event.AtVenue(venue)
+	}
 
-	if err := h.DB.SaveEvent(*event); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creando evento", "details": err.Error()})
+	if err := h.DB.SaveEvent(c.Request.Context(), *event); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error creando evento", "error", err)
+		problem.Internal(c, i18n.EventCreateFailed)
 		return
 	}
 
 	// Send notification to SQS
 	message := fmt.Sprintf("Nuevo evento creado: %s", event.Name)
-	h.SQS.SendMessage(message)
+	h.SQS.SendMessageAsync(c.Request.Context(), message)
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "Evento creado con éxito",
+		"message": i18n.T(c.Request.Context(), i18n.EventCreated),
 		"event":   event,
 	})
 }
@@ -110,87 +209,353 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 func (h *EventHandler) UpdateEvent(c *gin.Context) {
 	eventID := c.Param("id")
 	if eventID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de evento requerido"})
+		problem.BadRequest(c, i18n.EventIDRequired)
 		return
 	}
 
-	existingEvent, err := h.DB.GetEventByID(eventID)
+	existingEvent, err := h.DB.GetEventByID(c.Request.Context(), eventID)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Evento no encontrado"})
+			problem.NotFound(c, i18n.EventNotFound)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo evento", "details": err.Error()})
+		slog.ErrorContext(c.Request.Context(), "error obteniendo evento", "error", err)
+		problem.Internal(c, i18n.EventGetFailed)
 		return
 	}
 
-	var req model.CreateEventRequest
+	if !canManage(auth.FromContext(c.Request.Context()), existingEvent) {
+		problem.Forbidden(c, i18n.EventForbidden)
+		return
+	}
 
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos de actualización inválidos", "details": err.Error()})
+	var req model.UpdateEventRequest
+	if !bindJSON(c, &req, i18n.EventInvalidUpdate) {
 		return
 	}
 
-	// Update fields
-	if req.Name != "" {
-		existingEvent.Name = req.Name
+	if !applyEventUpdate(c, h.DB, existingEvent, req) {
+		return
 	}
-	if req.Description != "" {
-		existingEvent.Description = req.Description
+	// An occurrence edited on its own no longer follows its series
+	if existingEvent.SeriesID != "" {
+		existingEvent.Detached = true
 	}
-	if req.CategoryID != uuid.Nil {
-		existingEvent.CategoryID = req.CategoryID
+
+	existingEvent.UpdatedAt = time.Now()
+
+	if err := h.DB.SaveEvent(c.Request.Context(), *existingEvent); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error actualizando evento", "error", err)
+		problem.Internal(c, i18n.EventUpdateFailed)
+		return
 	}
-	if req.Location != "" {
-		existingEvent.Location = req.Location
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.EventUpdated),
+		"event":   existingEvent,
+	})
+}
+
+func (h *EventHandler) DeleteEvent(c *gin.Context) {
+	eventID := c.Param("id")
+	if eventID == "" {
+		problem.BadRequest(c, i18n.EventIDRequired)
+		return
 	}
-	if !req.Date.IsZero() {
-		existingEvent.Date = req.Date
+
+	event, err := h.DB.GetEventByID(c.Request.Context(), eventID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			problem.NotFound(c, i18n.EventNotFound)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error verificando evento", "error", err)
+		problem.Internal(c, i18n.EventVerifyFailed)
+		return
 	}
-	if req.Capacity > 0 {
-		existingEvent.Capacity = req.Capacity
+
+	if !canManage(auth.FromContext(c.Request.Context()), event) {
+		problem.Forbidden(c, i18n.EventForbidden)
+		return
 	}
-	if req.Price > 0 {
-		existingEvent.Price = req.Price
+
+	if err := h.DB.DeleteEvent(c.Request.Context(), eventID); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error eliminando evento", "error", err)
+		problem.Internal(c, i18n.EventDeleteFailed)
+		return
 	}
-	if req.ImageURL != "" {
-		existingEvent.ImageURL = req.ImageURL
+	// The images go once the event is gone, so a failed delete never leaves
+	// the event pointing at missing objects
+	deleteObjects(c, h.Storage, objectKeys(h.Storage, event))
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), i18n.EventDeleted)})
+}
+
+// ListMyEvents lists the events owned by the caller. Admins may pass
+// organizer_id to list another organizer's events.
+func (h *EventHandler) ListMyEvents(c *gin.Context) {
+	principal := auth.FromContext(c.Request.Context())
+	organizerID := principal.Subject
+	if other := c.Query("organizer_id"); other != "" && other != organizerID {
+		if !principal.HasRole(auth.RoleAdmin) {
+			problem.Forbidden(c, i18n.InsufficientPermissions)
+			return
+		}
+		organizerID = other
 	}
 
-	existingEvent.UpdatedAt = time.Now()
+	limitStr := c.Query("limit")
+	limit := 10 // default limit
+	if limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
 
-	if err := h.DB.SaveEvent(*existingEvent); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error actualizando evento", "details": err.Error()})
+	events, err := h.DB.GetEventsByOrganizer(c.Request.Context(), organizerID, limit)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error obteniendo eventos del organizador", "error", err)
+		problem.Internal(c, i18n.EventListFailed)
 		return
 	}
 
+	if !applyPricingRulesToEvents(c, h.DB, events) {
+		return
+	}
+	localizeEvents(c, events)
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Evento actualizado con éxito",
-		"event":   existingEvent,
+		"events":       events,
+		"count":        len(events),
+		"limit":        limit,
+		"organizer_id": organizerID,
 	})
 }
 
-func (h *EventHandler) DeleteEvent(c *gin.Context) {
+// TransferEvent hands ownership of an event to another organizer.
+func (h *EventHandler) TransferEvent(c *gin.Context) {
 	eventID := c.Param("id")
 	if eventID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de evento requerido"})
+		problem.BadRequest(c, i18n.EventIDRequired)
+		return
+	}
+
+	var req model.TransferEventRequest
+	if !bindJSON(c, &req, i18n.EventInvalidTransfer) {
 		return
 	}
 
-	_, err := h.DB.GetEventByID(eventID)
+	event, err := h.DB.GetEventByID(c.Request.Context(), eventID)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Evento no encontrado"})
+			problem.NotFound(c, i18n.EventNotFound)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error verificando evento", "details": err.Error()})
+		slog.ErrorContext(c.Request.Context(), "error obteniendo evento", "error", err)
+		problem.Internal(c, i18n.EventGetFailed)
+		return
+	}
+
+	if !canManage(auth.FromContext(c.Request.Context()), event) {
+		problem.Forbidden(c, i18n.EventForbidden)
+		return
+	}
+
+	previousOrganizer := event.OrganizerID
+	event.OrganizerID = req.OrganizerID
+	event.UpdatedAt = time.Now()
+
+	if err := h.DB.SaveEvent(c.Request.Context(), *event); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error transfiriendo evento", "error", err)
+		problem.Internal(c, i18n.EventTransferFailed)
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "evento transferido",
+		"event_id", event.ID.String(), "from", previousOrganizer, "to", event.OrganizerID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.EventTransferred),
+		"event":   event,
+	})
+}
+
+// PutEventTranslation adds or replaces the translation of an event for the
+// locale in the path.
+func (h *EventHandler) PutEventTranslation(c *gin.Context) {
+	eventID := c.Param("id")
+	locale, ok := translationLocale(c)
+	if !ok {
+		return
+	}
+
+	var req model.EventTranslationRequest
+	if !bindJSON(c, &req, i18n.TranslationInvalidData) {
+		return
+	}
+
+	event, ok := h.loadManagedEvent(c, eventID)
+	if !ok {
+		return
+	}
+
+	if event.Translations == nil {
+		event.Translations = make(model.Translations)
+	}
+	event.Translations[locale] = model.Translation{Name: req.Name, Description: req.Description}
+	event.UpdatedAt = time.Now()
+
+	if err := h.DB.SaveEvent(c.Request.Context(), *event); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error guardando traducción del evento", "error", err)
+		problem.Internal(c, i18n.EventUpdateFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.TranslationSaved),
+		"event":   event,
+	})
+}
+
+// DeleteEventTranslation removes the translation of an event for the locale
+// in the path.
+func (h *EventHandler) DeleteEventTranslation(c *gin.Context) {
+	eventID := c.Param("id")
+	locale, ok := translationLocale(c)
+	if !ok {
 		return
 	}
 
-	if err := h.DB.DeleteEvent(eventID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error eliminando evento", "details": err.Error()})
+	event, ok := h.loadManagedEvent(c, eventID)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Evento eliminado con éxito"})
+	if _, exists := event.Translations[locale]; !exists {
+		problem.NotFound(c, i18n.TranslationNotFound)
+		return
+	}
+	delete(event.Translations, locale)
+	event.UpdatedAt = time.Now()
+
+	if err := h.DB.SaveEvent(c.Request.Context(), *event); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error eliminando traducción del evento", "error", err)
+		problem.Internal(c, i18n.EventUpdateFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.TranslationDeleted),
+		"event":   event,
+	})
+}
+
+// loadManagedEvent fetches the event and checks the caller may modify it,
+// responding with the appropriate problem otherwise.
+func (h *EventHandler) loadManagedEvent(c *gin.Context, eventID string) (*model.Event, bool) {
+	event, err := h.DB.GetEventByID(c.Request.Context(), eventID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			problem.NotFound(c, i18n.EventNotFound)
+			return nil, false
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo evento", "error", err)
+		problem.Internal(c, i18n.EventGetFailed)
+		return nil, false
+	}
+
+	if !canManage(auth.FromContext(c.Request.Context()), event) {
+		problem.Forbidden(c, i18n.EventForbidden)
+		return nil, false
+	}
+	return event, true
+}
+
+// applyEventUpdate sets the fields present in req on event. It responds with a
+// problem and returns false if the venue does not exist or the merged
+// schedule is invalid.
+func applyEventUpdate(c *gin.Context, store *db.DynamoClient, event *model.Event, req model.UpdateEventRequest) bool {
+	if req.VenueID != nil {
+		venue, ok := referencedVenue(c, store, *req.VenueID, i18n.EventInvalidUpdate)
+		if !ok {
+			return false
+		}
+		// Moving to another venue takes its location, coordinates and time
+		// zone, unless the body sets them below
+		event.VenueID = &venue.ID
+		event.Location = venue.Label()
+		event.Latitude, event.Longitude = venue.Coordinates()
+		event.TimeZone = venue.TimeZone
+	}
+	if req.Name != nil {
+		event.Name = *req.Name
+	}
+	if req.Description != nil {
+		event.Description = *req.Description
+	}
+	if req.CategoryID != nil {
+		event.CategoryID = *req.CategoryID
+	}
+	if req.Location != nil {
+		event.Location = *req.Location
+	}
+	if req.Latitude != nil {
+		event.Latitude, event.Longitude = req.Latitude, req.Longitude
+	}
+	startsAt, endsAt, timeZone := event.StartsAt, event.EndsAt, event.TimeZone
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		endsAt = *req.EndsAt
+	}
+	if req.TimeZone != nil {
+		timeZone = *req.TimeZone
+	}
+	if !endsAt.After(startsAt) {
+		problem.Validation(c, i18n.EventInvalidUpdate, []validation.FieldError{
+			validation.NewFieldError(c.Request.Context(), "ends_at", validation.CodeNotAfter, i18n.ValidationNotAfter, "starts_at"),
+		})
+		return false
+	}
+	event.Schedule(startsAt, endsAt, timeZone)
+	if req.Capacity != nil {
+		event.Capacity = *req.Capacity
+	}
+	if req.Price != nil {
+		event.Price = *req.Price
+	}
+	if req.ImageURL != nil {
+		event.SetImageURL(*req.ImageURL)
+	}
+	if req.Status != nil {
+		if !model.CanTransition(event.Status, *req.Status) {
+			statusConflict(c, event.Status, *req.Status)
+			return false
+		}
+		event.Status = *req.Status
+	}
+	return true
+}
+
+// statusConflict responds that an event or series cannot move from status
+// from to status to.
+func statusConflict(c *gin.Context, from, to string) {
+	if from == "" {
+		from = model.EventStatusDraft
+	}
+	problem.Respond(c, problem.Problem{
+		Type:   problem.TypeConflict,
+		Status: http.StatusConflict,
+		Detail: i18n.T(c.Request.Context(), i18n.EventStatusTransition, from, to),
+	})
+}
+
+// canManage reports whether p may modify event: admins always can, organizers
+// only their own events.
+func canManage(p *auth.Principal, event *model.Event) bool {
+	if p.HasRole(auth.RoleAdmin) {
+		return true
+	}
+	return p != nil && event.OrganizerID != "" && event.OrganizerID == p.Subject
 }
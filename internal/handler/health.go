@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/queue"
+	"github.com/jhonathanssegura/ticket-events/internal/storage"
+)
+
+const (
+	statusUp   = "up"
+	statusDown = "down"
+)
+
+// DependencyStatus is the result of checking a single dependency. The
+// error itself is only logged, since /readyz is public.
+type DependencyStatus struct {
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	// Optional dependencies are reported but do not make the service
+	// unready.
+	Optional bool `json:"optional,omitempty"`
+}
+
+// ReadinessReport is the body returned by /readyz.
+type ReadinessReport struct {
+	Status       string                      `json:"status"`
+	CheckedAt    time.Time                   `json:"checked_at"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+type dependencyCheck struct {
+	ping     func(context.Context) error
+	optional bool
+}
+
+type HealthHandler struct {
+	DB      *db.DynamoClient
+	SQS     *queue.SQSClient
+	Storage *storage.S3Client
+
+	// Timeout bounds each dependency check; CacheTTL controls how long a
+	// report is reused so orchestrator probes do not hammer AWS.
+	Timeout  time.Duration
+	CacheTTL time.Duration
+
+	mu     sync.Mutex
+	cached *ReadinessReport
+}
+
+func NewHealthHandler(sqs *queue.SQSClient, db *db.DynamoClient, storage *storage.S3Client, timeout, cacheTTL time.Duration) *HealthHandler {
+	return &HealthHandler{DB: db, SQS: sqs, Storage: storage, Timeout: timeout, CacheTTL: cacheTTL}
+}
+
+// Healthz reports that the process is alive. It never touches dependencies.
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz reports whether DynamoDB tables and the SQS queue are reachable. The
+// image bucket is reported too, but only image uploads need it, so it never
+// takes the service out of rotation.
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.readiness(c.Request.Context())
+
+	code := http.StatusOK
+	if report.Status != statusUp {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, report)
+}
+
+func (h *HealthHandler) readiness(ctx context.Context) ReadinessReport {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cached != nil && time.Since(h.cached.CheckedAt) < h.CacheTTL {
+		return *h.cached
+	}
+
+	checks := map[string]dependencyCheck{
+		"dynamodb:" + db.EventsTable: {ping: func(ctx context.Context) error {
+			return h.DB.PingTable(ctx, db.EventsTable)
+		}},
+		"dynamodb:" + db.CategoriesTable: {ping: func(ctx context.Context) error {
+			return h.DB.PingTable(ctx, db.CategoriesTable)
+		}},
+		"sqs": {ping: h.SQS.Ping},
+		"s3":  {ping: h.Storage.Ping, optional: true},
+	}
+
+	report := ReadinessReport{
+		Status:       statusUp,
+		CheckedAt:    time.Now(),
+		Dependencies: make(map[string]DependencyStatus, len(checks)),
+	}
+
+	var (
+		wg      sync.WaitGroup
+		resultM sync.Mutex
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check dependencyCheck) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, h.Timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.ping(checkCtx)
+			status := DependencyStatus{Status: statusUp, LatencyMS: time.Since(start).Milliseconds(), Optional: check.optional}
+			if err != nil {
+				status.Status = statusDown
+				slog.WarnContext(ctx, "dependencia no disponible", "dependency", name, "error", err)
+			}
+
+			resultM.Lock()
+			report.Dependencies[name] = status
+			if err != nil && !check.optional {
+				report.Status = statusDown
+			}
+			resultM.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	h.cached = &report
+	return report
+}
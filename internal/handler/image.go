@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/auth"
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/imaging"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/storage"
+)
+
+// multipartOverhead is the room left in a request, beyond the image itself,
+// for the multipart boundaries and headers.
+const multipartOverhead = 64 << 10
+
+type ImageHandler struct {
+	DB      *db.DynamoClient
+	Storage *storage.S3Client
+	// MaxMB bounds the size of an uploaded image, in megabytes.
+	MaxMB int
+}
+
+func NewImageHandler(db *db.DynamoClient, storage *storage.S3Client, maxMB int) *ImageHandler {
+	return &ImageHandler{DB: db, Storage: storage, MaxMB: maxMB}
+}
+
+// UploadEventImage takes a JPEG, PNG, WebP or GIF image in the multipart
+// field "image", stores it with its resized variants and makes it the image
+// of the event. The previous uploaded image, if any, is deleted.
+func (h *ImageHandler) UploadEventImage(c *gin.Context) {
+	event, ok := h.loadManagedEvent(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	data, ok := h.readImage(c)
+	if !ok {
+		return
+	}
+
+	img, err := imaging.Process(data)
+	switch {
+	case errors.Is(err, imaging.ErrUnsupportedType):
+		problem.UnsupportedType(c, i18n.ImageUnsupportedType)
+		return
+	case errors.Is(err, imaging.ErrTooManyPixels):
+		problem.Respond(c, problem.Problem{
+			Type:   problem.TypeTooLarge,
+			Status: http.StatusRequestEntityTooLarge,
+			Detail: i18n.T(c.Request.Context(), i18n.ImageTooManyPixels, imaging.MaxMegapixels),
+		})
+		return
+	case err != nil:
+		slog.WarnContext(c.Request.Context(), "imagen de evento ilegible", "error", err)
+		problem.Unprocessable(c, i18n.ImageInvalid)
+		return
+	}
+
+	// Every upload gets its own prefix, so URLs never point at a different
+	// image and can be cached forever
+	prefix := path.Join(eventImagePrefix(event), uuid.NewString())
+	var uploaded []string
+	put := func(name string, file imaging.File) (string, error) {
+		key := prefix + "/" + name + "." + file.Extension
+		url, err := h.Storage.Put(c.Request.Context(), key, file.ContentType, file.Data)
+		if err == nil {
+			uploaded = append(uploaded, key)
+		}
+		return url, err
+	}
+
+	imageURL, err := put("original", img.Original)
+	variants := make(map[string]string, len(img.Variants))
+	for name, file := range img.Variants {
+		if err == nil {
+			variants[name], err = put(name, file)
+		}
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error subiendo imagen del evento", "error", err)
+		deleteObjects(c, h.Storage, uploaded)
+		problem.Internal(c, i18n.ImageUploadFailed)
+		return
+	}
+
+	previous := objectKeys(h.Storage, event)
+	event.ImageURL = imageURL
+	event.ImageVariants = variants
+	// An occurrence edited on its own no longer follows its series
+	if event.SeriesID != "" {
+		event.Detached = true
+	}
+	event.UpdatedAt = time.Now()
+
+	if err := h.DB.SaveEvent(c.Request.Context(), *event); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error actualizando evento", "error", err)
+		deleteObjects(c, h.Storage, uploaded)
+		problem.Internal(c, i18n.EventUpdateFailed)
+		return
+	}
+	deleteObjects(c, h.Storage, previous)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.EventImageUploaded),
+		"event":   event,
+	})
+}
+
+// readImage returns the content of the "image" field of the multipart body,
+// responding with a problem if it is missing or over MaxMB.
+func (h *ImageHandler) readImage(c *gin.Context) ([]byte, bool) {
+	maxBytes := int64(h.MaxMB) << 20
+	tooLarge := func() ([]byte, bool) {
+		problem.Respond(c, problem.Problem{
+			Type:   problem.TypeTooLarge,
+			Status: http.StatusRequestEntityTooLarge,
+			Detail: i18n.T(c.Request.Context(), i18n.ImageTooLarge, h.MaxMB),
+		})
+		return nil, false
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+multipartOverhead)
+	header, err := c.FormFile("image")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return tooLarge()
+		}
+		problem.BadRequest(c, i18n.ImageRequired)
+		return nil, false
+	}
+	if header.Size > maxBytes {
+		return tooLarge()
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error abriendo imagen del evento", "error", err)
+		problem.Internal(c, i18n.ImageUploadFailed)
+		return nil, false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error leyendo imagen del evento", "error", err)
+		problem.Internal(c, i18n.ImageUploadFailed)
+		return nil, false
+	}
+	return data, true
+}
+
+// objectKeys returns the keys of the image and variants uploaded for event.
+// URLs set by hand are skipped even if they point at the bucket, since they
+// may belong to another event.
+func objectKeys(store *storage.S3Client, event *model.Event) []string {
+	var keys []string
+	prefix := eventImagePrefix(event) + "/"
+	for _, url := range append([]string{event.ImageURL}, slices.Collect(maps.Values(event.ImageVariants))...) {
+		if key, ok := store.Key(url); ok && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// eventImagePrefix is the prefix of the keys of the images uploaded for
+// event.
+func eventImagePrefix(event *model.Event) string {
+	return path.Join("events", event.TenantID, event.ID.String())
+}
+
+// deleteObjects removes keys from storage. Failures leave orphan objects
+// behind but do not fail the request.
+func deleteObjects(c *gin.Context, store *storage.S3Client, keys []string) {
+	if err := store.Delete(c.Request.Context(), keys...); err != nil {
+		slog.WarnContext(c.Request.Context(), "error eliminando imágenes", "error", err)
+	}
+}
+
+func (h *ImageHandler) loadManagedEvent(c *gin.Context, eventID string) (*model.Event, bool) {
+	event, err := h.DB.GetEventByID(c.Request.Context(), eventID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			problem.NotFound(c, i18n.EventNotFound)
+			return nil, false
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo evento", "error", err)
+		problem.Internal(c, i18n.EventGetFailed)
+		return nil, false
+	}
+
+	if !canManage(auth.FromContext(c.Request.Context()), event) {
+		problem.Forbidden(c, i18n.EventForbidden)
+		return nil, false
+	}
+	return event, true
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+)
+
+// localizeEvent applies the translation that best matches the request's
+// ?lang or Accept-Language, keeping the original content when none fits.
+func localizeEvent(c *gin.Context, event *model.Event) {
+	if locale, ok := i18n.Match(i18n.Preference(c.Request), event.Translations.Locales()); ok {
+		event.Localize(locale)
+	}
+}
+
+func localizeEvents(c *gin.Context, events []model.Event) {
+	for i := range events {
+		localizeEvent(c, &events[i])
+	}
+}
+
+func localizeCategory(c *gin.Context, category *model.Category) {
+	if locale, ok := i18n.Match(i18n.Preference(c.Request), category.Translations.Locales()); ok {
+		category.Localize(locale)
+	}
+}
+
+// translationLocale returns the canonical form of the :locale path parameter.
+// If it is not a valid BCP 47 tag it responds 400 and returns false.
+func translationLocale(c *gin.Context) (string, bool) {
+	locale, err := i18n.Canonical(c.Param("locale"))
+	if err != nil {
+		problem.BadRequest(c, i18n.LocaleInvalid)
+		return "", false
+	}
+	return locale, true
+}
@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/pricing"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/service"
+	"github.com/jhonathanssegura/ticket-events/internal/validation"
+)
+
+// maxPriceHistory bounds the price changes returned at once.
+const maxPriceHistory = 100
+
+// PutPricingRules defines or replaces the pricing rules of the event and
+// returns the price they give now.
+func (h *EventHandler) PutPricingRules(c *gin.Context) {
+	event, ok := h.loadManagedEvent(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	var req model.PutPricingRulesRequest
+	if !bindJSON(c, &req, i18n.PricingRulesInvalidData) {
+		return
+	}
+	if req.Floor != nil && req.Ceiling != nil && *req.Ceiling < *req.Floor {
+		problem.Validation(c, i18n.PricingRulesInvalidData, []validation.FieldError{
+			validation.NewFieldError(c.Request.Context(), "ceiling", validation.CodeTooSmall, i18n.ValidationTooSmall, strconv.FormatFloat(*req.Floor, 'f', -1, 64)),
+		})
+		return
+	}
+
+	existing, err := h.DB.GetPricingRules(c.Request.Context(), event.ID.String())
+	if err != nil && !errors.Is(err, db.ErrPricingRulesNotFound) {
+		slog.ErrorContext(c.Request.Context(), "error obteniendo reglas de precio", "error", err)
+		problem.Internal(c, i18n.PricingRulesGetFailed)
+		return
+	}
+
+	now := time.Now()
+	rules := &model.PricingRules{
+		EventID:          event.ID,
+		TenantID:         event.TenantID,
+		TimeSteps:        req.TimeSteps,
+		SellThroughSteps: req.SellThroughSteps,
+		Floor:            req.Floor,
+		Ceiling:          req.Ceiling,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if rules.TimeSteps == nil {
+		rules.TimeSteps = []model.TimeStep{}
+	}
+	if rules.SellThroughSteps == nil {
+		rules.SellThroughSteps = []model.SellThroughStep{}
+	}
+	if existing != nil {
+		rules.CreatedAt = existing.CreatedAt
+		rules.CurrentPrice = existing.CurrentPrice
+	}
+
+	adjustment, err := service.EvaluatePricing(c.Request.Context(), h.DB, event, rules, now)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error calculando precio", "error", err)
+		problem.Internal(c, i18n.PriceEvaluateFailed)
+		return
+	}
+	var change *model.PriceChange
+	if rules.CurrentPrice == nil || *rules.CurrentPrice != adjustment.Price {
+		change = service.NewPriceChange(event, adjustment, rules.CurrentPrice, model.PriceChangeRules, now)
+		rules.CurrentPrice = &adjustment.Price
+	}
+
+	if err := h.DB.SavePricingRules(c.Request.Context(), *rules, change); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error guardando reglas de precio", "error", err)
+		problem.Internal(c, i18n.PricingRulesSaveFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       i18n.T(c.Request.Context(), i18n.PricingRulesSaved),
+		"pricing_rules": rules,
+		"price":         adjustment,
+	})
+}
+
+// GetPricingRules returns the pricing rules of the event and the price they
+// give now.
+func (h *EventHandler) GetPricingRules(c *gin.Context) {
+	event, ok := h.loadManagedEvent(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	rules, ok := h.loadPricingRules(c, event)
+	if !ok {
+		return
+	}
+
+	adjustment, err := service.EvaluatePricing(c.Request.Context(), h.DB, event, rules, time.Now())
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error calculando precio", "error", err)
+		problem.Internal(c, i18n.PriceEvaluateFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pricing_rules": rules,
+		"price":         adjustment,
+	})
+}
+
+// DeletePricingRules removes the pricing rules of the event, which goes back
+// to its base price.
+func (h *EventHandler) DeletePricingRules(c *gin.Context) {
+	event, ok := h.loadManagedEvent(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	rules, ok := h.loadPricingRules(c, event)
+	if !ok {
+		return
+	}
+
+	var change *model.PriceChange
+	if rules.CurrentPrice != nil && *rules.CurrentPrice != event.Price {
+		base := pricing.Adjustment{BasePrice: event.Price, Price: event.Price}
+		change = service.NewPriceChange(event, base, rules.CurrentPrice, model.PriceChangeRulesRemoved, time.Now())
+	}
+
+	if err := h.DB.DeletePricingRules(c.Request.Context(), event.ID.String(), change); err != nil {
+		if errors.Is(err, db.ErrPricingRulesNotFound) {
+			problem.NotFound(c, i18n.PricingRulesNotFound)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error eliminando reglas de precio", "error", err)
+		problem.Internal(c, i18n.PricingRulesDeleteFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.PricingRulesDeleted),
+	})
+}
+
+// ListPriceHistory returns the latest changes of the effective price of the
+// event, newest first.
+func (h *EventHandler) ListPriceHistory(c *gin.Context) {
+	event, ok := h.loadManagedEvent(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	limit := 20
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = min(l, maxPriceHistory)
+	}
+
+	changes, err := h.DB.ListPriceChanges(c.Request.Context(), event.ID.String(), limit)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error obteniendo historial de precios", "error", err)
+		problem.Internal(c, i18n.PriceHistoryFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"price_changes": changes,
+		"count":         len(changes),
+		"limit":         limit,
+	})
+}
+
+func (h *EventHandler) loadPricingRules(c *gin.Context, event *model.Event) (*model.PricingRules, bool) {
+	rules, err := h.DB.GetPricingRules(c.Request.Context(), event.ID.String())
+	if err != nil {
+		if errors.Is(err, db.ErrPricingRulesNotFound) {
+			problem.NotFound(c, i18n.PricingRulesNotFound)
+			return nil, false
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo reglas de precio", "error", err)
+		problem.Internal(c, i18n.PricingRulesGetFailed)
+		return nil, false
+	}
+	return rules, true
+}
+
+// applyPricingRules sets the effective price of event from its pricing
+// rules, if it has any. It responds with a problem and returns false if the
+// price cannot be computed.
+func applyPricingRules(c *gin.Context, store *db.DynamoClient, event *model.Event) bool {
+	ctx := c.Request.Context()
+	rules, err := store.GetPricingRules(ctx, event.ID.String())
+	if errors.Is(err, db.ErrPricingRulesNotFound) {
+		return true
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "error obteniendo reglas de precio", "error", err)
+		problem.Internal(c, i18n.PriceEvaluateFailed)
+		return false
+	}
+
+	adjustment, err := service.EvaluatePricing(ctx, store, event, rules, time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "error calculando precio", "error", err)
+		problem.Internal(c, i18n.PriceEvaluateFailed)
+		return false
+	}
+	event.EffectivePrice = &adjustment.Price
+	return true
+}
+
+// applyPricingRulesToEvents sets the effective price of every event of a
+// listing that has pricing rules, reading the rules and sales of all of them
+// in batches. It responds with a problem and returns false on failure.
+func applyPricingRulesToEvents(c *gin.Context, store *db.DynamoClient, events []model.Event) bool {
+	if len(events) == 0 {
+		return true
+	}
+	ctx := c.Request.Context()
+
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.ID.String()
+	}
+	rules, err := store.GetPricingRulesByEvents(ctx, ids)
+	if err != nil {
+		slog.ErrorContext(ctx, "error obteniendo reglas de precio", "error", err)
+		problem.Internal(c, i18n.PriceEvaluateFailed)
+		return false
+	}
+
+	// Sales are only read for events with a rule depending on them
+	var selling []string
+	for id, r := range rules {
+		if len(r.SellThroughSteps) > 0 {
+			selling = append(selling, id)
+		}
+	}
+	sold, err := store.GetSoldCounts(ctx, selling)
+	if err != nil {
+		slog.ErrorContext(ctx, "error obteniendo asientos vendidos", "error", err)
+		problem.Internal(c, i18n.PriceEvaluateFailed)
+		return false
+	}
+
+	now := time.Now()
+	for i := range events {
+		id := events[i].ID.String()
+		if r, ok := rules[id]; ok {
+			price := pricing.Evaluate(&events[i], r, sold[id], now).Price
+			events[i].EffectivePrice = &price
+		}
+	}
+	return true
+}
@@ -0,0 +1,501 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/auth"
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/pricing"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/validation"
+)
+
+type PromoHandler struct {
+	DB *db.DynamoClient
+}
+
+func NewPromoHandler(db *db.DynamoClient) *PromoHandler {
+	return &PromoHandler{DB: db}
+}
+
+// CreatePromoCode creates an active code. Codes of an event can be created
+// by its organizer; codes of a category or of every event only by an admin.
+func (h *PromoHandler) CreatePromoCode(c *gin.Context) {
+	var req model.CreatePromoCodeRequest
+	if !bindJSON(c, &req, i18n.PromoCodeInvalidData) {
+		return
+	}
+
+	principal := auth.FromContext(c.Request.Context())
+	now := time.Now()
+	promo := &model.PromoCode{
+		Code:               model.NormalizePromoCode(req.Code),
+		Kind:               req.Kind,
+		Value:              req.Value,
+		EventID:            req.EventID,
+		CategoryID:         req.CategoryID,
+		StartsAt:           req.StartsAt,
+		EndsAt:             req.EndsAt,
+		MaxUses:            req.MaxUses,
+		MaxUsesPerCustomer: req.MaxUsesPerCustomer,
+		Active:             true,
+		CreatedBy:          principal.Subject,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if errs := checkPromoCode(c.Request.Context(), promo); len(errs) > 0 {
+		problem.Validation(c, i18n.PromoCodeInvalidData, errs)
+		return
+	}
+
+	if promo.EventID != nil {
+		event, err := h.DB.GetEventByID(c.Request.Context(), promo.EventID.String())
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				problem.Validation(c, i18n.PromoCodeInvalidData, []validation.FieldError{
+					validation.NewFieldError(c.Request.Context(), "event_id", validation.CodeNotFound, i18n.ValidationEventNotFound),
+				})
+				return
+			}
+			slog.ErrorContext(c.Request.Context(), "error obteniendo evento", "error", err)
+			problem.Internal(c, i18n.EventGetFailed)
+			return
+		}
+		if !canManage(principal, event) {
+			problem.Forbidden(c, i18n.PromoCodeForbidden)
+			return
+		}
+	} else if !principal.HasRole(auth.RoleAdmin) {
+		problem.Forbidden(c, i18n.PromoCodeForbidden)
+		return
+	}
+
+	if err := h.DB.CreatePromoCode(c.Request.Context(), *promo); err != nil {
+		if errors.Is(err, db.ErrPromoCodeExists) {
+			problem.Respond(c, problem.Problem{
+				Type:   problem.TypeConflict,
+				Status: http.StatusConflict,
+				Detail: i18n.T(c.Request.Context(), i18n.PromoCodeExists, promo.Code),
+			})
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error creando código promocional", "error", err)
+		problem.Internal(c, i18n.PromoCodeCreateFailed)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    i18n.T(c.Request.Context(), i18n.PromoCodeCreated),
+		"promo_code": promo,
+	})
+}
+
+// ListPromoCodes lists the tenant's codes to admins. Organizers must pass
+// ?event_id and only see the codes of that event, if they manage it.
+func (h *PromoHandler) ListPromoCodes(c *gin.Context) {
+	eventID := c.Query("event_id")
+	if !auth.FromContext(c.Request.Context()).HasRole(auth.RoleAdmin) {
+		if eventID == "" {
+			problem.BadRequest(c, i18n.PromoCodeEventRequired)
+			return
+		}
+		if _, ok := h.loadManagedEvent(c, eventID); !ok {
+			return
+		}
+	}
+
+	promos, err := h.DB.ListPromoCodes(c.Request.Context(), eventID)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error obteniendo códigos promocionales", "error", err)
+		problem.Internal(c, i18n.PromoCodeListFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"promo_codes": promos,
+		"count":       len(promos),
+	})
+}
+
+func (h *PromoHandler) GetPromoCode(c *gin.Context) {
+	promo, ok := h.loadPromoCode(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"promo_code": promo})
+}
+
+// UpdatePromoCode changes the value, validity window, limits or active flag
+// of a code. Lowering a limit below the uses so far exhausts the code.
+func (h *PromoHandler) UpdatePromoCode(c *gin.Context) {
+	promo, ok := h.loadPromoCode(c)
+	if !ok {
+		return
+	}
+
+	var req model.UpdatePromoCodeRequest
+	if !bindJSON(c, &req, i18n.PromoCodeInvalidUpdate) {
+		return
+	}
+	if req.Value != nil {
+		promo.Value = *req.Value
+	}
+	if req.StartsAt != nil {
+		promo.StartsAt = req.StartsAt
+	}
+	if req.EndsAt != nil {
+		promo.EndsAt = req.EndsAt
+	}
+	if req.MaxUses != nil {
+		promo.MaxUses = *req.MaxUses
+	}
+	if req.MaxUsesPerCustomer != nil {
+		promo.MaxUsesPerCustomer = *req.MaxUsesPerCustomer
+	}
+	if req.Active != nil {
+		promo.Active = *req.Active
+	}
+	if errs := checkPromoCode(c.Request.Context(), promo); len(errs) > 0 {
+		problem.Validation(c, i18n.PromoCodeInvalidUpdate, errs)
+		return
+	}
+	promo.UpdatedAt = time.Now()
+
+	if err := h.DB.UpdatePromoCode(c.Request.Context(), *promo); err != nil {
+		if errors.Is(err, db.ErrPromoCodeNotFound) {
+			problem.NotFound(c, i18n.PromoCodeNotFound)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error actualizando código promocional", "error", err)
+		problem.Internal(c, i18n.PromoCodeUpdateFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    i18n.T(c.Request.Context(), i18n.PromoCodeUpdated),
+		"promo_code": promo,
+	})
+}
+
+func (h *PromoHandler) DeletePromoCode(c *gin.Context) {
+	promo, ok := h.loadPromoCode(c)
+	if !ok {
+		return
+	}
+
+	if err := h.DB.DeletePromoCode(c.Request.Context(), promo.Code); err != nil {
+		if errors.Is(err, db.ErrPromoCodeNotFound) {
+			problem.NotFound(c, i18n.PromoCodeNotFound)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error eliminando código promocional", "error", err)
+		problem.Internal(c, i18n.PromoCodeDeleteFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.PromoCodeDeleted),
+	})
+}
+
+// RedeemPromoCode uses the code for an order of the caller, counting it
+// against the code's limits, and returns the discounted quote. Concurrent
+// redemptions never exceed the limits.
+func (h *PromoHandler) RedeemPromoCode(c *gin.Context) {
+	var req model.RedeemPromoCodeRequest
+	if !bindJSON(c, &req, i18n.QuoteInvalidData) {
+		return
+	}
+
+	promo, err := h.DB.GetPromoCode(c.Request.Context(), model.NormalizePromoCode(c.Param("code")))
+	if err != nil {
+		if errors.Is(err, db.ErrPromoCodeNotFound) {
+			problem.NotFound(c, i18n.PromoCodeNotFound)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo código promocional", "error", err)
+		problem.Internal(c, i18n.PromoCodeGetFailed)
+		return
+	}
+
+	event, err := h.DB.GetEventByID(c.Request.Context(), req.EventID.String())
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			problem.NotFound(c, i18n.EventNotFound)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo evento", "error", err)
+		problem.Internal(c, i18n.EventGetFailed)
+		return
+	}
+	if event.Status != model.EventStatusPublished {
+		problem.Conflict(c, i18n.QuoteNotOnSale)
+		return
+	}
+
+	customer := auth.FromContext(c.Request.Context()).Subject
+	now := time.Now()
+	if !checkRedeemable(c, h.DB, promo, event, customer, now) {
+		return
+	}
+	if !applyPricingRules(c, h.DB, event) {
+		return
+	}
+	charges, ok := loadCharges(c, h.DB, event)
+	if !ok {
+		return
+	}
+
+	quote, ok := newQuote(c, h.DB, event, req.Quantity, req.Seats)
+	if !ok {
+		return
+	}
+	quote.ApplyPromo(promo)
+	quote.ApplyCharges(charges)
+	redemption := &model.Redemption{
+		ID:        uuid.New(),
+		Code:      promo.Code,
+		EventID:   event.ID,
+		Customer:  customer,
+		Quantity:  quote.Quantity,
+		Discount:  quote.Discount.Float64(),
+		Total:     quote.Total.Float64(),
+		CreatedAt: now,
+	}
+
+	if err := h.DB.RedeemPromoCode(c.Request.Context(), *promo, *redemption); err != nil {
+		switch {
+		case errors.Is(err, db.ErrPromoCustomerLimit):
+			problem.Unprocessable(c, i18n.PromoCodeCustomerLimit)
+		case errors.Is(err, db.ErrPromoCodeUnavailable):
+			// The code changed since it was read; explain why if we can
+			if current, err := h.DB.GetPromoCode(c.Request.Context(), promo.Code); err == nil {
+				promo = current
+			}
+			detail := promoCodeProblem(promo, event, now)
+			if detail == "" {
+				detail = i18n.PromoCodeExhausted
+			}
+			problem.Unprocessable(c, detail)
+		default:
+			slog.ErrorContext(c.Request.Context(), "error canjeando código promocional", "error", err)
+			problem.Internal(c, i18n.PromoCodeRedeemFailed)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    i18n.T(c.Request.Context(), i18n.PromoCodeRedeemed),
+		"redemption": redemption,
+		"quote":      quote,
+	})
+}
+
+// QuoteEvent prices a quantity of tickets of a published event at its
+// effective price, with its fees and taxes, applying a promo code if given.
+// It does not use up the code. Anonymous callers are not checked against the
+// per-customer limit.
+func (h *PromoHandler) QuoteEvent(c *gin.Context) {
+	event, err := h.DB.GetEventByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			problem.NotFound(c, i18n.EventNotFound)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo evento", "error", err)
+		problem.Internal(c, i18n.EventGetFailed)
+		return
+	}
+	if event.Status != model.EventStatusPublished {
+		problem.Conflict(c, i18n.QuoteNotOnSale)
+		return
+	}
+
+	var req model.QuoteRequest
+	if !bindJSON(c, &req, i18n.QuoteInvalidData) {
+		return
+	}
+	if !applyPricingRules(c, h.DB, event) {
+		return
+	}
+	charges, ok := loadCharges(c, h.DB, event)
+	if !ok {
+		return
+	}
+
+	quote, ok := newQuote(c, h.DB, event, req.Quantity, req.Seats)
+	if !ok {
+		return
+	}
+	quote.ApplyCharges(charges)
+	if code := model.NormalizePromoCode(req.Code); code != "" {
+		promo, err := h.DB.GetPromoCode(c.Request.Context(), code)
+		if err != nil {
+			if errors.Is(err, db.ErrPromoCodeNotFound) {
+				problem.Unprocessable(c, i18n.PromoCodeInvalid)
+				return
+			}
+			slog.ErrorContext(c.Request.Context(), "error obteniendo código promocional", "error", err)
+			problem.Internal(c, i18n.PromoCodeGetFailed)
+			return
+		}
+		var customer string
+		if principal := auth.FromContext(c.Request.Context()); principal != nil {
+			customer = principal.Subject
+		}
+		if !checkRedeemable(c, h.DB, promo, event, customer, time.Now()) {
+			return
+		}
+		quote.ApplyPromo(promo)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"quote": quote})
+}
+
+// newQuote quotes quantity tickets of event or, if it has a seat map, the
+// seats with the given IDs at the prices of their zones. It responds with a
+// problem if the request does not fit the event's seating.
+func newQuote(c *gin.Context, store *db.DynamoClient, event *model.Event, quantity int, seatIDs []string) (*pricing.Quote, bool) {
+	ctx := c.Request.Context()
+	seatMap, err := store.GetSeatMap(ctx, event.ID.String())
+	switch {
+	case errors.Is(err, db.ErrSeatMapNotFound):
+		if len(seatIDs) > 0 {
+			problem.Validation(c, i18n.QuoteInvalidData, []validation.FieldError{
+				validation.NewFieldError(ctx, "seats", validation.CodeInvalid, i18n.ValidationNoSeatMap),
+			})
+			return nil, false
+		}
+		return pricing.New(event, quantity), true
+	case err != nil:
+		slog.ErrorContext(ctx, "error obteniendo plano de asientos", "error", err)
+		problem.Internal(c, i18n.SeatMapGetFailed)
+		return nil, false
+	}
+
+	// Seats of different zones have different prices, so they must be named
+	if len(seatIDs) == 0 {
+		problem.Validation(c, i18n.QuoteInvalidData, []validation.FieldError{
+			validation.NewFieldError(ctx, "seats", validation.CodeRequired, i18n.ValidationRequired),
+		})
+		return nil, false
+	}
+	seats, ok := mapSeats(c, seatMap, seatIDs, i18n.QuoteInvalidData)
+	if !ok {
+		return nil, false
+	}
+	return pricing.NewForSeats(event, seats), true
+}
+
+// loadPromoCode fetches the code named by the :code parameter and checks
+// the caller may manage it, responding with the appropriate problem
+// otherwise.
+func (h *PromoHandler) loadPromoCode(c *gin.Context) (*model.PromoCode, bool) {
+	promo, err := h.DB.GetPromoCode(c.Request.Context(), model.NormalizePromoCode(c.Param("code")))
+	if err != nil {
+		if errors.Is(err, db.ErrPromoCodeNotFound) {
+			problem.NotFound(c, i18n.PromoCodeNotFound)
+			return nil, false
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo código promocional", "error", err)
+		problem.Internal(c, i18n.PromoCodeGetFailed)
+		return nil, false
+	}
+
+	principal := auth.FromContext(c.Request.Context())
+	if principal.HasRole(auth.RoleAdmin) {
+		return promo, true
+	}
+	if promo.EventID == nil {
+		problem.Forbidden(c, i18n.PromoCodeForbidden)
+		return nil, false
+	}
+	if _, ok := h.loadManagedEvent(c, promo.EventID.String()); !ok {
+		return nil, false
+	}
+	return promo, true
+}
+
+func (h *PromoHandler) loadManagedEvent(c *gin.Context, eventID string) (*model.Event, bool) {
+	event, err := h.DB.GetEventByID(c.Request.Context(), eventID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			problem.NotFound(c, i18n.EventNotFound)
+			return nil, false
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo evento", "error", err)
+		problem.Internal(c, i18n.EventGetFailed)
+		return nil, false
+	}
+
+	if !canManage(auth.FromContext(c.Request.Context()), event) {
+		problem.Forbidden(c, i18n.PromoCodeForbidden)
+		return nil, false
+	}
+	return event, true
+}
+
+// checkPromoCode validates the rules across fields of a code, also after
+// merging an update.
+func checkPromoCode(ctx context.Context, promo *model.PromoCode) []validation.FieldError {
+	var errs []validation.FieldError
+	if promo.Kind == model.PromoPercentage && promo.Value > 100 {
+		errs = append(errs, validation.NewFieldError(ctx, "value", validation.CodeTooLarge, i18n.ValidationPercentage))
+	}
+	if promo.StartsAt != nil && promo.EndsAt != nil && !promo.EndsAt.After(*promo.StartsAt) {
+		errs = append(errs, validation.NewFieldError(ctx, "ends_at", validation.CodeNotAfter, i18n.ValidationNotAfter, "starts_at"))
+	}
+	return errs
+}
+
+// checkRedeemable verifies that customer may use promo on event at now. An
+// empty customer skips the per-customer limit. It responds with the reason
+// and returns false if the code cannot be used.
+func checkRedeemable(c *gin.Context, store *db.DynamoClient, promo *model.PromoCode, event *model.Event, customer string, now time.Time) bool {
+	if detail := promoCodeProblem(promo, event, now); detail != "" {
+		problem.Unprocessable(c, detail)
+		return false
+	}
+	if customer == "" || promo.MaxUsesPerCustomer == 0 {
+		return true
+	}
+
+	uses, err := store.GetCustomerUses(c.Request.Context(), promo.Code, customer)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error obteniendo usos del código promocional", "error", err)
+		problem.Internal(c, i18n.PromoCodeGetFailed)
+		return false
+	}
+	if uses >= promo.MaxUsesPerCustomer {
+		problem.Unprocessable(c, i18n.PromoCodeCustomerLimit)
+		return false
+	}
+	return true
+}
+
+// promoCodeProblem returns why promo cannot be used on event at now, or ""
+// if it can.
+func promoCodeProblem(promo *model.PromoCode, event *model.Event, now time.Time) i18n.Key {
+	switch {
+	case !promo.Active:
+		return i18n.PromoCodeInvalid
+	case !promo.Started(now):
+		return i18n.PromoCodeNotStarted
+	case promo.Ended(now):
+		return i18n.PromoCodeExpired
+	case !promo.AppliesTo(event):
+		return i18n.PromoCodeNotApplicable
+	case promo.Exhausted():
+		return i18n.PromoCodeExhausted
+	}
+	return ""
+}
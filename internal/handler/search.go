@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/search"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+// maxSearchResults bounds ?limit on a full-text search.
+const maxSearchResults = 50
+
+type SearchHandler struct {
+	DB    *db.DynamoClient
+	Index *search.Index
+}
+
+func NewSearchHandler(db *db.DynamoClient, index *search.Index) *SearchHandler {
+	return &SearchHandler{DB: db, Index: index}
+}
+
+// SearchEvents finds the tenant's events matching ?q in their name,
+// description, location or translations, most relevant first. The index
+// only returns IDs; the events themselves are read from the table so the
+// results are current.
+func (h *SearchHandler) SearchEvents(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		problem.BadRequest(c, i18n.EventSearchQueryRequired)
+		return
+	}
+	limit := 10 // default limit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = min(l, maxSearchResults)
+		}
+	}
+
+	hits := h.Index.Search(tenant.FromContext(c.Request.Context()), query, limit)
+	ids := make([]string, len(hits))
+	scores := make(map[string]float64, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+		scores[hit.ID] = hit.Score
+	}
+
+	events, err := h.DB.GetEventsByIDs(c.Request.Context(), ids)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error buscando eventos", "error", err)
+		problem.Internal(c, i18n.EventSearchFailed)
+		return
+	}
+	for i := range events {
+		score := scores[events[i].ID.String()]
+		events[i].Score = &score
+	}
+
+	if !applyPricingRulesToEvents(c, h.DB, events) {
+		return
+	}
+	localizeEvents(c, events)
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"count":  len(events),
+		"limit":  limit,
+		"query":  query,
+	})
+}
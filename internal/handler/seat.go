@@ -0,0 +1,451 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/auth"
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/pricing"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/service"
+	"github.com/jhonathanssegura/ticket-events/internal/validation"
+)
+
+// maxSeatMapSeats bounds the size of a seat map, which is stored as a single
+// item.
+const maxSeatMapSeats = 100000
+
+type SeatHandler struct {
+	DB      *db.DynamoClient
+	HoldTTL time.Duration
+}
+
+func NewSeatHandler(db *db.DynamoClient, holdTTL time.Duration) *SeatHandler {
+	return &SeatHandler{DB: db, HoldTTL: holdTTL}
+}
+
+// PutSeatMap defines or replaces the seat map of the event and sets its
+// capacity to the number of seats. Seats already held or sold must remain in
+// the new map.
+func (h *SeatHandler) PutSeatMap(c *gin.Context) {
+	event, ok := h.loadEvent(c, true)
+	if !ok {
+		return
+	}
+
+	var req model.PutSeatMapRequest
+	if !bindJSON(c, &req, i18n.SeatMapInvalidData) {
+		return
+	}
+
+	now := time.Now()
+	seatMap := &model.SeatMap{
+		EventID:   event.ID,
+		TenantID:  event.TenantID,
+		Zones:     req.Zones,
+		Sections:  req.Sections,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if !checkSeatMap(c, seatMap, i18n.SeatMapInvalidData) {
+		return
+	}
+
+	existing, err := h.DB.GetSeatMap(c.Request.Context(), event.ID.String())
+	switch {
+	case err == nil:
+		seatMap.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, db.ErrSeatMapNotFound):
+		slog.ErrorContext(c.Request.Context(), "error obteniendo plano de asientos", "error", err)
+		problem.Internal(c, i18n.SeatMapGetFailed)
+		return
+	}
+
+	taken, err := h.DB.GetTakenSeats(c.Request.Context(), event.ID.String(), "", now)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error obteniendo asientos ocupados", "error", err)
+		problem.Internal(c, i18n.SeatsGetFailed)
+		return
+	}
+	var missing []string
+	for seatID := range taken {
+		if _, ok := seatMap.Seat(seatID); !ok {
+			missing = append(missing, seatID)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		problem.Respond(c, problem.Problem{
+			Type:   problem.TypeConflict,
+			Status: http.StatusConflict,
+			Detail: i18n.T(c.Request.Context(), i18n.SeatMapSeatsTaken, strings.Join(missing, ", ")),
+		})
+		return
+	}
+
+	if err := h.DB.SaveSeatMap(c.Request.Context(), *seatMap); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error guardando plano de asientos", "error", err)
+		problem.Internal(c, i18n.SeatMapSaveFailed)
+		return
+	}
+
+	event.Capacity = seatMap.Capacity()
+	event.UpdatedAt = now
+	if err := h.DB.SaveEvent(c.Request.Context(), *event); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error actualizando aforo del evento", "error", err)
+		problem.Internal(c, i18n.EventUpdateFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  i18n.T(c.Request.Context(), i18n.SeatMapSaved),
+		"seat_map": seatMap,
+		"capacity": event.Capacity,
+	})
+}
+
+func (h *SeatHandler) GetSeatMap(c *gin.Context) {
+	event, ok := h.loadEvent(c, false)
+	if !ok {
+		return
+	}
+	seatMap, ok := h.loadSeatMap(c, event)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"seat_map": seatMap,
+		"capacity": seatMap.Capacity(),
+	})
+}
+
+// ListSeats returns every seat of the event with its price and status,
+// optionally only those of ?section or with ?status.
+func (h *SeatHandler) ListSeats(c *gin.Context) {
+	event, ok := h.loadEvent(c, false)
+	if !ok {
+		return
+	}
+	seatMap, ok := h.loadSeatMap(c, event)
+	if !ok {
+		return
+	}
+
+	section, status := c.Query("section"), c.Query("status")
+	taken, err := h.DB.GetTakenSeats(c.Request.Context(), event.ID.String(), section, time.Now())
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error obteniendo asientos ocupados", "error", err)
+		problem.Internal(c, i18n.SeatsGetFailed)
+		return
+	}
+
+	summary := map[string]int{model.SeatAvailable: 0, model.SeatHeld: 0, model.SeatSold: 0}
+	seats := make([]model.Seat, 0)
+	for _, seat := range seatMap.Seats(section) {
+		if s, ok := taken[seat.ID]; ok {
+			seat.Status = s
+		}
+		summary[seat.Status]++
+		if status == "" || seat.Status == status {
+			seats = append(seats, seat)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"seats":   seats,
+		"count":   len(seats),
+		"summary": summary,
+	})
+}
+
+// CreateHold holds the requested seats for the caller for HoldTTL. Either
+// all of them are held or, if any is taken, none is.
+func (h *SeatHandler) CreateHold(c *gin.Context) {
+	event, ok := h.loadEvent(c, false)
+	if !ok {
+		return
+	}
+	if event.Status != model.EventStatusPublished {
+		problem.Conflict(c, i18n.SeatsNotOnSale)
+		return
+	}
+
+	var req model.CreateHoldRequest
+	if !bindJSON(c, &req, i18n.HoldInvalidData) {
+		return
+	}
+
+	seatMap, ok := h.loadSeatMap(c, event)
+	if !ok {
+		return
+	}
+	seats, ok := mapSeats(c, seatMap, req.Seats, i18n.HoldInvalidData)
+	if !ok {
+		return
+	}
+	// Held seats are priced like a quote, so the total matches what the
+	// buyer is charged
+	if !applyPricingRules(c, h.DB, event) {
+		return
+	}
+	charges, ok := loadCharges(c, h.DB, event)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	// expires_at is stored in whole seconds
+	expiresAt := now.Add(h.HoldTTL).Truncate(time.Second).UTC()
+	hold := &model.SeatHold{
+		ID:        uuid.New(),
+		EventID:   event.ID,
+		TenantID:  event.TenantID,
+		Holder:    auth.FromContext(c.Request.Context()).Subject,
+		Seats:     slices.Sorted(slices.Values(req.Seats)),
+		Status:    model.SeatHeld,
+		ExpiresAt: &expiresAt,
+		CreatedAt: now,
+	}
+
+	var unavailable *db.SeatsUnavailableError
+	if err := h.DB.HoldSeats(c.Request.Context(), *hold); err != nil {
+		if errors.As(err, &unavailable) {
+			problem.Respond(c, problem.Problem{
+				Type:   problem.TypeConflict,
+				Status: http.StatusConflict,
+				Detail: i18n.T(c.Request.Context(), i18n.SeatsUnavailable, strings.Join(unavailable.Seats, ", ")),
+			})
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error reservando asientos", "error", err)
+		problem.Internal(c, i18n.HoldCreateFailed)
+		return
+	}
+
+	for i := range seats {
+		seats[i].Status = model.SeatHeld
+	}
+	quote := pricing.NewForSeats(event, seats)
+	quote.ApplyCharges(charges)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.SeatsHeld),
+		"hold":    hold,
+		"seats":   seats,
+		"quote":   quote,
+		"total":   quote.Total,
+	})
+}
+
+func (h *SeatHandler) GetHold(c *gin.Context) {
+	_, hold, ok := h.loadHold(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hold": hold})
+}
+
+// ConfirmHold marks the seats of an active hold as sold, e.g. once the
+// buyer has paid.
+func (h *SeatHandler) ConfirmHold(c *gin.Context) {
+	event, hold, ok := h.loadHold(c)
+	if !ok {
+		return
+	}
+	if hold.Status != model.SeatHeld || hold.Expired(time.Now()) {
+		problem.Conflict(c, i18n.HoldNotActive)
+		return
+	}
+
+	if err := h.DB.ConfirmHold(c.Request.Context(), *hold); err != nil {
+		if errors.Is(err, db.ErrHoldNotActive) {
+			problem.Conflict(c, i18n.HoldNotActive)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error confirmando reserva", "error", err)
+		problem.Internal(c, i18n.HoldConfirmFailed)
+		return
+	}
+	hold.Status = model.SeatSold
+	hold.ExpiresAt = nil
+	// Sales move sell-through steps; the price change is only logged on
+	// failure since the sale itself already happened
+	if err := service.RecordPriceChange(c.Request.Context(), h.DB, event, time.Now()); err != nil {
+		slog.WarnContext(c.Request.Context(), "error registrando cambio de precio", "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.HoldConfirmed),
+		"hold":    hold,
+	})
+}
+
+// ReleaseHold gives the seats of a hold that was not confirmed back.
+func (h *SeatHandler) ReleaseHold(c *gin.Context) {
+	_, hold, ok := h.loadHold(c)
+	if !ok {
+		return
+	}
+
+	if err := h.DB.ReleaseHold(c.Request.Context(), *hold); err != nil {
+		if errors.Is(err, db.ErrHoldNotActive) {
+			problem.Conflict(c, i18n.HoldNotActive)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error liberando reserva", "error", err)
+		problem.Internal(c, i18n.HoldReleaseFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), i18n.HoldReleased)})
+}
+
+// loadEvent fetches the :id event. With manage, the caller must be allowed
+// to manage it.
+func (h *SeatHandler) loadEvent(c *gin.Context, manage bool) (*model.Event, bool) {
+	event, err := h.DB.GetEventByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			problem.NotFound(c, i18n.EventNotFound)
+			return nil, false
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo evento", "error", err)
+		problem.Internal(c, i18n.EventGetFailed)
+		return nil, false
+	}
+
+	if manage && !canManage(auth.FromContext(c.Request.Context()), event) {
+		problem.Forbidden(c, i18n.EventForbidden)
+		return nil, false
+	}
+	return event, true
+}
+
+func (h *SeatHandler) loadSeatMap(c *gin.Context, event *model.Event) (*model.SeatMap, bool) {
+	seatMap, err := h.DB.GetSeatMap(c.Request.Context(), event.ID.String())
+	if err != nil {
+		if errors.Is(err, db.ErrSeatMapNotFound) {
+			problem.NotFound(c, i18n.SeatMapNotFound)
+			return nil, false
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo plano de asientos", "error", err)
+		problem.Internal(c, i18n.SeatMapGetFailed)
+		return nil, false
+	}
+	return seatMap, true
+}
+
+// mapSeats returns the seats of seatMap with the given IDs, responding with
+// a validation problem under key if any is not in the map.
+func mapSeats(c *gin.Context, seatMap *model.SeatMap, seatIDs []string, key i18n.Key) ([]model.Seat, bool) {
+	seats := make([]model.Seat, 0, len(seatIDs))
+	var errs []validation.FieldError
+	for i, seatID := range seatIDs {
+		seat, ok := seatMap.Seat(seatID)
+		if !ok {
+			errs = append(errs, validation.NewFieldError(c.Request.Context(), fmt.Sprintf("seats[%d]", i), validation.CodeNotFound, i18n.ValidationSeatNotFound, seatID))
+			continue
+		}
+		seats = append(seats, seat)
+	}
+	if len(errs) > 0 {
+		problem.Validation(c, key, errs)
+		return nil, false
+	}
+	return seats, true
+}
+
+// loadHold fetches the :id event and its :hold_id hold. Only the holder and
+// those who manage the event may act on it.
+func (h *SeatHandler) loadHold(c *gin.Context) (*model.Event, *model.SeatHold, bool) {
+	event, ok := h.loadEvent(c, false)
+	if !ok {
+		return nil, nil, false
+	}
+
+	hold, err := h.DB.GetHold(c.Request.Context(), event.ID.String(), c.Param("hold_id"))
+	if err != nil {
+		if errors.Is(err, db.ErrHoldNotFound) {
+			problem.NotFound(c, i18n.HoldNotFound)
+			return nil, nil, false
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo reserva", "error", err)
+		problem.Internal(c, i18n.HoldGetFailed)
+		return nil, nil, false
+	}
+
+	principal := auth.FromContext(c.Request.Context())
+	if principal == nil || (principal.Subject != hold.Holder && !canManage(principal, event)) {
+		problem.Forbidden(c, i18n.HoldForbidden)
+		return nil, nil, false
+	}
+	return event, hold, true
+}
+
+// checkSeatMap responds with a validation problem and returns false if the
+// seat map repeats zones, sections or rows, refers to unknown zones, marks
+// seats beyond the end of a row as accessible or has too many seats.
+func checkSeatMap(c *gin.Context, seatMap *model.SeatMap, detail i18n.Key) bool {
+	ctx := c.Request.Context()
+	var errs []validation.FieldError
+
+	zones := make(map[string]bool)
+	for i, zone := range seatMap.Zones {
+		if zones[zone.Code] {
+			errs = append(errs, validation.NewFieldError(ctx, fmt.Sprintf("zones[%d].code", i), validation.CodeDuplicate, i18n.ValidationDuplicate))
+		}
+		zones[zone.Code] = true
+	}
+
+	sections := make(map[string]bool)
+	for i, section := range seatMap.Sections {
+		field := fmt.Sprintf("sections[%d]", i)
+		if sections[section.Name] {
+			errs = append(errs, validation.NewFieldError(ctx, field+".name", validation.CodeDuplicate, i18n.ValidationDuplicate))
+		}
+		sections[section.Name] = true
+		if !zones[section.Zone] {
+			errs = append(errs, validation.NewFieldError(ctx, field+".zone", validation.CodeNotFound, i18n.ValidationZoneNotFound))
+		}
+
+		rows := make(map[string]bool)
+		for j, row := range section.Rows {
+			field := fmt.Sprintf("%s.rows[%d]", field, j)
+			if rows[row.Name] {
+				errs = append(errs, validation.NewFieldError(ctx, field+".name", validation.CodeDuplicate, i18n.ValidationDuplicate))
+			}
+			rows[row.Name] = true
+			if row.Zone != "" && !zones[row.Zone] {
+				errs = append(errs, validation.NewFieldError(ctx, field+".zone", validation.CodeNotFound, i18n.ValidationZoneNotFound))
+			}
+			for k, number := range row.Accessible {
+				if number > row.Seats {
+					errs = append(errs, validation.NewFieldError(ctx, fmt.Sprintf("%s.accessible[%d]", field, k), validation.CodeTooLarge, i18n.ValidationTooLarge, strconv.Itoa(row.Seats)))
+				}
+			}
+		}
+	}
+
+	if total := seatMap.Capacity(); total > maxSeatMapSeats {
+		errs = append(errs, validation.NewFieldError(ctx, "sections", validation.CodeTooLarge, i18n.ValidationTooManySeats, total, maxSeatMapSeats))
+	}
+
+	if len(errs) > 0 {
+		problem.Validation(c, detail, errs)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,455 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/auth"
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/service"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+	"github.com/jhonathanssegura/ticket-events/internal/validation"
+)
+
+type SeriesHandler struct {
+	DB     *db.DynamoClient
+	Series *service.SeriesService
+}
+
+func NewSeriesHandler(db *db.DynamoClient, series *service.SeriesService) *SeriesHandler {
+	return &SeriesHandler{DB: db, Series: series}
+}
+
+// CreateSeries creates a recurring series and materializes its occurrences
+// up to the horizon.
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	var req model.CreateSeriesRequest
+	if !bindJSON(c, &req, i18n.SeriesInvalidData) {
+		return
+	}
+
+	now := time.Now()
+	series := &model.EventSeries{
+		ID:          uuid.New(),
+		TenantID:    tenant.FromContext(c.Request.Context()),
+		OrganizerID: auth.FromContext(c.Request.Context()).Subject,
+		Name:        req.Name,
+		Description: req.Description,
+		CategoryID:  req.CategoryID,
+		Location:    req.Location,
+		Latitude:    req.Latitude,
+		Longitude:   req.Longitude,
+		Capacity:    req.Capacity,
+		Price:       *req.Price,
+		ImageURL:    req.ImageURL,
+		Status:      model.EventStatusDraft,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	series.Schedule(req.StartsAt, req.EndsAt, req.TimeZone, req.RRule, req.ExDates)
+	if req.VenueID != nil {
+		venue, ok := referencedVenue(c, h.DB, *req.VenueID, i18n.SeriesInvalidData)
+		if !ok {
+			return
+		}
+		series.AtVenue(venue)
+	}
+
+	if err := h.DB.SaveSeries(c.Request.Context(), *series); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error creando serie", "error", err)
+		problem.Internal(c, i18n.SeriesCreateFailed)
+		return
+	}
+
+	created, err := h.Series.Materialize(c.Request.Context(), series, now)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error materializando serie", "series_id", series.ID.String(), "error", err)
+		problem.Internal(c, i18n.SeriesCreateFailed)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     i18n.T(c.Request.Context(), i18n.SeriesCreated),
+		"series":      series,
+		"occurrences": created,
+	})
+}
+
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+	series, ok := h.loadSeries(c, false)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"series": series})
+}
+
+// ListSeriesEvents lists the materialized occurrences of a series starting at
+// or after ?from (RFC 3339, default now).
+func (h *SeriesHandler) ListSeriesEvents(c *gin.Context) {
+	from := time.Now()
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			problem.BadRequest(c, i18n.SeriesInvalidFrom)
+			return
+		}
+		from = parsed
+	}
+
+	series, ok := h.loadSeries(c, false)
+	if !ok {
+		return
+	}
+
+	events, err := h.DB.GetEventsBySeries(c.Request.Context(), series.ID.String(), from)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error obteniendo ocurrencias de la serie", "error", err)
+		problem.Internal(c, i18n.SeriesEventsFailed)
+		return
+	}
+
+	if !applyPricingRulesToEvents(c, h.DB, events) {
+		return
+	}
+	localizeEvents(c, events)
+
+	c.JSON(http.StatusOK, gin.H{
+		"events":    events,
+		"count":     len(events),
+		"series_id": series.ID.String(),
+	})
+}
+
+// UpdateSeries changes the whole series. Template changes reach upcoming
+// occurrences that were not edited individually; schedule changes
+// regenerate them.
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+	series, ok := h.loadSeries(c, true)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateSeriesRequest
+	if !bindJSON(c, &req, i18n.SeriesInvalidUpdate) {
+		return
+	}
+
+	rescheduled, ok := applySeriesUpdate(c, h.DB, series, req)
+	if !ok {
+		return
+	}
+	if !h.saveSeries(c, series, rescheduled, time.Now()) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.SeriesUpdated),
+		"series":  series,
+	})
+}
+
+// UpdateOccurrence edits one occurrence of a series. With ?scope=this (the
+// default) only that occurrence changes and it stops following the series.
+// With ?scope=future the series is split there, and the update applies to
+// the new series holding this and every later occurrence.
+func (h *SeriesHandler) UpdateOccurrence(c *gin.Context) {
+	scope := c.DefaultQuery("scope", model.SeriesScopeThis)
+	if scope != model.SeriesScopeThis && scope != model.SeriesScopeFuture {
+		problem.BadRequest(c, i18n.SeriesInvalidScope)
+		return
+	}
+
+	series, ok := h.loadSeries(c, true)
+	if !ok {
+		return
+	}
+
+	event, err := h.DB.GetEventByID(c.Request.Context(), c.Param("event_id"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			problem.NotFound(c, i18n.SeriesOccurrenceNotFound)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo evento", "error", err)
+		problem.Internal(c, i18n.EventGetFailed)
+		return
+	}
+	if event.SeriesID != series.ID.String() || event.RecurrenceID == nil {
+		problem.NotFound(c, i18n.SeriesOccurrenceNotFound)
+		return
+	}
+
+	now := time.Now()
+	if scope == model.SeriesScopeThis {
+		var req model.UpdateEventRequest
+		if !bindJSON(c, &req, i18n.EventInvalidUpdate) {
+			return
+		}
+		if !applyEventUpdate(c, h.DB, event, req) {
+			return
+		}
+		event.Detached = true
+		event.UpdatedAt = now
+
+		if err := h.DB.SaveEvent(c.Request.Context(), *event); err != nil {
+			slog.ErrorContext(c.Request.Context(), "error actualizando ocurrencia", "error", err)
+			problem.Internal(c, i18n.EventUpdateFailed)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": i18n.T(c.Request.Context(), i18n.EventUpdated),
+			"event":   event,
+		})
+		return
+	}
+
+	var req model.UpdateSeriesRequest
+	if !bindJSON(c, &req, i18n.SeriesInvalidUpdate) {
+		return
+	}
+
+	head, tail, err := service.SplitSeries(series, *event.RecurrenceID, now)
+	if errors.Is(err, service.ErrNotOccurrence) {
+		// The rule changed after the occurrence was edited on its own
+		problem.NotFound(c, i18n.SeriesOccurrenceNotFound)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error dividiendo serie", "error", err)
+		problem.Internal(c, i18n.SeriesUpdateFailed)
+		return
+	}
+
+	// The first occurrence: the whole series changes
+	if tail == nil {
+		rescheduled, ok := applySeriesUpdate(c, h.DB, series, req)
+		if !ok {
+			return
+		}
+		if !h.saveSeries(c, series, rescheduled, now) {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message": i18n.T(c.Request.Context(), i18n.SeriesUpdated),
+			"series":  series,
+		})
+		return
+	}
+
+	if _, ok := applySeriesUpdate(c, h.DB, tail, req); !ok {
+		return
+	}
+
+	// Occurrences from the split on move to the new series, individually
+	// edited ones included; past ones stay with the original as history
+	from := *event.RecurrenceID
+	if from.Before(now) {
+		from = now
+	}
+	if err := h.DB.SaveSeries(c.Request.Context(), head); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error actualizando serie", "error", err)
+		problem.Internal(c, i18n.SeriesUpdateFailed)
+		return
+	}
+	if err := h.DB.SaveSeries(c.Request.Context(), *tail); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error creando serie", "error", err)
+		problem.Internal(c, i18n.SeriesUpdateFailed)
+		return
+	}
+	if _, err := h.Series.MoveOccurrences(c.Request.Context(), &head, tail, from, now); err != nil {
+		seriesWriteFailed(c, tail, err)
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "serie dividida",
+		"series_id", head.ID.String(), "new_series_id", tail.ID.String(), "at", event.RecurrenceID.Format(time.RFC3339))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         i18n.T(c.Request.Context(), i18n.SeriesUpdated),
+		"series":          tail,
+		"previous_series": head,
+	})
+}
+
+// DeleteSeries deletes a series and its upcoming occurrences. Past
+// occurrences are kept.
+func (h *SeriesHandler) DeleteSeries(c *gin.Context) {
+	series, ok := h.loadSeries(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.Series.DeleteOccurrences(c.Request.Context(), series.ID.String(), time.Now(), false); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error eliminando ocurrencias de la serie", "error", err)
+		problem.Internal(c, i18n.SeriesDeleteFailed)
+		return
+	}
+	if err := h.DB.DeleteSeries(c.Request.Context(), series.ID.String()); err != nil {
+		if errors.Is(err, db.ErrSeriesNotFound) {
+			problem.NotFound(c, i18n.SeriesNotFound)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error eliminando serie", "error", err)
+		problem.Internal(c, i18n.SeriesDeleteFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), i18n.SeriesDeleted)})
+}
+
+// saveSeries stores an updated series and brings its upcoming occurrences in
+// line, responding with a problem and returning false on failure.
+func (h *SeriesHandler) saveSeries(c *gin.Context, series *model.EventSeries, rescheduled bool, now time.Time) bool {
+	series.UpdatedAt = now
+	if err := h.DB.SaveSeries(c.Request.Context(), *series); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error actualizando serie", "error", err)
+		problem.Internal(c, i18n.SeriesUpdateFailed)
+		return false
+	}
+
+	var err error
+	if rescheduled {
+		_, err = h.Series.Rematerialize(c.Request.Context(), series, now)
+	} else {
+		err = h.Series.PropagateTemplate(c.Request.Context(), series, now, now)
+	}
+	if err != nil {
+		seriesWriteFailed(c, series, err)
+		return false
+	}
+	return true
+}
+
+// seriesWriteFailed responds to an error bringing the occurrences of series
+// in line, which fails if another request updated or deleted it meanwhile.
+func seriesWriteFailed(c *gin.Context, series *model.EventSeries, err error) {
+	switch {
+	case errors.Is(err, db.ErrSeriesChanged):
+		problem.Conflict(c, i18n.SeriesChanged)
+	case errors.Is(err, db.ErrSeriesNotFound):
+		problem.NotFound(c, i18n.SeriesNotFound)
+	default:
+		slog.ErrorContext(c.Request.Context(), "error actualizando ocurrencias de la serie", "series_id", series.ID.String(), "error", err)
+		problem.Internal(c, i18n.SeriesUpdateFailed)
+	}
+}
+
+// loadSeries fetches the series in the :id path parameter. With manage it
+// also checks the caller may modify it. It responds with the appropriate
+// problem and returns false otherwise.
+func (h *SeriesHandler) loadSeries(c *gin.Context, manage bool) (*model.EventSeries, bool) {
+	series, err := h.DB.GetSeriesByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, db.ErrSeriesNotFound) {
+			problem.NotFound(c, i18n.SeriesNotFound)
+			return nil, false
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo serie", "error", err)
+		problem.Internal(c, i18n.SeriesGetFailed)
+		return nil, false
+	}
+
+	if manage && !canManageSeries(auth.FromContext(c.Request.Context()), series) {
+		problem.Forbidden(c, i18n.SeriesForbidden)
+		return nil, false
+	}
+	return series, true
+}
+
+// applySeriesUpdate sets the fields present in req on series and reports
+// whether its schedule changed. It responds with a problem and returns false
+// if the venue does not exist or the merged schedule is invalid.
+func applySeriesUpdate(c *gin.Context, store *db.DynamoClient, series *model.EventSeries, req model.UpdateSeriesRequest) (rescheduled, ok bool) {
+	if req.VenueID != nil {
+		venue, ok := referencedVenue(c, store, *req.VenueID, i18n.SeriesInvalidUpdate)
+		if !ok {
+			return false, false
+		}
+		series.VenueID = &venue.ID
+		series.Location = venue.Label()
+		series.Latitude, series.Longitude = venue.Coordinates()
+		if req.TimeZone == nil && series.TimeZone != venue.TimeZone {
+			// The rule is evaluated in the venue's zone
+			tz := venue.TimeZone
+			req.TimeZone = &tz
+		}
+	}
+	if req.Name != nil {
+		series.Name = *req.Name
+	}
+	if req.Description != nil {
+		series.Description = *req.Description
+	}
+	if req.CategoryID != nil {
+		series.CategoryID = *req.CategoryID
+	}
+	if req.Location != nil {
+		series.Location = *req.Location
+	}
+	if req.Latitude != nil {
+		series.Latitude, series.Longitude = req.Latitude, req.Longitude
+	}
+	if req.Capacity != nil {
+		series.Capacity = *req.Capacity
+	}
+	if req.Price != nil {
+		series.Price = *req.Price
+	}
+	if req.ImageURL != nil {
+		series.ImageURL = *req.ImageURL
+	}
+	if req.Status != nil {
+		if !model.CanTransition(series.Status, *req.Status) {
+			statusConflict(c, series.Status, *req.Status)
+			return false, false
+		}
+		series.Status = *req.Status
+	}
+
+	startsAt, endsAt, timeZone := series.StartsAt, series.EndsAt, series.TimeZone
+	rule, exdates := series.RRule, series.ExDates
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		endsAt = *req.EndsAt
+	}
+	if req.TimeZone != nil {
+		timeZone = *req.TimeZone
+	}
+	if req.RRule != nil {
+		rule = *req.RRule
+	}
+	if req.ExDates != nil {
+		exdates = *req.ExDates
+	}
+	if !endsAt.After(startsAt) {
+		problem.Validation(c, i18n.SeriesInvalidUpdate, []validation.FieldError{
+			validation.NewFieldError(c.Request.Context(), "ends_at", validation.CodeNotAfter, i18n.ValidationNotAfter, "starts_at"),
+		})
+		return false, false
+	}
+	series.Schedule(startsAt, endsAt, timeZone, rule, exdates)
+
+	rescheduled = req.StartsAt != nil || req.EndsAt != nil || req.TimeZone != nil || req.RRule != nil || req.ExDates != nil
+	return rescheduled, true
+}
+
+// canManageSeries reports whether p may modify series: admins always can,
+// organizers only their own series.
+func canManageSeries(p *auth.Principal, series *model.EventSeries) bool {
+	if p.HasRole(auth.RoleAdmin) {
+		return true
+	}
+	return p != nil && series.OrganizerID != "" && series.OrganizerID == p.Subject
+}
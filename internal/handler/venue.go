@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+	"github.com/jhonathanssegura/ticket-events/internal/validation"
+)
+
+type VenueHandler struct {
+	DB *db.DynamoClient
+}
+
+func NewVenueHandler(db *db.DynamoClient) *VenueHandler {
+	return &VenueHandler{DB: db}
+}
+
+func (h *VenueHandler) CreateVenue(c *gin.Context) {
+	var req model.CreateVenueRequest
+	if !bindJSON(c, &req, i18n.VenueInvalidData) {
+		return
+	}
+
+	now := time.Now()
+	venue := &model.Venue{
+		ID:        uuid.New(),
+		TenantID:  tenant.FromContext(c.Request.Context()),
+		Name:      req.Name,
+		Address:   req.Address,
+		City:      req.City,
+		Country:   req.Country,
+		Latitude:  *req.Latitude,
+		Longitude: *req.Longitude,
+		TimeZone:  req.TimeZone,
+		Capacity:  req.Capacity,
+		Sections:  req.Sections,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if !checkSections(c, venue, i18n.VenueInvalidData) {
+		return
+	}
+
+	if err := h.DB.SaveVenue(c.Request.Context(), *venue); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error creando recinto", "error", err)
+		problem.Internal(c, i18n.VenueCreateFailed)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.VenueCreated),
+		"venue":   venue,
+	})
+}
+
+func (h *VenueHandler) ListVenues(c *gin.Context) {
+	venues, err := h.DB.ListVenues(c.Request.Context())
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "error obteniendo recintos", "error", err)
+		problem.Internal(c, i18n.VenueListFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"venues": venues,
+		"count":  len(venues),
+	})
+}
+
+func (h *VenueHandler) GetVenue(c *gin.Context) {
+	venue, ok := h.loadVenue(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"venue": venue})
+}
+
+// UpdateVenue changes the venue. Events already linked to it keep the
+// location, time zone and capacity they were created with.
+func (h *VenueHandler) UpdateVenue(c *gin.Context) {
+	venue, ok := h.loadVenue(c)
+	if !ok {
+		return
+	}
+
+	var req model.UpdateVenueRequest
+	if !bindJSON(c, &req, i18n.VenueInvalidUpdate) {
+		return
+	}
+
+	if req.Name != nil {
+		venue.Name = *req.Name
+	}
+	if req.Address != nil {
+		venue.Address = *req.Address
+	}
+	if req.City != nil {
+		venue.City = *req.City
+	}
+	if req.Country != nil {
+		venue.Country = *req.Country
+	}
+	if req.Latitude != nil {
+		venue.Latitude = *req.Latitude
+	}
+	if req.Longitude != nil {
+		venue.Longitude = *req.Longitude
+	}
+	if req.TimeZone != nil {
+		venue.TimeZone = *req.TimeZone
+	}
+	if req.Capacity != nil {
+		venue.Capacity = *req.Capacity
+	}
+	if req.Sections != nil {
+		venue.Sections = *req.Sections
+	}
+	if !checkSections(c, venue, i18n.VenueInvalidUpdate) {
+		return
+	}
+	venue.UpdatedAt = time.Now()
+
+	if err := h.DB.SaveVenue(c.Request.Context(), *venue); err != nil {
+		slog.ErrorContext(c.Request.Context(), "error actualizando recinto", "error", err)
+		problem.Internal(c, i18n.VenueUpdateFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(c.Request.Context(), i18n.VenueUpdated),
+		"venue":   venue,
+	})
+}
+
+// DeleteVenue deletes the venue. Events linked to it keep their copy of its
+// location.
+func (h *VenueHandler) DeleteVenue(c *gin.Context) {
+	if err := h.DB.DeleteVenue(c.Request.Context(), c.Param("id")); err != nil {
+		if errors.Is(err, db.ErrVenueNotFound) {
+			problem.NotFound(c, i18n.VenueNotFound)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "error eliminando recinto", "error", err)
+		problem.Internal(c, i18n.VenueDeleteFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), i18n.VenueDeleted)})
+}
+
+func (h *VenueHandler) loadVenue(c *gin.Context) (*model.Venue, bool) {
+	venue, err := h.DB.GetVenueByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, db.ErrVenueNotFound) {
+			problem.NotFound(c, i18n.VenueNotFound)
+			return nil, false
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo recinto", "error", err)
+		problem.Internal(c, i18n.VenueGetFailed)
+		return nil, false
+	}
+	return venue, true
+}
+
+// checkSections responds with a validation problem and returns false if the
+// sections of venue hold more people than its capacity.
+func checkSections(c *gin.Context, venue *model.Venue, detail i18n.Key) bool {
+	if total := venue.SectionsCapacity(); total > venue.Capacity {
+		problem.Validation(c, detail, []validation.FieldError{
+			validation.NewFieldError(c.Request.Context(), "sections", validation.CodeOverCapacity, i18n.ValidationOverCapacity, total, venue.Capacity),
+		})
+		return false
+	}
+	return true
+}
+
+// referencedVenue fetches the venue an event or series payload refers to. If
+// it does not exist it responds with a validation problem on venue_id.
+func referencedVenue(c *gin.Context, store *db.DynamoClient, venueID uuid.UUID, detail i18n.Key) (*model.Venue, bool) {
+	venue, err := store.GetVenueByID(c.Request.Context(), venueID.String())
+	if err != nil {
+		if errors.Is(err, db.ErrVenueNotFound) {
+			problem.Validation(c, detail, []validation.FieldError{
+				validation.NewFieldError(c.Request.Context(), "venue_id", validation.CodeNotFound, i18n.ValidationVenueNotFound),
+			})
+			return nil, false
+		}
+		slog.ErrorContext(c.Request.Context(), "error obteniendo recinto", "error", err)
+		problem.Internal(c, i18n.VenueGetFailed)
+		return nil, false
+	}
+	return venue, true
+}
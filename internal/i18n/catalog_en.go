@@ -0,0 +1,225 @@
+package i18n
+
+// english falls back to Spanish for any missing key.
+var english = map[Key]string{
+	EventCreated:                "Event created successfully",
+	EventUpdated:                "Event updated successfully",
+	EventImageUploaded:          "Event image uploaded successfully",
+	EventDeleted:                "Event deleted successfully",
+	EventTransferred:            "Event transferred successfully",
+	CategoryCreated:             "Category created successfully",
+	APIKeyCreated:               "API key created successfully. Store it, it will not be shown again",
+	APIKeyRotated:               "API key rotated successfully. Store it, it will not be shown again",
+	APIKeyRevoked:               "API key revoked successfully",
+	TranslationSaved:            "Translation saved successfully",
+	TranslationDeleted:          "Translation deleted successfully",
+	SeriesCreated:               "Series created successfully",
+	SeriesUpdated:               "Series updated successfully",
+	SeriesDeleted:               "Series deleted successfully",
+	VenueCreated:                "Venue created successfully",
+	VenueUpdated:                "Venue updated successfully",
+	VenueDeleted:                "Venue deleted successfully",
+	SeatMapSaved:                "Seat map saved",
+	SeatsHeld:                   "Seats held",
+	HoldConfirmed:               "Hold confirmed, the seats are sold",
+	HoldReleased:                "Hold released",
+	PromoCodeCreated:            "Promo code created",
+	PromoCodeUpdated:            "Promo code updated",
+	PromoCodeDeleted:            "Promo code deleted",
+	PromoCodeRedeemed:           "Promo code redeemed",
+	PricingRulesSaved:           "Pricing rules saved",
+	PricingRulesDeleted:         "Pricing rules deleted",
+	TaxJurisdictionSaved:        "Taxes saved",
+	TaxJurisdictionDeleted:      "Taxes deleted",
+	CategoryFeesSaved:           "Service fees saved",
+	CategoryFeesDeleted:         "Service fees deleted",
+	InternalError:               "Internal server error",
+	RouteNotFound:               "Route not found",
+	RequestReadFailed:           "Error reading the request",
+	RequestTooLarge:             "The request body is too large",
+	TooManyRequests:             "Too many requests, try again later",
+	TenantInvalid:               "Invalid tenant",
+	TenantRequired:              "Tenant required",
+	LocaleInvalid:               "Invalid locale, use a BCP 47 tag such as es or pt-BR",
+	TranslationInvalidData:      "Invalid translation data",
+	TranslationNotFound:         "Translation not found",
+	TokenRequired:               "Authentication token required",
+	TokenInvalid:                "Invalid authentication token",
+	TokenWrongTenant:            "The token does not belong to this tenant",
+	TokenTenantRequired:         "The token is not bound to a tenant",
+	InsufficientPermissions:     "Insufficient permissions",
+	IdempotencyKeyTooLong:       "Idempotency-Key too long",
+	IdempotencyInProgress:       "A request with the same Idempotency-Key is in progress",
+	IdempotencyKeyReused:        "Idempotency-Key already used with a different request",
+	IdempotencyVerifyFailed:     "Error verifying Idempotency-Key",
+	EventIDRequired:             "Event ID required",
+	EventNotFound:               "Event not found",
+	EventForbidden:              "You do not have permission on this event",
+	EventInvalidData:            "Invalid event data",
+	EventInvalidUpdate:          "Invalid update data",
+	EventInvalidTransfer:        "Invalid transfer data",
+	EventListFailed:             "Error retrieving events",
+	EventGetFailed:              "Error retrieving event",
+	EventVerifyFailed:           "Error verifying event",
+	EventCreateFailed:           "Error creating event",
+	EventUpdateFailed:           "Error updating event",
+	EventDeleteFailed:           "Error deleting event",
+	EventTransferFailed:         "Error transferring event",
+	EventInvalidNear:            "Invalid near parameter, use latitude,longitude in decimal degrees",
+	EventInvalidRadius:          "radius_km must be greater than 0 and at most 100",
+	EventAreaTooLarge:           "Search area too large, reduce radius_km",
+	EventSearchQueryRequired:    "The q parameter is required",
+	EventSearchFailed:           "Error searching events",
+	EventStatusTransition:       "The status cannot change from %s to %s",
+	CategoryInvalidData:         "Invalid category data",
+	CategoryNotFound:            "Category not found",
+	CategoryGetFailed:           "Error retrieving category",
+	CategoryUpdateFailed:        "Error updating category",
+	CategoryCreateFailed:        "Error creating category",
+	SeriesNotFound:              "Series not found",
+	SeriesForbidden:             "You do not have permission on this series",
+	SeriesInvalidData:           "Invalid series data",
+	SeriesInvalidUpdate:         "Invalid series update data",
+	SeriesInvalidScope:          "Invalid scope, use this or future",
+	SeriesInvalidFrom:           "Invalid from parameter, use an RFC 3339 date",
+	SeriesOccurrenceNotFound:    "The occurrence does not belong to the series",
+	SeriesChanged:               "The series was modified by another request, try again",
+	SeriesGetFailed:             "Error getting series",
+	SeriesCreateFailed:          "Error creating series",
+	SeriesUpdateFailed:          "Error updating series",
+	SeriesDeleteFailed:          "Error deleting series",
+	SeriesEventsFailed:          "Error getting series occurrences",
+	VenueNotFound:               "Venue not found",
+	VenueInvalidData:            "Invalid venue data",
+	VenueInvalidUpdate:          "Invalid venue update data",
+	VenueListFailed:             "Error listing venues",
+	VenueGetFailed:              "Error getting venue",
+	VenueCreateFailed:           "Error creating venue",
+	VenueUpdateFailed:           "Error updating venue",
+	VenueDeleteFailed:           "Error deleting venue",
+	SeatMapNotFound:             "The event has no seat map",
+	SeatMapInvalidData:          "Invalid seat map data",
+	SeatMapSeatsTaken:           "The new seat map leaves out held or sold seats: %s",
+	SeatMapGetFailed:            "Error retrieving seat map",
+	SeatMapSaveFailed:           "Error saving seat map",
+	SeatsGetFailed:              "Error retrieving seat availability",
+	SeatsUnavailable:            "Seats not available: %s",
+	SeatsNotOnSale:              "The event is not published, seats cannot be held",
+	HoldInvalidData:             "Invalid hold data",
+	HoldNotFound:                "Hold not found",
+	HoldForbidden:               "Only the holder or the event organizer can manage this hold",
+	HoldNotActive:               "The hold has expired or is no longer active",
+	HoldCreateFailed:            "Error holding seats",
+	HoldGetFailed:               "Error retrieving hold",
+	HoldConfirmFailed:           "Error confirming hold",
+	HoldReleaseFailed:           "Error releasing hold",
+	PromoCodeNotFound:           "Promo code not found",
+	PromoCodeForbidden:          "Event codes can only be managed by the event organizer; category and tenant-wide codes by an administrator",
+	PromoCodeExists:             "Promo code %s already exists",
+	PromoCodeInvalidData:        "Invalid promo code data",
+	PromoCodeInvalidUpdate:      "Invalid promo code update",
+	PromoCodeEventRequired:      "Set event_id to list the codes of one of your events",
+	PromoCodeInvalid:            "The promo code is not valid",
+	PromoCodeNotStarted:         "The promo code is not valid yet",
+	PromoCodeExpired:            "The promo code has expired",
+	PromoCodeNotApplicable:      "The promo code does not apply to this event",
+	PromoCodeExhausted:          "The promo code has no uses left",
+	PromoCodeCustomerLimit:      "You have already used this promo code the maximum number of times",
+	PromoCodeListFailed:         "Error retrieving promo codes",
+	PromoCodeGetFailed:          "Error retrieving promo code",
+	PromoCodeCreateFailed:       "Error creating promo code",
+	PromoCodeUpdateFailed:       "Error updating promo code",
+	PromoCodeDeleteFailed:       "Error deleting promo code",
+	PromoCodeRedeemFailed:       "Error redeeming promo code",
+	QuoteInvalidData:            "Invalid quote data",
+	QuoteNotOnSale:              "The event is not published, it is not on sale",
+	PricingRulesNotFound:        "The event has no pricing rules",
+	PricingRulesInvalidData:     "Invalid pricing rules",
+	PricingRulesGetFailed:       "Error retrieving pricing rules",
+	PricingRulesSaveFailed:      "Error saving pricing rules",
+	PricingRulesDeleteFailed:    "Error deleting pricing rules",
+	PriceEvaluateFailed:         "Error computing the event price",
+	PriceHistoryFailed:          "Error retrieving the price history",
+	TaxCountryInvalid:           "The country must be an ISO 3166-1 alpha-2 code",
+	TaxJurisdictionNotFound:     "No taxes are configured for the country",
+	TaxJurisdictionInvalidData:  "Invalid taxes",
+	TaxJurisdictionListFailed:   "Error listing taxes",
+	TaxJurisdictionGetFailed:    "Error retrieving taxes",
+	TaxJurisdictionSaveFailed:   "Error saving taxes",
+	TaxJurisdictionDeleteFailed: "Error deleting taxes",
+	CategoryFeesNotFound:        "The category has no service fees",
+	CategoryFeesInvalidData:     "Invalid service fees",
+	CategoryFeesGetFailed:       "Error retrieving service fees",
+	CategoryFeesSaveFailed:      "Error saving service fees",
+	CategoryFeesDeleteFailed:    "Error deleting service fees",
+	ChargesLoadFailed:           "Error retrieving the event taxes and fees",
+	ImageRequired:               "The image file is missing from the image field",
+	ImageTooLarge:               "The image cannot exceed %d MB",
+	ImageUnsupportedType:        "The image must be JPEG, PNG, WebP or GIF",
+	ImageTooManyPixels:          "The image cannot exceed %d megapixels",
+	ImageInvalid:                "The image could not be read",
+	ImageUploadFailed:           "Error uploading the event image",
+	APIKeyInvalid:               "Invalid API key",
+	APIKeyExpired:               "API key expired or revoked",
+	APIKeyWrongTenant:           "The API key does not belong to this tenant",
+	APIKeyInvalidID:             "Invalid API key ID",
+	APIKeyNotFound:              "API key not found",
+	APIKeyAlreadyRevoked:        "The API key is revoked",
+	APIKeyChanged:               "The API key was modified concurrently, please retry",
+	APIKeyInvalidData:           "Invalid API key data",
+	APIKeyVerifyFailed:          "Error verifying API key",
+	APIKeyGenerateFailed:        "Error generating API key",
+	APIKeyCreateFailed:          "Error creating API key",
+	APIKeyListFailed:            "Error retrieving API keys",
+	APIKeyGetFailed:             "Error retrieving API key",
+	APIKeyRotateFailed:          "Error rotating API key",
+	APIKeyRevokeFailed:          "Error revoking API key",
+
+	ProblemBadRequest:      "Bad request",
+	ProblemValidation:      "Invalid data",
+	ProblemUnauthorized:    "Unauthenticated",
+	ProblemForbidden:       "Access denied",
+	ProblemNotFound:        "Resource not found",
+	ProblemConflict:        "Conflict",
+	ProblemTooLarge:        "Payload too large",
+	ProblemUnsupportedType: "Unsupported media type",
+	ProblemUnprocessable:   "Unprocessable request",
+	ProblemTooManyRequests: "Too many requests",
+	ProblemInternal:        "Internal error",
+
+	ValidationRequired:         "Required field",
+	ValidationBlank:            "Must not be blank",
+	ValidationTooShort:         "Must be at least %s characters long",
+	ValidationTooFewItems:      "Must have at least %s items",
+	ValidationTooLong:          "Must be at most %s characters long",
+	ValidationTooManyItems:     "Must have at most %s items",
+	ValidationTooSmall:         "Must be greater than or equal to %s",
+	ValidationTooLarge:         "Must be less than or equal to %s",
+	ValidationNotFuture:        "Must be a future date",
+	ValidationNotAfter:         "Must be after %s",
+	ValidationInvalidZone:      "Must be an IANA time zone, e.g. Europe/Madrid",
+	ValidationInvalidURL:       "Must be a valid http or https URL",
+	ValidationInvalidScope:     "Unknown scope, allowed values: %s",
+	ValidationInvalidRRule:     "Invalid recurrence rule, use an RFC 5545 RRULE with a daily, weekly, monthly or yearly frequency",
+	ValidationInvalidLatitude:  "Must be a latitude between -90 and 90",
+	ValidationInvalidLongitude: "Must be a longitude between -180 and 180",
+	ValidationInvalidCountry:   "Must be an ISO 3166-1 alpha-2 country code, such as CO or ES",
+	ValidationOverCapacity:     "The sections add up to %d seats, more than the capacity of %d",
+	ValidationVenueNotFound:    "The venue does not exist",
+	ValidationExcludes:         "Must not contain \"%s\"",
+	ValidationDuplicate:        "Is duplicated",
+	ValidationDuplicateItems:   "Must not have repeated items",
+	ValidationZoneNotFound:     "The price zone does not exist",
+	ValidationSeatNotFound:     "Seat %s is not in the seat map",
+	ValidationTooManySeats:     "The seat map has %d seats, the maximum is %d",
+	ValidationNoSeatMap:        "The event has no seat map",
+	ValidationOneOf:            "Must be one of: %s",
+	ValidationExcludedWith:     "Cannot be set together with %s",
+	ValidationPromoCode:        "Must be 3 to 32 letters, digits, hyphens or underscores",
+	ValidationPercentage:       "A percentage cannot be greater than 100",
+	ValidationEventNotFound:    "The event does not exist",
+	ValidationInvalidType:      "Invalid data type",
+	ValidationInvalidFormat:    "Invalid date format, use RFC 3339",
+	ValidationInvalid:          "Invalid value",
+	ValidationMalformedBody:    "The request body is not valid JSON",
+}
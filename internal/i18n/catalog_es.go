@@ -0,0 +1,225 @@
+package i18n
+
+// spanish is the reference catalog; every key must have an entry here.
+var spanish = map[Key]string{
+	EventCreated:                "Evento creado con éxito",
+	EventUpdated:                "Evento actualizado con éxito",
+	EventImageUploaded:          "Imagen del evento subida con éxito",
+	EventDeleted:                "Evento eliminado con éxito",
+	EventTransferred:            "Evento transferido con éxito",
+	CategoryCreated:             "Categoría creada con éxito",
+	APIKeyCreated:               "API key creada con éxito. Guárdela, no se volverá a mostrar",
+	APIKeyRotated:               "API key rotada con éxito. Guárdela, no se volverá a mostrar",
+	APIKeyRevoked:               "API key revocada con éxito",
+	TranslationSaved:            "Traducción guardada con éxito",
+	TranslationDeleted:          "Traducción eliminada con éxito",
+	SeriesCreated:               "Serie creada con éxito",
+	SeriesUpdated:               "Serie actualizada con éxito",
+	SeriesDeleted:               "Serie eliminada con éxito",
+	VenueCreated:                "Recinto creado con éxito",
+	VenueUpdated:                "Recinto actualizado con éxito",
+	VenueDeleted:                "Recinto eliminado con éxito",
+	SeatMapSaved:                "Plano de asientos guardado",
+	SeatsHeld:                   "Asientos reservados",
+	HoldConfirmed:               "Reserva confirmada, los asientos quedan vendidos",
+	HoldReleased:                "Reserva liberada",
+	PromoCodeCreated:            "Código promocional creado",
+	PromoCodeUpdated:            "Código promocional actualizado",
+	PromoCodeDeleted:            "Código promocional eliminado",
+	PromoCodeRedeemed:           "Código promocional canjeado",
+	PricingRulesSaved:           "Reglas de precio guardadas",
+	PricingRulesDeleted:         "Reglas de precio eliminadas",
+	TaxJurisdictionSaved:        "Impuestos guardados",
+	TaxJurisdictionDeleted:      "Impuestos eliminados",
+	CategoryFeesSaved:           "Cargos por servicio guardados",
+	CategoryFeesDeleted:         "Cargos por servicio eliminados",
+	InternalError:               "Error interno del servidor",
+	RouteNotFound:               "Ruta no encontrada",
+	RequestReadFailed:           "Error leyendo la petición",
+	RequestTooLarge:             "El cuerpo de la petición es demasiado grande",
+	TooManyRequests:             "Demasiadas peticiones, intente más tarde",
+	TenantInvalid:               "Tenant inválido",
+	TenantRequired:              "Tenant requerido",
+	LocaleInvalid:               "Idioma inválido, use una etiqueta BCP 47 como es o pt-BR",
+	TranslationInvalidData:      "Datos de traducción inválidos",
+	TranslationNotFound:         "Traducción no encontrada",
+	TokenRequired:               "Token de autenticación requerido",
+	TokenInvalid:                "Token de autenticación inválido",
+	TokenWrongTenant:            "El token no pertenece a este tenant",
+	TokenTenantRequired:         "El token no está asociado a ningún tenant",
+	InsufficientPermissions:     "Permisos insuficientes",
+	IdempotencyKeyTooLong:       "Idempotency-Key demasiado larga",
+	IdempotencyInProgress:       "Hay una petición en curso con la misma Idempotency-Key",
+	IdempotencyKeyReused:        "Idempotency-Key ya usada con una petición distinta",
+	IdempotencyVerifyFailed:     "Error verificando Idempotency-Key",
+	EventIDRequired:             "ID de evento requerido",
+	EventNotFound:               "Evento no encontrado",
+	EventForbidden:              "No tiene permisos sobre este evento",
+	EventInvalidData:            "Datos de evento inválidos",
+	EventInvalidUpdate:          "Datos de actualización inválidos",
+	EventInvalidTransfer:        "Datos de transferencia inválidos",
+	EventListFailed:             "Error obteniendo eventos",
+	EventGetFailed:              "Error obteniendo evento",
+	EventVerifyFailed:           "Error verificando evento",
+	EventCreateFailed:           "Error creando evento",
+	EventUpdateFailed:           "Error actualizando evento",
+	EventDeleteFailed:           "Error eliminando evento",
+	EventTransferFailed:         "Error transfiriendo evento",
+	EventInvalidNear:            "Parámetro near inválido, use latitud,longitud en grados decimales",
+	EventInvalidRadius:          "radius_km debe ser mayor que 0 y como mucho 100",
+	EventAreaTooLarge:           "Zona de búsqueda demasiado amplia, reduzca radius_km",
+	EventSearchQueryRequired:    "El parámetro q es obligatorio",
+	EventSearchFailed:           "Error buscando eventos",
+	EventStatusTransition:       "El estado no puede pasar de %s a %s",
+	CategoryInvalidData:         "Datos de categoría inválidos",
+	CategoryNotFound:            "Categoría no encontrada",
+	CategoryGetFailed:           "Error obteniendo categoría",
+	CategoryUpdateFailed:        "Error actualizando categoría",
+	CategoryCreateFailed:        "Error creando categoría",
+	SeriesNotFound:              "Serie no encontrada",
+	SeriesForbidden:             "No tiene permisos sobre esta serie",
+	SeriesInvalidData:           "Datos de serie inválidos",
+	SeriesInvalidUpdate:         "Datos de actualización de serie inválidos",
+	SeriesInvalidScope:          "Alcance inválido, use this o future",
+	SeriesInvalidFrom:           "Parámetro from inválido, use una fecha RFC 3339",
+	SeriesOccurrenceNotFound:    "La ocurrencia no pertenece a la serie",
+	SeriesChanged:               "La serie fue modificada por otra petición, inténtelo de nuevo",
+	SeriesGetFailed:             "Error obteniendo serie",
+	SeriesCreateFailed:          "Error creando serie",
+	SeriesUpdateFailed:          "Error actualizando serie",
+	SeriesDeleteFailed:          "Error eliminando serie",
+	SeriesEventsFailed:          "Error obteniendo ocurrencias de la serie",
+	VenueNotFound:               "Recinto no encontrado",
+	VenueInvalidData:            "Datos de recinto inválidos",
+	VenueInvalidUpdate:          "Datos de actualización de recinto inválidos",
+	VenueListFailed:             "Error obteniendo recintos",
+	VenueGetFailed:              "Error obteniendo recinto",
+	VenueCreateFailed:           "Error creando recinto",
+	VenueUpdateFailed:           "Error actualizando recinto",
+	VenueDeleteFailed:           "Error eliminando recinto",
+	SeatMapNotFound:             "El evento no tiene plano de asientos",
+	SeatMapInvalidData:          "Datos del plano de asientos inválidos",
+	SeatMapSeatsTaken:           "El nuevo plano no incluye asientos reservados o vendidos: %s",
+	SeatMapGetFailed:            "Error obteniendo plano de asientos",
+	SeatMapSaveFailed:           "Error guardando plano de asientos",
+	SeatsGetFailed:              "Error obteniendo disponibilidad de asientos",
+	SeatsUnavailable:            "Asientos no disponibles: %s",
+	SeatsNotOnSale:              "El evento no está publicado, no se pueden reservar asientos",
+	HoldInvalidData:             "Datos de reserva inválidos",
+	HoldNotFound:                "Reserva no encontrada",
+	HoldForbidden:               "Solo quien hizo la reserva o el organizador del evento pueden gestionarla",
+	HoldNotActive:               "La reserva ha caducado o ya no está activa",
+	HoldCreateFailed:            "Error reservando asientos",
+	HoldGetFailed:               "Error obteniendo reserva",
+	HoldConfirmFailed:           "Error confirmando reserva",
+	HoldReleaseFailed:           "Error liberando reserva",
+	PromoCodeNotFound:           "Código promocional no encontrado",
+	PromoCodeForbidden:          "Los códigos de un evento solo los gestiona su organizador; los de una categoría o de todos los eventos, un administrador",
+	PromoCodeExists:             "Ya existe el código promocional %s",
+	PromoCodeInvalidData:        "Datos de código promocional inválidos",
+	PromoCodeInvalidUpdate:      "Actualización de código promocional inválida",
+	PromoCodeEventRequired:      "Indique event_id para listar los códigos de uno de sus eventos",
+	PromoCodeInvalid:            "El código promocional no es válido",
+	PromoCodeNotStarted:         "El código promocional todavía no está vigente",
+	PromoCodeExpired:            "El código promocional ha caducado",
+	PromoCodeNotApplicable:      "El código promocional no es válido para este evento",
+	PromoCodeExhausted:          "El código promocional ha agotado sus usos",
+	PromoCodeCustomerLimit:      "Ya ha usado este código promocional el máximo de veces permitido",
+	PromoCodeListFailed:         "Error obteniendo códigos promocionales",
+	PromoCodeGetFailed:          "Error obteniendo código promocional",
+	PromoCodeCreateFailed:       "Error creando código promocional",
+	PromoCodeUpdateFailed:       "Error actualizando código promocional",
+	PromoCodeDeleteFailed:       "Error eliminando código promocional",
+	PromoCodeRedeemFailed:       "Error canjeando código promocional",
+	QuoteInvalidData:            "Datos de presupuesto inválidos",
+	QuoteNotOnSale:              "El evento no está publicado, no está a la venta",
+	PricingRulesNotFound:        "El evento no tiene reglas de precio",
+	PricingRulesInvalidData:     "Reglas de precio inválidas",
+	PricingRulesGetFailed:       "Error obteniendo reglas de precio",
+	PricingRulesSaveFailed:      "Error guardando reglas de precio",
+	PricingRulesDeleteFailed:    "Error eliminando reglas de precio",
+	PriceEvaluateFailed:         "Error calculando el precio del evento",
+	PriceHistoryFailed:          "Error obteniendo el historial de precios",
+	TaxCountryInvalid:           "El país debe ser un código ISO 3166-1 alfa-2",
+	TaxJurisdictionNotFound:     "No hay impuestos configurados para el país",
+	TaxJurisdictionInvalidData:  "Impuestos inválidos",
+	TaxJurisdictionListFailed:   "Error listando impuestos",
+	TaxJurisdictionGetFailed:    "Error obteniendo impuestos",
+	TaxJurisdictionSaveFailed:   "Error guardando impuestos",
+	TaxJurisdictionDeleteFailed: "Error eliminando impuestos",
+	CategoryFeesNotFound:        "La categoría no tiene cargos por servicio",
+	CategoryFeesInvalidData:     "Cargos por servicio inválidos",
+	CategoryFeesGetFailed:       "Error obteniendo cargos por servicio",
+	CategoryFeesSaveFailed:      "Error guardando cargos por servicio",
+	CategoryFeesDeleteFailed:    "Error eliminando cargos por servicio",
+	ChargesLoadFailed:           "Error obteniendo impuestos y cargos del evento",
+	ImageRequired:               "Falta el archivo de imagen en el campo image",
+	ImageTooLarge:               "La imagen no puede superar los %d MB",
+	ImageUnsupportedType:        "La imagen debe ser JPEG, PNG, WebP o GIF",
+	ImageTooManyPixels:          "La imagen no puede superar los %d megapíxeles",
+	ImageInvalid:                "No se pudo leer la imagen",
+	ImageUploadFailed:           "Error subiendo la imagen del evento",
+	APIKeyInvalid:               "API key inválida",
+	APIKeyExpired:               "API key expirada o revocada",
+	APIKeyWrongTenant:           "La API key no pertenece a este tenant",
+	APIKeyInvalidID:             "ID de API key inválido",
+	APIKeyNotFound:              "API key no encontrada",
+	APIKeyAlreadyRevoked:        "La API key está revocada",
+	APIKeyChanged:               "La API key fue modificada simultáneamente, intente de nuevo",
+	APIKeyInvalidData:           "Datos de API key inválidos",
+	APIKeyVerifyFailed:          "Error verificando API key",
+	APIKeyGenerateFailed:        "Error generando API key",
+	APIKeyCreateFailed:          "Error creando API key",
+	APIKeyListFailed:            "Error obteniendo API keys",
+	APIKeyGetFailed:             "Error obteniendo API key",
+	APIKeyRotateFailed:          "Error rotando API key",
+	APIKeyRevokeFailed:          "Error revocando API key",
+
+	ProblemBadRequest:      "Petición incorrecta",
+	ProblemValidation:      "Datos inválidos",
+	ProblemUnauthorized:    "No autenticado",
+	ProblemForbidden:       "Acceso denegado",
+	ProblemNotFound:        "Recurso no encontrado",
+	ProblemConflict:        "Conflicto",
+	ProblemTooLarge:        "Contenido demasiado grande",
+	ProblemUnsupportedType: "Tipo de contenido no admitido",
+	ProblemUnprocessable:   "Petición no procesable",
+	ProblemTooManyRequests: "Demasiadas peticiones",
+	ProblemInternal:        "Error interno",
+
+	ValidationRequired:         "Campo obligatorio",
+	ValidationBlank:            "No puede estar vacío",
+	ValidationTooShort:         "Debe tener al menos %s caracteres",
+	ValidationTooFewItems:      "Debe tener al menos %s elementos",
+	ValidationTooLong:          "Debe tener como máximo %s caracteres",
+	ValidationTooManyItems:     "Debe tener como máximo %s elementos",
+	ValidationTooSmall:         "Debe ser mayor o igual que %s",
+	ValidationTooLarge:         "Debe ser menor o igual que %s",
+	ValidationNotFuture:        "Debe ser una fecha futura",
+	ValidationNotAfter:         "Debe ser posterior a %s",
+	ValidationInvalidZone:      "Debe ser una zona horaria IANA, p. ej. Europe/Madrid",
+	ValidationInvalidURL:       "Debe ser una URL http o https válida",
+	ValidationInvalidScope:     "Scope desconocido, valores posibles: %s",
+	ValidationInvalidRRule:     "Regla de recurrencia inválida, use RRULE de RFC 5545 con frecuencia diaria, semanal, mensual o anual",
+	ValidationInvalidLatitude:  "Debe ser una latitud entre -90 y 90",
+	ValidationInvalidLongitude: "Debe ser una longitud entre -180 y 180",
+	ValidationInvalidCountry:   "Debe ser un código de país ISO 3166-1 alpha-2, como CO o ES",
+	ValidationOverCapacity:     "Las secciones suman %d plazas, más que el aforo de %d",
+	ValidationVenueNotFound:    "El recinto no existe",
+	ValidationExcludes:         "No puede contener \"%s\"",
+	ValidationDuplicate:        "Está repetido",
+	ValidationDuplicateItems:   "No puede tener elementos repetidos",
+	ValidationZoneNotFound:     "La zona de precio no existe",
+	ValidationSeatNotFound:     "El asiento %s no existe en el plano",
+	ValidationTooManySeats:     "El plano tiene %d asientos, el máximo es %d",
+	ValidationNoSeatMap:        "El evento no tiene plano de asientos",
+	ValidationOneOf:            "Debe ser uno de: %s",
+	ValidationExcludedWith:     "No puede indicarse junto con %s",
+	ValidationPromoCode:        "Debe tener de 3 a 32 letras, números, guiones o guiones bajos",
+	ValidationPercentage:       "Un porcentaje no puede ser mayor que 100",
+	ValidationEventNotFound:    "El evento no existe",
+	ValidationInvalidType:      "Tipo de dato inválido",
+	ValidationInvalidFormat:    "Fecha con formato inválido, use RFC 3339",
+	ValidationInvalid:          "Valor inválido",
+	ValidationMalformedBody:    "El cuerpo de la petición no es un JSON válido",
+}
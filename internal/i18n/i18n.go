@@ -0,0 +1,130 @@
+// Package i18n holds the catalog of client-facing messages and resolves
+// them in the locale negotiated for each request.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+// Key identifies a message. Keys are stable; their texts may change.
+type Key string
+
+const (
+	Spanish = "es"
+	English = "en"
+
+	// Default is used when the client accepts none of the supported locales.
+	Default = Spanish
+)
+
+var (
+	supported = []language.Tag{language.Spanish, language.English}
+	matcher   = language.NewMatcher(supported)
+
+	catalogs = map[string]map[Key]string{
+		Spanish: spanish,
+		English: english,
+	}
+)
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx carrying locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// Locale returns the locale stored in ctx, or Default.
+func Locale(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok {
+		return locale
+	}
+	return Default
+}
+
+// Negotiate picks the best supported locale for an Accept-Language header
+// or a plain language code such as "en".
+func Negotiate(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	base, _ := supported[index].Base()
+	return base.String()
+}
+
+// Preference returns the locale preference of r: the lang query parameter
+// when present, otherwise the Accept-Language header.
+func Preference(r *http.Request) string {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		return lang
+	}
+	return r.Header.Get("Accept-Language")
+}
+
+// Match picks the locale in available that best fits preference, which is
+// an Accept-Language header or a single tag such as "pt-BR". It reports
+// false when none of them is acceptable.
+func Match(preference string, available []string) (string, bool) {
+	tags, _, err := language.ParseAcceptLanguage(preference)
+	if err != nil || len(tags) == 0 || len(available) == 0 {
+		return "", false
+	}
+	candidates := make([]language.Tag, 0, len(available))
+	locales := make([]string, 0, len(available))
+	for _, locale := range available {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, tag)
+		locales = append(locales, locale)
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	_, index, confidence := language.NewMatcher(candidates).Match(tags...)
+	if confidence == language.No {
+		return "", false
+	}
+	return locales[index], true
+}
+
+// Canonical validates a BCP 47 locale such as "pt-br" and returns its
+// canonical form ("pt-BR").
+func Canonical(locale string) (string, error) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", err
+	}
+	return tag.String(), nil
+}
+
+// T returns the message for key in the locale of ctx. Args fill the verbs
+// of messages that take parameters.
+func T(ctx context.Context, key Key, args ...any) string {
+	return Translate(Locale(ctx), key, args...)
+}
+
+// Translate returns the message for key in locale, falling back to the
+// default locale and then to the key itself.
+func Translate(locale string, key Key, args ...any) string {
+	msg, ok := catalogs[locale][key]
+	if !ok {
+		if msg, ok = catalogs[Default][key]; !ok {
+			msg = string(key)
+		}
+	}
+	if len(args) > 0 {
+		msg = fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
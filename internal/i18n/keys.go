@@ -0,0 +1,231 @@
+package i18n
+
+// Response messages.
+const (
+	EventCreated                Key = "event.created"
+	EventUpdated                Key = "event.updated"
+	EventImageUploaded          Key = "event.image_uploaded"
+	EventDeleted                Key = "event.deleted"
+	EventTransferred            Key = "event.transferred"
+	CategoryCreated             Key = "category.created"
+	APIKeyCreated               Key = "apikey.created"
+	APIKeyRotated               Key = "apikey.rotated"
+	APIKeyRevoked               Key = "apikey.revoked"
+	TranslationSaved            Key = "translation.saved"
+	TranslationDeleted          Key = "translation.deleted"
+	SeriesCreated               Key = "series.created"
+	SeriesUpdated               Key = "series.updated"
+	SeriesDeleted               Key = "series.deleted"
+	VenueCreated                Key = "venue.created"
+	VenueUpdated                Key = "venue.updated"
+	VenueDeleted                Key = "venue.deleted"
+	SeatMapSaved                Key = "seatmap.saved"
+	SeatsHeld                   Key = "hold.created"
+	HoldConfirmed               Key = "hold.confirmed"
+	HoldReleased                Key = "hold.released"
+	PromoCodeCreated            Key = "promo.created"
+	PromoCodeUpdated            Key = "promo.updated"
+	PromoCodeDeleted            Key = "promo.deleted"
+	PromoCodeRedeemed           Key = "promo.redeemed"
+	PricingRulesSaved           Key = "pricing.saved"
+	PricingRulesDeleted         Key = "pricing.deleted"
+	TaxJurisdictionSaved        Key = "taxes.saved"
+	TaxJurisdictionDeleted      Key = "taxes.deleted"
+	CategoryFeesSaved           Key = "fees.saved"
+	CategoryFeesDeleted         Key = "fees.deleted"
+	InternalError               Key = "internal_error"
+	RouteNotFound               Key = "route.not_found"
+	RequestReadFailed           Key = "request.read_failed"
+	RequestTooLarge             Key = "request.too_large"
+	TooManyRequests             Key = "ratelimit.exceeded"
+	TenantInvalid               Key = "tenant.invalid"
+	TenantRequired              Key = "tenant.required"
+	LocaleInvalid               Key = "locale.invalid"
+	TranslationInvalidData      Key = "translation.invalid_data"
+	TranslationNotFound         Key = "translation.not_found"
+	TokenRequired               Key = "auth.token_required"
+	TokenInvalid                Key = "auth.token_invalid"
+	TokenWrongTenant            Key = "auth.token_wrong_tenant"
+	TokenTenantRequired         Key = "auth.token_tenant_required"
+	InsufficientPermissions     Key = "auth.insufficient_permissions"
+	IdempotencyKeyTooLong       Key = "idempotency.key_too_long"
+	IdempotencyInProgress       Key = "idempotency.in_progress"
+	IdempotencyKeyReused        Key = "idempotency.key_reused"
+	IdempotencyVerifyFailed     Key = "idempotency.verify_failed"
+	EventIDRequired             Key = "event.id_required"
+	EventNotFound               Key = "event.not_found"
+	EventForbidden              Key = "event.forbidden"
+	EventInvalidData            Key = "event.invalid_data"
+	EventInvalidUpdate          Key = "event.invalid_update"
+	EventInvalidTransfer        Key = "event.invalid_transfer"
+	EventListFailed             Key = "event.list_failed"
+	EventGetFailed              Key = "event.get_failed"
+	EventVerifyFailed           Key = "event.verify_failed"
+	EventCreateFailed           Key = "event.create_failed"
+	EventUpdateFailed           Key = "event.update_failed"
+	EventDeleteFailed           Key = "event.delete_failed"
+	EventTransferFailed         Key = "event.transfer_failed"
+	EventInvalidNear            Key = "event.invalid_near"
+	EventInvalidRadius          Key = "event.invalid_radius"
+	EventAreaTooLarge           Key = "event.area_too_large"
+	EventSearchQueryRequired    Key = "event.search_query_required"
+	EventSearchFailed           Key = "event.search_failed"
+	EventStatusTransition       Key = "event.status_transition"
+	CategoryInvalidData         Key = "category.invalid_data"
+	CategoryNotFound            Key = "category.not_found"
+	CategoryGetFailed           Key = "category.get_failed"
+	CategoryUpdateFailed        Key = "category.update_failed"
+	CategoryCreateFailed        Key = "category.create_failed"
+	SeriesNotFound              Key = "series.not_found"
+	SeriesForbidden             Key = "series.forbidden"
+	SeriesInvalidData           Key = "series.invalid_data"
+	SeriesInvalidUpdate         Key = "series.invalid_update"
+	SeriesInvalidScope          Key = "series.invalid_scope"
+	SeriesInvalidFrom           Key = "series.invalid_from"
+	SeriesOccurrenceNotFound    Key = "series.occurrence_not_found"
+	SeriesChanged               Key = "series.changed"
+	SeriesGetFailed             Key = "series.get_failed"
+	SeriesCreateFailed          Key = "series.create_failed"
+	SeriesUpdateFailed          Key = "series.update_failed"
+	SeriesDeleteFailed          Key = "series.delete_failed"
+	SeriesEventsFailed          Key = "series.events_failed"
+	VenueNotFound               Key = "venue.not_found"
+	VenueInvalidData            Key = "venue.invalid_data"
+	VenueInvalidUpdate          Key = "venue.invalid_update"
+	VenueListFailed             Key = "venue.list_failed"
+	VenueGetFailed              Key = "venue.get_failed"
+	VenueCreateFailed           Key = "venue.create_failed"
+	VenueUpdateFailed           Key = "venue.update_failed"
+	VenueDeleteFailed           Key = "venue.delete_failed"
+	SeatMapNotFound             Key = "seatmap.not_found"
+	SeatMapInvalidData          Key = "seatmap.invalid_data"
+	SeatMapSeatsTaken           Key = "seatmap.seats_taken"
+	SeatMapGetFailed            Key = "seatmap.get_failed"
+	SeatMapSaveFailed           Key = "seatmap.save_failed"
+	SeatsGetFailed              Key = "seats.get_failed"
+	SeatsUnavailable            Key = "seats.unavailable"
+	SeatsNotOnSale              Key = "seats.not_on_sale"
+	HoldInvalidData             Key = "hold.invalid_data"
+	HoldNotFound                Key = "hold.not_found"
+	HoldForbidden               Key = "hold.forbidden"
+	HoldNotActive               Key = "hold.not_active"
+	HoldCreateFailed            Key = "hold.create_failed"
+	HoldGetFailed               Key = "hold.get_failed"
+	HoldConfirmFailed           Key = "hold.confirm_failed"
+	HoldReleaseFailed           Key = "hold.release_failed"
+	PromoCodeNotFound           Key = "promo.not_found"
+	PromoCodeForbidden          Key = "promo.forbidden"
+	PromoCodeExists             Key = "promo.exists"
+	PromoCodeInvalidData        Key = "promo.invalid_data"
+	PromoCodeInvalidUpdate      Key = "promo.invalid_update"
+	PromoCodeEventRequired      Key = "promo.event_required"
+	PromoCodeInvalid            Key = "promo.invalid"
+	PromoCodeNotStarted         Key = "promo.not_started"
+	PromoCodeExpired            Key = "promo.expired"
+	PromoCodeNotApplicable      Key = "promo.not_applicable"
+	PromoCodeExhausted          Key = "promo.exhausted"
+	PromoCodeCustomerLimit      Key = "promo.customer_limit"
+	PromoCodeListFailed         Key = "promo.list_failed"
+	PromoCodeGetFailed          Key = "promo.get_failed"
+	PromoCodeCreateFailed       Key = "promo.create_failed"
+	PromoCodeUpdateFailed       Key = "promo.update_failed"
+	PromoCodeDeleteFailed       Key = "promo.delete_failed"
+	PromoCodeRedeemFailed       Key = "promo.redeem_failed"
+	QuoteInvalidData            Key = "quote.invalid_data"
+	QuoteNotOnSale              Key = "quote.not_on_sale"
+	PricingRulesNotFound        Key = "pricing.not_found"
+	PricingRulesInvalidData     Key = "pricing.invalid_data"
+	PricingRulesGetFailed       Key = "pricing.get_failed"
+	PricingRulesSaveFailed      Key = "pricing.save_failed"
+	PricingRulesDeleteFailed    Key = "pricing.delete_failed"
+	PriceEvaluateFailed         Key = "pricing.evaluate_failed"
+	PriceHistoryFailed          Key = "pricing.history_failed"
+	TaxCountryInvalid           Key = "taxes.invalid_country"
+	TaxJurisdictionNotFound     Key = "taxes.not_found"
+	TaxJurisdictionInvalidData  Key = "taxes.invalid_data"
+	TaxJurisdictionListFailed   Key = "taxes.list_failed"
+	TaxJurisdictionGetFailed    Key = "taxes.get_failed"
+	TaxJurisdictionSaveFailed   Key = "taxes.save_failed"
+	TaxJurisdictionDeleteFailed Key = "taxes.delete_failed"
+	CategoryFeesNotFound        Key = "fees.not_found"
+	CategoryFeesInvalidData     Key = "fees.invalid_data"
+	CategoryFeesGetFailed       Key = "fees.get_failed"
+	CategoryFeesSaveFailed      Key = "fees.save_failed"
+	CategoryFeesDeleteFailed    Key = "fees.delete_failed"
+	ChargesLoadFailed           Key = "charges.load_failed"
+	ImageRequired               Key = "image.required"
+	ImageTooLarge               Key = "image.too_large"
+	ImageUnsupportedType        Key = "image.unsupported_type"
+	ImageTooManyPixels          Key = "image.too_many_pixels"
+	ImageInvalid                Key = "image.invalid"
+	ImageUploadFailed           Key = "image.upload_failed"
+	APIKeyInvalid               Key = "apikey.invalid"
+	APIKeyExpired               Key = "apikey.expired"
+	APIKeyWrongTenant           Key = "apikey.wrong_tenant"
+	APIKeyInvalidID             Key = "apikey.invalid_id"
+	APIKeyNotFound              Key = "apikey.not_found"
+	APIKeyAlreadyRevoked        Key = "apikey.already_revoked"
+	APIKeyChanged               Key = "apikey.changed"
+	APIKeyInvalidData           Key = "apikey.invalid_data"
+	APIKeyVerifyFailed          Key = "apikey.verify_failed"
+	APIKeyGenerateFailed        Key = "apikey.generate_failed"
+	APIKeyCreateFailed          Key = "apikey.create_failed"
+	APIKeyListFailed            Key = "apikey.list_failed"
+	APIKeyGetFailed             Key = "apikey.get_failed"
+	APIKeyRotateFailed          Key = "apikey.rotate_failed"
+	APIKeyRevokeFailed          Key = "apikey.revoke_failed"
+)
+
+// Problem titles.
+const (
+	ProblemBadRequest      Key = "problem.bad_request"
+	ProblemValidation      Key = "problem.validation"
+	ProblemUnauthorized    Key = "problem.unauthorized"
+	ProblemForbidden       Key = "problem.forbidden"
+	ProblemNotFound        Key = "problem.not_found"
+	ProblemConflict        Key = "problem.conflict"
+	ProblemTooLarge        Key = "problem.too_large"
+	ProblemUnsupportedType Key = "problem.unsupported_type"
+	ProblemUnprocessable   Key = "problem.unprocessable"
+	ProblemTooManyRequests Key = "problem.too_many_requests"
+	ProblemInternal        Key = "problem.internal"
+)
+
+// Validation messages. Some take the rule parameter as argument.
+const (
+	ValidationRequired         Key = "validation.required"
+	ValidationBlank            Key = "validation.blank"
+	ValidationTooShort         Key = "validation.too_short"
+	ValidationTooFewItems      Key = "validation.too_few_items"
+	ValidationTooLong          Key = "validation.too_long"
+	ValidationTooManyItems     Key = "validation.too_many_items"
+	ValidationTooSmall         Key = "validation.too_small"
+	ValidationTooLarge         Key = "validation.too_large"
+	ValidationNotFuture        Key = "validation.not_future"
+	ValidationNotAfter         Key = "validation.not_after"
+	ValidationInvalidZone      Key = "validation.invalid_time_zone"
+	ValidationInvalidURL       Key = "validation.invalid_url"
+	ValidationInvalidScope     Key = "validation.invalid_scope"
+	ValidationInvalidRRule     Key = "validation.invalid_rrule"
+	ValidationInvalidLatitude  Key = "validation.invalid_latitude"
+	ValidationInvalidLongitude Key = "validation.invalid_longitude"
+	ValidationInvalidCountry   Key = "validation.invalid_country"
+	ValidationOverCapacity     Key = "validation.over_capacity"
+	ValidationVenueNotFound    Key = "validation.venue_not_found"
+	ValidationExcludes         Key = "validation.excludes"
+	ValidationDuplicate        Key = "validation.duplicate"
+	ValidationDuplicateItems   Key = "validation.duplicate_items"
+	ValidationZoneNotFound     Key = "validation.zone_not_found"
+	ValidationSeatNotFound     Key = "validation.seat_not_found"
+	ValidationTooManySeats     Key = "validation.too_many_seats"
+	ValidationNoSeatMap        Key = "validation.no_seat_map"
+	ValidationOneOf            Key = "validation.one_of"
+	ValidationExcludedWith     Key = "validation.excluded_with"
+	ValidationPromoCode        Key = "validation.promo_code"
+	ValidationPercentage       Key = "validation.percentage"
+	ValidationEventNotFound    Key = "validation.event_not_found"
+	ValidationInvalidType      Key = "validation.invalid_type"
+	ValidationInvalidFormat    Key = "validation.invalid_format"
+	ValidationInvalid          Key = "validation.invalid"
+	ValidationMalformedBody    Key = "validation.malformed_body"
+)
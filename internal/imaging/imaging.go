@@ -0,0 +1,148 @@
+// Package imaging checks uploaded images and renders resized variants of
+// them, in pure Go.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	xdraw "golang.org/x/image/draw"
+
+	// Decoders of the accepted formats besides JPEG and PNG
+	_ "golang.org/x/image/webp"
+	_ "image/gif"
+)
+
+// MaxMegapixels bounds the size of a decoded image, so a small file cannot
+// expand into gigabytes of pixels.
+const MaxMegapixels = 40
+
+// jpegQuality is the quality variants are encoded with.
+const jpegQuality = 85
+
+var (
+	// ErrUnsupportedType is returned for content that is not a JPEG, PNG,
+	// WebP or GIF image.
+	ErrUnsupportedType = errors.New("unsupported image type")
+	// ErrTooManyPixels is returned for images over MaxMegapixels.
+	ErrTooManyPixels = errors.New("image too large")
+)
+
+// accepted maps the sniffed content type of the accepted formats to their
+// file extension.
+var accepted = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/webp": "webp",
+	"image/gif":  "gif",
+}
+
+// Variant is a resized copy of an image at most Width pixels wide.
+type Variant struct {
+	Name  string
+	Width int
+}
+
+// Variants are the copies rendered of every image, smallest first.
+var Variants = []Variant{
+	{Name: "thumbnail", Width: 320},
+	{Name: "medium", Width: 800},
+	{Name: "large", Width: 1600},
+}
+
+// File is an encoded image ready to be stored.
+type File struct {
+	ContentType string
+	Extension   string
+	Data        []byte
+	Width       int
+	Height      int
+}
+
+// Image is an uploaded image and its variants by name.
+type Image struct {
+	Original File
+	Variants map[string]File
+}
+
+// Process checks that data is an accepted image and renders its variants.
+// The type is sniffed from the content, not taken from the client. Variants
+// keep the aspect ratio and are never upscaled; they are PNG for PNG images,
+// to keep transparency, and JPEG otherwise.
+func Process(data []byte) (*Image, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := accepted[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+	if config.Width*config.Height > MaxMegapixels*1_000_000 {
+		return nil, ErrTooManyPixels
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+
+	img := &Image{
+		Original: File{
+			ContentType: contentType,
+			Extension:   ext,
+			Data:        data,
+			Width:       config.Width,
+			Height:      config.Height,
+		},
+		Variants: make(map[string]File, len(Variants)),
+	}
+	for _, v := range Variants {
+		file, err := resize(src, v.Width, contentType == "image/png")
+		if err != nil {
+			return nil, fmt.Errorf("error rendering %s variant: %w", v.Name, err)
+		}
+		img.Variants[v.Name] = file
+	}
+	return img, nil
+}
+
+// resize renders src at most width pixels wide, as PNG or else as JPEG over
+// a white background.
+func resize(src image.Image, width int, asPNG bool) (File, error) {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > width {
+		h = max(1, h*width/w)
+		w = width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if !asPNG {
+		draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	}
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, xdraw.Over, nil)
+
+	var buf bytes.Buffer
+	file := File{Width: w, Height: h}
+	if asPNG {
+		if err := png.Encode(&buf, dst); err != nil {
+			return File{}, err
+		}
+		file.ContentType, file.Extension = "image/png", "png"
+	} else {
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return File{}, err
+		}
+		file.ContentType, file.Extension = "image/jpeg", "jpg"
+	}
+	file.Data = buf.Bytes()
+	return file, nil
+}
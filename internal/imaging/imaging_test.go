@@ -0,0 +1,149 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encoded(t *testing.T, format string, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatalf("encoding %s: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+// pngHeader is the start of a PNG claiming to be w x h, enough for
+// DecodeConfig but not to decode any pixel.
+func pngHeader(w, h uint32) []byte {
+	ihdr := make([]byte, 17)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], w)
+	binary.BigEndian.PutUint32(ihdr[8:], h)
+	ihdr[12], ihdr[13] = 8, 2 // 8-bit RGB
+
+	data := []byte("\x89PNG\r\n\x1a\n")
+	data = binary.BigEndian.AppendUint32(data, 13)
+	data = append(data, ihdr...)
+	return binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(ihdr))
+}
+
+func TestProcess(t *testing.T) {
+	type size struct{ w, h int }
+	tests := []struct {
+		name        string
+		format      string
+		w, h        int
+		contentType string
+		extension   string
+		variantType string
+		variants    map[string]size
+	}{
+		{
+			name: "png keeps format", format: "png", w: 2000, h: 1000,
+			contentType: "image/png", extension: "png", variantType: "image/png",
+			variants: map[string]size{"thumbnail": {320, 160}, "medium": {800, 400}, "large": {1600, 800}},
+		},
+		{
+			name: "jpeg is never upscaled", format: "jpeg", w: 500, h: 250,
+			contentType: "image/jpeg", extension: "jpg", variantType: "image/jpeg",
+			variants: map[string]size{"thumbnail": {320, 160}, "medium": {500, 250}, "large": {500, 250}},
+		},
+		{
+			name: "gif variants are jpeg", format: "gif", w: 100, h: 300,
+			contentType: "image/gif", extension: "gif", variantType: "image/jpeg",
+			variants: map[string]size{"thumbnail": {100, 300}, "medium": {100, 300}, "large": {100, 300}},
+		},
+		{
+			name: "height never drops to zero", format: "png", w: 1000, h: 1,
+			contentType: "image/png", extension: "png", variantType: "image/png",
+			variants: map[string]size{"thumbnail": {320, 1}, "medium": {800, 1}, "large": {1000, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := encoded(t, tt.format, tt.w, tt.h)
+			img, err := Process(data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			orig := img.Original
+			if orig.ContentType != tt.contentType || orig.Extension != tt.extension {
+				t.Fatalf("original is %s/%s, want %s/%s", orig.ContentType, orig.Extension, tt.contentType, tt.extension)
+			}
+			if orig.Width != tt.w || orig.Height != tt.h || !bytes.Equal(orig.Data, data) {
+				t.Fatalf("original is %dx%d, want %dx%d and the uploaded bytes", orig.Width, orig.Height, tt.w, tt.h)
+			}
+
+			if len(img.Variants) != len(Variants) {
+				t.Fatalf("got %d variants, want %d", len(img.Variants), len(Variants))
+			}
+			for name, want := range tt.variants {
+				v, ok := img.Variants[name]
+				if !ok {
+					t.Fatalf("missing %s variant", name)
+				}
+				if v.ContentType != tt.variantType {
+					t.Errorf("%s is %s, want %s", name, v.ContentType, tt.variantType)
+				}
+				config, _, err := image.DecodeConfig(bytes.NewReader(v.Data))
+				if err != nil {
+					t.Fatalf("%s does not decode: %v", name, err)
+				}
+				if v.Width != want.w || v.Height != want.h || config.Width != want.w || config.Height != want.h {
+					t.Errorf("%s is %dx%d (encoded %dx%d), want %dx%d", name, v.Width, v.Height, config.Width, config.Height, want.w, want.h)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{name: "text", data: []byte("not an image at all"), want: ErrUnsupportedType},
+		{name: "pdf", data: []byte("%PDF-1.7\n"), want: ErrUnsupportedType},
+		{name: "empty", data: nil, want: ErrUnsupportedType},
+		{name: "too many pixels", data: pngHeader(10_000, 5_000), want: ErrTooManyPixels},
+		{name: "truncated", data: pngHeader(100, 100)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := Process(tt.data)
+			if err == nil {
+				t.Fatalf("accepted %+v", img.Original)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "ticket_events"
+
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests handled, by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency, by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dynamoDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "dynamodb_request_duration_seconds",
+		Help:      "DynamoDB call latency, by operation and table.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "table"})
+
+	dynamoErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dynamodb_errors_total",
+		Help:      "Failed DynamoDB calls, by operation and table.",
+	}, []string{"operation", "table"})
+
+	dynamoCapacity = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dynamodb_consumed_capacity_units_total",
+		Help:      "Capacity units consumed by DynamoDB calls, by operation and table.",
+	}, []string{"operation", "table"})
+
+	sqsOperations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sqs_operations_total",
+		Help:      "SQS calls, by operation and result.",
+	}, []string{"operation", "result"})
+
+	sqsDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sqs_request_duration_seconds",
+		Help:      "SQS call latency, by operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	sqsMessagesReceived = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sqs_messages_received_total",
+		Help:      "Messages received from SQS.",
+	})
+
+	s3Operations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "s3_operations_total",
+		Help:      "S3 calls, by operation and result.",
+	}, []string{"operation", "result"})
+
+	s3Duration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "s3_request_duration_seconds",
+		Help:      "S3 call latency, by operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	eventsByStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "events",
+		Help:      "Events stored, by tenant and status. Refreshed periodically.",
+	}, []string{"tenant", "status"})
+)
+
+// ObserveHTTP records a handled HTTP request.
+func ObserveHTTP(method, route string, status int, elapsed time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpDuration.WithLabelValues(method, route, code).Observe(elapsed.Seconds())
+}
+
+// ObserveDynamo records a DynamoDB call and the capacity units it consumed.
+func ObserveDynamo(operation, table string, elapsed time.Duration, capacityUnits float64, err error) {
+	dynamoDuration.WithLabelValues(operation, table).Observe(elapsed.Seconds())
+	if err != nil {
+		dynamoErrors.WithLabelValues(operation, table).Inc()
+	}
+	if capacityUnits > 0 {
+		dynamoCapacity.WithLabelValues(operation, table).Add(capacityUnits)
+	}
+}
+
+// ObserveSQS records an SQS call.
+func ObserveSQS(operation string, elapsed time.Duration, err error) {
+	result := resultSuccess
+	if err != nil {
+		result = resultError
+	}
+	sqsOperations.WithLabelValues(operation, result).Inc()
+	sqsDuration.WithLabelValues(operation).Observe(elapsed.Seconds())
+}
+
+// AddSQSMessagesReceived counts messages returned by a receive call.
+func AddSQSMessagesReceived(n int) {
+	sqsMessagesReceived.Add(float64(n))
+}
+
+// ObserveS3 records an S3 call.
+func ObserveS3(operation string, elapsed time.Duration, err error) {
+	result := resultSuccess
+	if err != nil {
+		result = resultError
+	}
+	s3Operations.WithLabelValues(operation, result).Inc()
+	s3Duration.WithLabelValues(operation).Observe(elapsed.Seconds())
+}
+
+// SetEventsByStatus replaces the events gauge with counts keyed by tenant and
+// then status.
+func SetEventsByStatus(counts map[string]map[string]int) {
+	eventsByStatus.Reset()
+	for tenantID, byStatus := range counts {
+		for status, n := range byStatus {
+			eventsByStatus.WithLabelValues(tenantID, status).Set(float64(n))
+		}
+	}
+}
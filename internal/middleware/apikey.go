@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/auth"
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyTouchInterval limits how often last-used time is written per key.
+const apiKeyTouchInterval = time.Minute
+
+// APIKey authenticates requests carrying an X-API-Key header. Requests
+// without the header pass through untouched; an invalid, expired or revoked
+// key is rejected. The key determines the tenant and its owner becomes the
+// acting principal, limited to the key's scopes.
+func APIKey(store *db.DynamoClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		plaintext := c.GetHeader(APIKeyHeader)
+		if plaintext == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		id, secret, err := auth.ParseAPIKey(plaintext)
+		if err != nil {
+			problem.Unauthorized(c, i18n.APIKeyInvalid)
+			return
+		}
+
+		key, err := store.LookupAPIKey(ctx, id)
+		if err != nil {
+			if errors.Is(err, db.ErrAPIKeyNotFound) {
+				problem.Unauthorized(c, i18n.APIKeyInvalid)
+				return
+			}
+			slog.ErrorContext(ctx, "error verificando API key", "error", err)
+			problem.Internal(c, i18n.APIKeyVerifyFailed)
+			return
+		}
+
+		now := time.Now()
+		if !auth.MatchAPIKeySecret(secret, key.KeyHash) {
+			problem.Unauthorized(c, i18n.APIKeyInvalid)
+			return
+		}
+		if !key.Active(now) {
+			problem.Unauthorized(c, i18n.APIKeyExpired)
+			return
+		}
+
+		if key.TenantID != tenant.FromContext(ctx) {
+			if c.GetBool(tenantExplicitKey) {
+				problem.Forbidden(c, i18n.APIKeyWrongTenant)
+				return
+			}
+			ctx = tenant.WithTenant(ctx, key.TenantID)
+			c.Header(TenantHeader, key.TenantID)
+		}
+
+		if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+			if err := store.TouchAPIKey(ctx, key.ID, now); err != nil {
+				slog.WarnContext(ctx, "error registrando uso de API key", "api_key_id", key.ID.String(), "error", err)
+			}
+		}
+
+		principal := &auth.Principal{
+			Subject:  key.OwnerID,
+			TenantID: key.TenantID,
+			APIKeyID: key.ID.String(),
+			Scopes:   key.Scopes,
+		}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(ctx, principal))
+		c.Next()
+	}
+}
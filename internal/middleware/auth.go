@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/auth"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+// Authenticate requires a valid "Authorization: Bearer <jwt>" header and
+// stores the caller in the request context. Every route behind it is
+// scoped to a tenant, so tokens must carry a tenant_id claim and are only
+// accepted for that tenant. Requests already authenticated by APIKey pass
+// through.
+func Authenticate(verifier *auth.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if auth.FromContext(c.Request.Context()).IsAPIKey() {
+			c.Next()
+			return
+		}
+
+		token, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			c.Header("WWW-Authenticate", `Bearer`)
+			problem.Unauthorized(c, i18n.TokenRequired)
+			return
+		}
+
+		principal, err := verifier.Verify(c.Request.Context(), token)
+		if err != nil {
+			slog.InfoContext(c.Request.Context(), "token rechazado", "error", err)
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			problem.Unauthorized(c, i18n.TokenInvalid)
+			return
+		}
+
+		// A token without a tenant would otherwise act on whichever tenant
+		// the request names
+		if principal.TenantID == "" {
+			problem.Forbidden(c, i18n.TokenTenantRequired)
+			return
+		}
+
+		ctx := c.Request.Context()
+		if principal.TenantID != tenant.FromContext(ctx) {
+			// Claims are issued by the identity provider, but still validate
+			// them before they end up in DynamoDB keys and queue attributes.
+			if !tenant.Valid(principal.TenantID) {
+				problem.Forbidden(c, i18n.TokenWrongTenant)
+				return
+			}
+			if c.GetBool(tenantExplicitKey) {
+				problem.Forbidden(c, i18n.TokenWrongTenant)
+				return
+			}
+			ctx = tenant.WithTenant(ctx, principal.TenantID)
+			c.Header(TenantHeader, principal.TenantID)
+		}
+
+		c.Request = c.Request.WithContext(auth.WithPrincipal(ctx, principal))
+		c.Next()
+	}
+}
+
+// RequireRole rejects callers that hold none of roles. API keys carry no
+// roles, so routes guarded only by RequireRole are closed to them. It must
+// run after Authenticate.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !auth.FromContext(c.Request.Context()).HasRole(roles...) {
+			problem.Forbidden(c, i18n.InsufficientPermissions)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAccess admits API key callers granted scope and other callers
+// holding any of roles. With no roles, anonymous and token callers are
+// admitted and only API keys are restricted.
+func RequireAccess(scope string, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := auth.FromContext(c.Request.Context())
+		allowed := len(roles) == 0 || principal.HasRole(roles...)
+		if principal.IsAPIKey() {
+			allowed = principal.HasScope(scope)
+		}
+		if !allowed {
+			problem.Forbidden(c, i18n.InsufficientPermissions)
+			return
+		}
+		c.Next()
+	}
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jhonathanssegura/ticket-events/internal/auth"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+func TestAuthenticateTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const secret = "test-secret"
+	verifier := auth.NewVerifier(auth.VerifierConfig{HMACSecret: secret})
+
+	tests := []struct {
+		name        string
+		tokenTenant string
+		header      string
+		want        int
+		wantTenant  string
+	}{
+		{name: "token without tenant and explicit tenant", header: "acme", want: http.StatusForbidden},
+		{name: "token without tenant and default tenant", want: http.StatusForbidden},
+		{name: "token for the requested tenant", tokenTenant: "acme", header: "acme", want: http.StatusOK, wantTenant: "acme"},
+		{name: "token for another tenant", tokenTenant: "acme", header: "other", want: http.StatusForbidden},
+		{name: "token tenant replaces the default", tokenTenant: "acme", want: http.StatusOK, wantTenant: "acme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					Subject:   "user-1",
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+				},
+				TenantID: tt.tokenTenant,
+			}).SignedString([]byte(secret))
+			if err != nil {
+				t.Fatalf("signing token: %v", err)
+			}
+
+			var gotTenant string
+			r := gin.New()
+			r.Use(Tenant("", "default"), Authenticate(verifier))
+			r.POST("/events", func(c *gin.Context) {
+				gotTenant = tenant.FromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/events", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			if tt.header != "" {
+				req.Header.Set(TenantHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if gotTenant != tt.wantTenant {
+				t.Fatalf("tenant = %q, want %q", gotTenant, tt.wantTenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+)
+
+// BodyLimit caps request bodies at maxBytes, so an oversized body is
+// rejected with 413 while it is read instead of being buffered whole before
+// validation. Routes in uploads, as "METHOD /path" with the path as
+// registered, are left to their handlers, which set their own, larger
+// limits; every other route is capped whatever its Content-Type.
+func BodyLimit(maxBytes int64, uploads ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if route := c.FullPath(); route != "" && slices.Contains(uploads, c.Request.Method+" "+route) {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > maxBytes {
+			problem.TooLarge(c, i18n.RequestTooLarge)
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
+
+// IsBodyTooLarge reports whether err comes from reading past the limit set
+// by BodyLimit.
+func IsBodyTooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
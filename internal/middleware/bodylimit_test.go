@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		path        string
+		chunked     bool
+		want        int
+	}{
+		{name: "within limit", body: strings.Repeat("a", 16), contentType: "application/json", want: http.StatusOK},
+		{name: "declared too large", body: strings.Repeat("a", 17), contentType: "application/json", want: http.StatusRequestEntityTooLarge},
+		{name: "chunked too large", body: strings.Repeat("a", 17), contentType: "application/json", chunked: true, want: http.StatusRequestEntityTooLarge},
+		{name: "upload route skipped", body: strings.Repeat("a", 17), contentType: "multipart/form-data; boundary=x", path: "/events/1/image", want: http.StatusOK},
+		{name: "multipart on a json route", body: strings.Repeat("a", 17), contentType: "multipart/form-data; boundary=x", want: http.StatusRequestEntityTooLarge},
+		{name: "json on the upload route", body: strings.Repeat("a", 17), contentType: "application/json", path: "/events/1/image", want: http.StatusOK},
+		{name: "unmatched route", body: strings.Repeat("a", 17), contentType: "multipart/form-data; boundary=x", path: "/nowhere", want: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(BodyLimit(16, "POST /events/:id/image"))
+			read := func(c *gin.Context) {
+				if _, err := io.ReadAll(c.Request.Body); IsBodyTooLarge(err) {
+					c.Status(http.StatusRequestEntityTooLarge)
+					return
+				}
+				c.Status(http.StatusOK)
+			}
+			r.POST("/", read)
+			r.POST("/events/:id/image", read)
+			r.NoRoute(read)
+
+			path := tt.path
+			if path == "" {
+				path = "/"
+			}
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
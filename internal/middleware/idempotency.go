@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/auth"
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayHeader marks responses replayed from a stored result.
+	IdempotentReplayHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// idempotencyLock bounds how long a claim blocks other requests with the
+	// same key if the process dies before completing it.
+	idempotencyLock = time.Minute
+	// idempotencyPoll is how often a concurrent duplicate re-checks the key.
+	idempotencyPoll = 200 * time.Millisecond
+)
+
+// Idempotency makes a route safe to retry with an Idempotency-Key header.
+// The first response is stored per tenant, caller and key for ttl, and
+// requests repeating the key get it replayed. A duplicate sent while the
+// original is still running waits up to wait for it, then gets 409. Reusing a
+// key with a different body is rejected with 422. Server errors are not
+// stored so the client can retry. It must run after authentication.
+func Idempotency(store *db.DynamoClient, ttl, wait time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(IdempotencyKeyHeader)
+		p := auth.FromContext(c.Request.Context())
+		if header == "" || p == nil {
+			c.Next()
+			return
+		}
+		if len(header) > maxIdempotencyKeyLength {
+			problem.BadRequest(c, i18n.IdempotencyKeyTooLong)
+			return
+		}
+
+		ctx := c.Request.Context()
+		body, err := io.ReadAll(c.Request.Body)
+		if IsBodyTooLarge(err) {
+			problem.TooLarge(c, i18n.RequestTooLarge)
+			return
+		}
+		if err != nil {
+			problem.BadRequest(c, i18n.RequestReadFailed)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := callerID(p) + "#" + header
+		hash := requestHash(c.Request.Method, c.Request.URL.Path, body)
+
+		record, claimed, err := store.ClaimIdempotencyKey(ctx, key, hash, idempotencyLock, ttl)
+		deadline := time.Now().Add(wait)
+		for err == nil && !claimed && record.Status == db.IdempotencyInProgress && time.Now().Before(deadline) {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(idempotencyPoll):
+			}
+			record, claimed, err = store.ClaimIdempotencyKey(ctx, key, hash, idempotencyLock, ttl)
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "error verificando Idempotency-Key", "error", err)
+			problem.Internal(c, i18n.IdempotencyVerifyFailed)
+			return
+		}
+
+		if !claimed {
+			switch {
+			case record.RequestHash != hash:
+				problem.Unprocessable(c, i18n.IdempotencyKeyReused)
+			case record.Status == db.IdempotencyInProgress:
+				problem.Conflict(c, i18n.IdempotencyInProgress)
+			default:
+				c.Header(IdempotentReplayHeader, "true")
+				c.Data(record.StatusCode, record.ContentType, record.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			if err := store.ReleaseIdempotencyKey(ctx, key); err != nil {
+				slog.WarnContext(ctx, "error liberando Idempotency-Key", "error", err)
+			}
+			return
+		}
+		if err := store.CompleteIdempotencyKey(ctx, key, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			slog.ErrorContext(ctx, "error guardando respuesta idempotente", "error", err)
+		}
+	}
+}
+
+// callerID identifies who sent a request, so different callers cannot
+// replay each other's responses by guessing keys.
+func callerID(p *auth.Principal) string {
+	if p.IsAPIKey() {
+		return "key:" + p.APIKeyID
+	}
+	return "sub:" + p.Subject
+}
+
+// requestHash fingerprints a request by method, concrete path and body, so a
+// key reused for another resource on the same route is rejected rather than
+// replayed.
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// bodyRecorder copies the response body while it is written to the client.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
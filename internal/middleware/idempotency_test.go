@@ -0,0 +1,28 @@
+package middleware
+
+import "testing"
+
+func TestRequestHash(t *testing.T) {
+	base := requestHash("POST", "/events/1/holds", []byte(`{"seats":["A1"]}`))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		same   bool
+	}{
+		{name: "identical request", method: "POST", path: "/events/1/holds", body: `{"seats":["A1"]}`, same: true},
+		{name: "other resource on the same route", method: "POST", path: "/events/2/holds", body: `{"seats":["A1"]}`},
+		{name: "other body", method: "POST", path: "/events/1/holds", body: `{"seats":["A2"]}`},
+		{name: "other method", method: "PUT", path: "/events/1/holds", body: `{"seats":["A1"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := requestHash(tt.method, tt.path, []byte(tt.body))
+			if (got == base) != tt.same {
+				t.Fatalf("requestHash equal = %v, want %v", got == base, tt.same)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+)
+
+// Locale negotiates the response language from the lang query parameter or
+// the Accept-Language header, defaulting to Spanish, and stores it in the
+// request context. The chosen locale is echoed in Content-Language.
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := i18n.Negotiate(i18n.Preference(c.Request))
+		c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), locale))
+		c.Header("Content-Language", locale)
+		c.Header("Vary", "Accept-Language")
+		c.Next()
+	}
+}
@@ -0,0 +1,13 @@
+package middleware
+
+import "net/http"
+
+// SkipProbes reports false for health, readiness and metrics scrapes so they
+// can be excluded from tracing.
+func SkipProbes(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/healthz", "/readyz", "/metrics":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/auth"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/ratelimit"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+// RateLimit applies a token bucket per tenant, client and route. Clients are
+// identified by API key when present and by IP otherwise. Every response
+// carries RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset; rejected
+// requests get 429 with Retry-After. If the store fails the request is let
+// through, so a backend outage does not take the API down.
+func RateLimit(store ratelimit.Store, policy ratelimit.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			c.Next()
+			return
+		}
+		route = c.Request.Method + " " + route
+
+		ctx := c.Request.Context()
+		client := "ip:" + c.ClientIP()
+		p := auth.FromContext(ctx)
+		isAPIKey := p != nil && p.IsAPIKey()
+		if isAPIKey {
+			client = "key:" + p.APIKeyID
+		}
+		key := tenant.FromContext(ctx) + "|" + client + "|" + route
+
+		limit := policy.For(route, isAPIKey)
+		res, err := store.Take(ctx, key, limit, time.Now())
+		if err != nil {
+			slog.WarnContext(ctx, "error aplicando límite de peticiones", "key", key, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(res.Reset))
+		if !res.Allowed {
+			c.Header("Retry-After", ceilSeconds(res.RetryAfter))
+			problem.TooManyRequests(c, i18n.TooManyRequests)
+			return
+		}
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
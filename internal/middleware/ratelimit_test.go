@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/ratelimit"
+)
+
+func TestRateLimitClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		trusted []string
+		// Each request comes from remote with the given X-Forwarded-For.
+		remote    []string
+		forwarded []string
+		want      []int
+	}{
+		{
+			name:      "spoofed header does not reset the bucket",
+			remote:    []string{"203.0.113.7:5000", "203.0.113.7:5001", "203.0.113.7:5002"},
+			forwarded: []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"},
+			want:      []int{http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests},
+		},
+		{
+			name:      "header of an untrusted proxy ignored",
+			trusted:   []string{"10.0.0.0/8"},
+			remote:    []string{"203.0.113.7:5000", "203.0.113.7:5001"},
+			forwarded: []string{"198.51.100.1", "198.51.100.2"},
+			want:      []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:      "clients behind a trusted proxy have their own buckets",
+			trusted:   []string{"10.0.0.0/8"},
+			remote:    []string{"10.0.0.2:5000", "10.0.0.2:5001", "10.0.0.2:5002"},
+			forwarded: []string{"198.51.100.1", "198.51.100.2", "198.51.100.1"},
+			want:      []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:   "different connections",
+			remote: []string{"203.0.113.7:5000", "203.0.113.8:5000"},
+			want:   []int{http.StatusOK, http.StatusOK},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			if err := r.SetTrustedProxies(tt.trusted); err != nil {
+				t.Fatalf("SetTrustedProxies: %v", err)
+			}
+			r.Use(RateLimit(ratelimit.NewMemoryStore(), ratelimit.Policy{Anonymous: ratelimit.Limit{Rate: 0.001, Burst: 1}}))
+			r.GET("/events", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			for i, remote := range tt.remote {
+				req := httptest.NewRequest(http.MethodGet, "/events", nil)
+				req.RemoteAddr = remote
+				if i < len(tt.forwarded) {
+					req.Header.Set("X-Forwarded-For", tt.forwarded[i])
+				}
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+				if w.Code != tt.want[i] {
+					t.Fatalf("request %d: status = %d, want %d", i, w.Code, tt.want[i])
+				}
+			}
+		})
+	}
+}
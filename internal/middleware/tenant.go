@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/problem"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+const TenantHeader = "X-Tenant-ID"
+
+// tenantExplicitKey marks requests whose tenant came from the header or the
+// subdomain rather than the default, so Authenticate knows whether a token
+// claim may replace it.
+const tenantExplicitKey = "tenant_explicit"
+
+// Tenant resolves the tenant of each request from the X-Tenant-ID header,
+// then from the subdomain of baseDomain (e.g. "brand" in
+// brand.tickets.example.com), falling back to defaultTenant. Requests with
+// no resolvable tenant are rejected. On authenticated routes the tenant_id
+// claim of the token replaces the default and must match an explicit tenant.
+func Tenant(baseDomain, defaultTenant string) gin.HandlerFunc {
+	baseDomain = strings.ToLower(strings.TrimPrefix(baseDomain, "."))
+	return func(c *gin.Context) {
+		id := strings.ToLower(strings.TrimSpace(c.GetHeader(TenantHeader)))
+		if id == "" {
+			id = subdomainTenant(c.Request.Host, baseDomain)
+		}
+		c.Set(tenantExplicitKey, id != "")
+		if id == "" {
+			id = defaultTenant
+		}
+
+		if id != "" {
+			if !tenant.Valid(id) {
+				problem.BadRequest(c, i18n.TenantInvalid)
+				return
+			}
+			c.Request = c.Request.WithContext(tenant.WithTenant(c.Request.Context(), id))
+			c.Header(TenantHeader, id)
+		}
+		c.Next()
+	}
+}
+
+// RequireTenant rejects requests for which no tenant could be resolved.
+func RequireTenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if tenant.FromContext(c.Request.Context()) == "" {
+			problem.BadRequest(c, i18n.TenantRequired)
+			return
+		}
+		c.Next()
+	}
+}
+
+func subdomainTenant(host, baseDomain string) string {
+	if baseDomain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	sub, ok := strings.CutSuffix(host, "."+baseDomain)
+	if !ok || sub == "" || strings.Contains(sub, ".") {
+		return ""
+	}
+	return sub
+}
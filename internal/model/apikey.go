@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey is a credential issued to a partner integration. Only the hash of
+// the secret is stored; the plaintext key is returned once on creation or
+// rotation.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	TenantID   string     `json:"tenant_id" db:"tenant_id"`
+	Name       string     `json:"name" db:"name"`
+	OwnerID    string     `json:"owner_id" db:"owner_id"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	Scopes     []string   `json:"scopes" db:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Active reports whether the key may be used at now.
+func (k *APIKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,notblank,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,unique,dive,scope"`
+	OwnerID   string     `json:"owner_id" binding:"max=200"`
+	ExpiresAt *time.Time `json:"expires_at" binding:"omitnil,future"`
+}
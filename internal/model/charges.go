@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaxJurisdiction holds the taxes charged on tickets of events held in a
+// country, the country of the event's venue. Rates are percentages.
+type TaxJurisdiction struct {
+	// Country is an ISO 3166-1 alpha-2 code.
+	Country  string `json:"country" db:"country"`
+	TenantID string `json:"tenant_id" db:"tenant_id"`
+	Taxes    []Tax  `json:"taxes" db:"taxes"`
+	// TaxFees makes service fees part of the taxable amount, as most VAT
+	// regimes do.
+	TaxFees   bool      `json:"tax_fees" db:"tax_fees"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type Tax struct {
+	Name string  `json:"name" binding:"required,notblank,max=100"`
+	Rate float64 `json:"rate" binding:"min=0,max=100"`
+}
+
+// CategoryFees holds the service fees charged on tickets of events of a
+// category.
+type CategoryFees struct {
+	CategoryID uuid.UUID    `json:"category_id" db:"category_id"`
+	TenantID   string       `json:"tenant_id" db:"tenant_id"`
+	Fees       []ServiceFee `json:"fees" db:"fees"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// ServiceFee charges Percent of the ticket price, after discounts, plus
+// PerTicket on every ticket.
+type ServiceFee struct {
+	Name      string  `json:"name" binding:"required,notblank,max=100"`
+	Percent   float64 `json:"percent" binding:"min=0,max=100"`
+	PerTicket float64 `json:"per_ticket" binding:"min=0"`
+}
+
+type PutTaxJurisdictionRequest struct {
+	Taxes   []Tax `json:"taxes" binding:"required,min=1,max=10,unique=Name,dive"`
+	TaxFees bool  `json:"tax_fees"`
+}
+
+type PutCategoryFeesRequest struct {
+	Fees []ServiceFee `json:"fees" binding:"required,min=1,max=10,unique=Name,dive"`
+}
@@ -1,48 +1,254 @@
 package model
 
 import (
+	"encoding/json"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Event struct {
-	ID          uuid.UUID  `json:"id" db:"id"`
-	Name        string     `json:"name" db:"name"`
-	Description string     `json:"description" db:"description"`
-	CategoryID  uuid.UUID  `json:"category_id" db:"category_id"`
-	Location    string     `json:"location" db:"location"`
-	Date        time.Time  `json:"date" db:"date"`
-	Capacity    int        `json:"capacity" db:"capacity"`
-	Price       float64    `json:"price" db:"price"`
-	Status      string     `json:"status" db:"status"`
-	ImageURL    string     `json:"image_url" db:"image_url"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
-}
-
-type Category struct {
 	ID          uuid.UUID `json:"id" db:"id"`
+	TenantID    string    `json:"tenant_id" db:"tenant_id"`
 	Name        string    `json:"name" db:"name"`
 	Description string    `json:"description" db:"description"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	CategoryID  uuid.UUID `json:"category_id" db:"category_id"`
+	OrganizerID string    `json:"organizer_id" db:"organizer_id"`
+	Location    string    `json:"location" db:"location"`
+	// VenueID is the venue the event is held at, if any. Location keeps a
+	// copy of its address.
+	VenueID *uuid.UUID `json:"venue_id,omitempty" db:"venue_id"`
+	// Latitude and Longitude locate the event, usually copied from its venue.
+	Latitude  *float64 `json:"latitude,omitempty" db:"latitude"`
+	Longitude *float64 `json:"longitude,omitempty" db:"longitude"`
+	// DistanceKm is set on the results of a search near a point. It is not
+	// stored.
+	DistanceKm *float64 `json:"distance_km,omitempty" db:"-"`
+	// Score is the relevance of the event on the results of a full-text
+	// search. It is not stored.
+	Score *float64 `json:"score,omitempty" db:"-"`
+	// EffectivePrice is Price adjusted by the pricing rules of the event at
+	// the time of the request, if it has any. It is not stored.
+	EffectivePrice *float64 `json:"effective_price,omitempty" db:"-"`
+	// StartsAt and EndsAt are kept in UTC. TimeZone is the IANA zone of the
+	// venue, used to present local times.
+	StartsAt  time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time `json:"ends_at" db:"ends_at"`
+	TimeZone  string    `json:"time_zone" db:"time_zone"`
+	Capacity  int       `json:"capacity" db:"capacity"`
+	Price     float64   `json:"price" db:"price"`
+	Status    string    `json:"status" db:"status"`
+	ImageURL  string    `json:"image_url" db:"image_url"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// ImageVariants holds the URLs of the resized copies of an uploaded image
+	// by variant name (thumbnail, medium, large). Events whose ImageURL is
+	// set by hand have none.
+	ImageVariants map[string]string `json:"image_variants,omitempty" db:"image_variants"`
+
+	// Translations holds the name and description per locale (BCP 47 tag).
+	// Name and Description are the untranslated originals.
+	Translations Translations `json:"translations,omitempty" db:"translations"`
+	// Locale is the translation applied by Localize, if any. It is not
+	// stored.
+	Locale string `json:"locale,omitempty" db:"-"`
+
+	// SeriesID links an occurrence to its EventSeries. RecurrenceID is the
+	// start the series originally scheduled it at, and Detached is set once
+	// the occurrence is edited on its own, so series-wide changes skip it.
+	SeriesID     string     `json:"series_id,omitempty" db:"series_id"`
+	RecurrenceID *time.Time `json:"recurrence_id,omitempty" db:"recurrence_id"`
+	Detached     bool       `json:"detached,omitempty" db:"detached"`
+}
+
+// Schedule sets when the event takes place, normalizing the times to UTC.
+func (e *Event) Schedule(startsAt, endsAt time.Time, timeZone string) {
+	e.StartsAt = startsAt.UTC()
+	e.EndsAt = endsAt.UTC()
+	e.TimeZone = timeZone
+}
+
+// SetImageURL points the event at the image at url, dropping the variants
+// of a previously uploaded image.
+func (e *Event) SetImageURL(url string) {
+	if url != e.ImageURL {
+		e.ImageVariants = nil
+	}
+	e.ImageURL = url
+}
+
+// AtVenue links the event to venue, taking the venue's location,
+// coordinates, time zone and capacity where the event has none of its own.
+func (e *Event) AtVenue(venue *Venue) {
+	id := venue.ID
+	e.VenueID = &id
+	if e.Location == "" {
+		e.Location = venue.Label()
+	}
+	if e.Latitude == nil {
+		e.Latitude, e.Longitude = venue.Coordinates()
+	}
+	if e.TimeZone == "" {
+		e.TimeZone = venue.TimeZone
+	}
+	if e.Capacity == 0 {
+		e.Capacity = venue.Capacity
+	}
+}
+
+// Zone returns the venue time zone, or UTC if it is unset or unknown.
+func (e *Event) Zone() *time.Location {
+	return zone(e.TimeZone)
+}
+
+func zone(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// MarshalJSON adds the start and end times in the venue time zone next to
+// the UTC ones.
+func (e Event) MarshalJSON() ([]byte, error) {
+	type event Event
+	zone := e.Zone()
+	return json.Marshal(struct {
+		event
+		LocalStartsAt time.Time `json:"local_starts_at"`
+		LocalEndsAt   time.Time `json:"local_ends_at"`
+	}{
+		event:         event(e),
+		LocalStartsAt: e.StartsAt.In(zone),
+		LocalEndsAt:   e.EndsAt.In(zone),
+	})
+}
+
+// Localize replaces the name and description with the translation for
+// locale, when there is one.
+func (e *Event) Localize(locale string) {
+	if t, ok := e.Translations[locale]; ok {
+		e.Name, e.Description = t.apply(e.Name, e.Description)
+		e.Locale = locale
+	}
+}
+
+type Category struct {
+	ID           uuid.UUID    `json:"id" db:"id"`
+	TenantID     string       `json:"tenant_id" db:"tenant_id"`
+	Name         string       `json:"name" db:"name"`
+	Description  string       `json:"description" db:"description"`
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at" db:"updated_at"`
+	Translations Translations `json:"translations,omitempty" db:"translations"`
+	Locale       string       `json:"locale,omitempty" db:"-"`
+}
+
+// Localize replaces the name and description with the translation for
+// locale, when there is one.
+func (c *Category) Localize(locale string) {
+	if t, ok := c.Translations[locale]; ok {
+		c.Name, c.Description = t.apply(c.Name, c.Description)
+		c.Locale = locale
+	}
+}
+
+// Translation is the localized content of an event or category.
+type Translation struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// apply returns the translated name and description, keeping the originals
+// for fields that are not translated.
+func (t Translation) apply(name, description string) (string, string) {
+	if t.Name != "" {
+		name = t.Name
+	}
+	if t.Description != "" {
+		description = t.Description
+	}
+	return name, description
+}
+
+// Translations maps a locale to its translation.
+type Translations map[string]Translation
+
+// Locales lists the locales that have a translation, sorted.
+func (t Translations) Locales() []string {
+	return slices.Sorted(maps.Keys(t))
 }
 
 type CreateEventRequest struct {
-	Name        string    `json:"name" binding:"required"`
-	Description string    `json:"description" binding:"required"`
+	Name        string    `json:"name" binding:"required,notblank,max=200"`
+	Description string    `json:"description" binding:"required,notblank,max=5000"`
 	CategoryID  uuid.UUID `json:"category_id" binding:"required"`
-	Location    string    `json:"location" binding:"required"`
-	Date        time.Time `json:"date" binding:"required"`
-	Capacity    int       `json:"capacity" binding:"required"`
-	Price       float64   `json:"price" binding:"required"`
-	ImageURL    string    `json:"image_url"`
+	// With a VenueID, Location, TimeZone and Capacity default to the
+	// venue's.
+	VenueID  *uuid.UUID `json:"venue_id"`
+	Location string     `json:"location" binding:"required_without=VenueID,omitempty,notblank,max=300"`
+	// Latitude and Longitude go together.
+	Latitude  *float64 `json:"latitude" binding:"required_with=Longitude,omitnil,latitude"`
+	Longitude *float64 `json:"longitude" binding:"required_with=Latitude,omitnil,longitude"`
+	// StartsAt and EndsAt may carry any offset; they are stored in UTC.
+	StartsAt time.Time `json:"starts_at" binding:"required,future"`
+	EndsAt   time.Time `json:"ends_at" binding:"required,gtfield=StartsAt"`
+	TimeZone string    `json:"time_zone" binding:"required_without=VenueID,omitempty,timezone"`
+	Capacity int       `json:"capacity" binding:"required_without=VenueID,omitempty,min=1,max=1000000"`
+	// Price is a pointer so that free events (price 0) can be told apart
+	// from a missing price.
+	Price    *float64 `json:"price" binding:"required,min=0,max=1000000"`
+	ImageURL string   `json:"image_url" binding:"omitempty,http_url,max=2048"`
+}
+
+// UpdateEventRequest is a partial update: only the fields present in the
+// body are changed, and they follow the same rules as on creation. An empty
+// image_url removes the image. That ends_at follows starts_at is checked on
+// the merged event, and status against the current one. A new venue_id also replaces the location, coordinates
+// and time zone unless they are given.
+type UpdateEventRequest struct {
+	Name        *string    `json:"name" binding:"omitnil,notblank,max=200"`
+	Description *string    `json:"description" binding:"omitnil,notblank,max=5000"`
+	CategoryID  *uuid.UUID `json:"category_id" binding:"omitnil,required"`
+	VenueID     *uuid.UUID `json:"venue_id" binding:"omitnil,required"`
+	Location    *string    `json:"location" binding:"omitnil,notblank,max=300"`
+	Latitude    *float64   `json:"latitude" binding:"required_with=Longitude,omitnil,latitude"`
+	Longitude   *float64   `json:"longitude" binding:"required_with=Latitude,omitnil,longitude"`
+	StartsAt    *time.Time `json:"starts_at" binding:"omitnil,future"`
+	EndsAt      *time.Time `json:"ends_at" binding:"omitnil"`
+	TimeZone    *string    `json:"time_zone" binding:"omitnil,timezone"`
+	Capacity    *int       `json:"capacity" binding:"omitnil,min=1,max=1000000"`
+	Price       *float64   `json:"price" binding:"omitnil,min=0,max=1000000"`
+	ImageURL    *string    `json:"image_url" binding:"omitnil,max=2048,len=0|http_url"`
+	// Status moves the event along its lifecycle; see CanTransition.
+	Status *string `json:"status" binding:"omitnil,oneof=draft published cancelled completed"`
+}
+
+type TransferEventRequest struct {
+	OrganizerID string `json:"organizer_id" binding:"required,notblank,max=200"`
+}
+
+// EventTranslationRequest adds or replaces the translation of an event for
+// the locale in the path. Description may be left out to keep the original.
+type EventTranslationRequest struct {
+	Name        string `json:"name" binding:"required,notblank,max=200"`
+	Description string `json:"description" binding:"max=5000"`
+}
+
+type CategoryTranslationRequest struct {
+	Name        string `json:"name" binding:"required,notblank,max=100"`
+	Description string `json:"description" binding:"max=1000"`
 }
 
 type CreateCategoryRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description" binding:"required"`
+	Name        string `json:"name" binding:"required,notblank,max=100"`
+	Description string `json:"description" binding:"required,notblank,max=1000"`
 }
 
 const (
@@ -50,4 +256,21 @@ const (
 	EventStatusPublished = "published"
 	EventStatusCancelled = "cancelled"
 	EventStatusCompleted = "completed"
-) 
\ No newline at end of file
+)
+
+// statusTransitions lists the statuses an event may move to from each
+// status. Cancelled and completed events stay as they are.
+var statusTransitions = map[string][]string{
+	EventStatusDraft:     {EventStatusPublished, EventStatusCancelled},
+	EventStatusPublished: {EventStatusDraft, EventStatusCancelled, EventStatusCompleted},
+}
+
+// CanTransition reports whether an event in status from may move to status
+// to. Only published events are on sale. Events stored without a status
+// count as drafts.
+func CanTransition(from, to string) bool {
+	if from == "" {
+		from = EventStatusDraft
+	}
+	return from == to || slices.Contains(statusTransitions[from], to)
+}
@@ -0,0 +1,33 @@
+package model
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{from: EventStatusDraft, to: EventStatusDraft, want: true},
+		{from: EventStatusDraft, to: EventStatusPublished, want: true},
+		{from: EventStatusDraft, to: EventStatusCancelled, want: true},
+		{from: EventStatusDraft, to: EventStatusCompleted, want: false},
+		{from: EventStatusPublished, to: EventStatusDraft, want: true},
+		{from: EventStatusPublished, to: EventStatusCancelled, want: true},
+		{from: EventStatusPublished, to: EventStatusCompleted, want: true},
+		{from: EventStatusCancelled, to: EventStatusPublished, want: false},
+		{from: EventStatusCancelled, to: EventStatusDraft, want: false},
+		{from: EventStatusCompleted, to: EventStatusPublished, want: false},
+		{from: EventStatusCompleted, to: EventStatusCompleted, want: true},
+		// Events stored before statuses were set are drafts
+		{from: "", to: EventStatusPublished, want: true},
+		{from: "", to: EventStatusCompleted, want: false},
+		{from: EventStatusDraft, to: "archived", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.want {
+				t.Fatalf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
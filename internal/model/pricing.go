@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PricingRules raise or lower the price of an event as it approaches and as
+// it sells. They are evaluated on read against the event's Price; the
+// adjustments of the time step and the sell-through step in effect add up,
+// and the result is kept between Floor and Ceiling.
+type PricingRules struct {
+	EventID          uuid.UUID         `json:"event_id" db:"event_id"`
+	TenantID         string            `json:"tenant_id" db:"tenant_id"`
+	TimeSteps        []TimeStep        `json:"time_steps" db:"time_steps"`
+	SellThroughSteps []SellThroughStep `json:"sell_through_steps" db:"sell_through_steps"`
+	Floor            *float64          `json:"floor,omitempty" db:"floor"`
+	Ceiling          *float64          `json:"ceiling,omitempty" db:"ceiling"`
+	// CurrentPrice is the effective price last recorded in the price
+	// history.
+	CurrentPrice *float64  `json:"current_price,omitempty" db:"current_price"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TimeStep adjusts the price by Percent from HoursBefore hours before the
+// event starts. The step closest to the start wins.
+type TimeStep struct {
+	HoursBefore int     `json:"hours_before" binding:"min=1,max=8760"`
+	Percent     float64 `json:"percent" binding:"min=-90,max=1000"`
+}
+
+// SellThroughStep adjusts the price by Percent once SoldPercent of the
+// capacity is sold. The highest step reached wins.
+type SellThroughStep struct {
+	SoldPercent float64 `json:"sold_percent" binding:"min=1,max=100"`
+	Percent     float64 `json:"percent" binding:"min=-90,max=1000"`
+}
+
+// Reasons of a PriceChange.
+const (
+	PriceChangeRules        = "rules_updated"
+	PriceChangeRulesRemoved = "rules_removed"
+	PriceChangeDynamic      = "dynamic"
+)
+
+// PriceChange records that the effective price of an event changed, either
+// because its rules changed or because time passed or tickets sold.
+type PriceChange struct {
+	EventID       uuid.UUID `json:"event_id" db:"event_id"`
+	Price         float64   `json:"price" db:"price"`
+	PreviousPrice *float64  `json:"previous_price,omitempty" db:"previous_price"`
+	BasePrice     float64   `json:"base_price" db:"base_price"`
+	// Percent is the total adjustment applied to BasePrice, before the
+	// floor and ceiling.
+	Percent     float64   `json:"percent" db:"percent"`
+	SoldPercent float64   `json:"sold_percent" db:"sold_percent"`
+	Reason      string    `json:"reason" db:"reason"`
+	ChangedAt   time.Time `json:"changed_at" db:"changed_at"`
+}
+
+type PutPricingRulesRequest struct {
+	TimeSteps        []TimeStep        `json:"time_steps" binding:"max=20,unique=HoursBefore,dive"`
+	SellThroughSteps []SellThroughStep `json:"sell_through_steps" binding:"max=20,unique=SoldPercent,dive"`
+	Floor            *float64          `json:"floor" binding:"omitnil,min=0"`
+	Ceiling          *float64          `json:"ceiling" binding:"omitnil,min=0"`
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PromoPercentage = "percentage"
+	PromoFixed      = "fixed"
+)
+
+// PromoCode is a discount code of a tenant. It applies to one event, to the
+// events of one category or, with neither set, to every event. MaxUses and
+// MaxUsesPerCustomer are 0 when unlimited.
+type PromoCode struct {
+	Code     string `json:"code" db:"code"`
+	TenantID string `json:"tenant_id" db:"tenant_id"`
+	// Kind is PromoPercentage, with Value between 0 and 100, or PromoFixed,
+	// with Value an amount off the order, never more than its subtotal.
+	Kind               string     `json:"kind" db:"kind"`
+	Value              float64    `json:"value" db:"value"`
+	EventID            *uuid.UUID `json:"event_id,omitempty" db:"event_id"`
+	CategoryID         *uuid.UUID `json:"category_id,omitempty" db:"category_id"`
+	StartsAt           *time.Time `json:"starts_at,omitempty" db:"starts_at"`
+	EndsAt             *time.Time `json:"ends_at,omitempty" db:"ends_at"`
+	MaxUses            int        `json:"max_uses" db:"max_uses"`
+	MaxUsesPerCustomer int        `json:"max_uses_per_customer" db:"max_uses_per_customer"`
+	Uses               int        `json:"uses" db:"uses"`
+	Active             bool       `json:"active" db:"active"`
+	CreatedBy          string     `json:"created_by" db:"created_by"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// NormalizePromoCode returns the stored form of a code typed by a customer.
+// Codes are case-insensitive.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// AppliesTo reports whether the code is valid for event.
+func (p *PromoCode) AppliesTo(event *Event) bool {
+	switch {
+	case p.EventID != nil:
+		return *p.EventID == event.ID
+	case p.CategoryID != nil:
+		return *p.CategoryID == event.CategoryID
+	default:
+		return true
+	}
+}
+
+// Started reports whether the validity window has opened at now.
+func (p *PromoCode) Started(now time.Time) bool {
+	return p.StartsAt == nil || !now.Before(*p.StartsAt)
+}
+
+// Ended reports whether the validity window has closed at now.
+func (p *PromoCode) Ended(now time.Time) bool {
+	return p.EndsAt != nil && !now.Before(*p.EndsAt)
+}
+
+// Exhausted reports whether the code has no uses left.
+func (p *PromoCode) Exhausted() bool {
+	return p.MaxUses > 0 && p.Uses >= p.MaxUses
+}
+
+type CreatePromoCodeRequest struct {
+	Code               string     `json:"code" binding:"required,promocode"`
+	Kind               string     `json:"kind" binding:"required,oneof=percentage fixed"`
+	Value              float64    `json:"value" binding:"required,min=0.01"`
+	EventID            *uuid.UUID `json:"event_id"`
+	CategoryID         *uuid.UUID `json:"category_id" binding:"excluded_with=EventID"`
+	StartsAt           *time.Time `json:"starts_at"`
+	EndsAt             *time.Time `json:"ends_at"`
+	MaxUses            int        `json:"max_uses" binding:"min=0"`
+	MaxUsesPerCustomer int        `json:"max_uses_per_customer" binding:"min=0"`
+}
+
+// UpdatePromoCodeRequest changes the fields present. The code, its kind and
+// its scope cannot change.
+type UpdatePromoCodeRequest struct {
+	Value              *float64   `json:"value" binding:"omitnil,min=0.01"`
+	StartsAt           *time.Time `json:"starts_at"`
+	EndsAt             *time.Time `json:"ends_at"`
+	MaxUses            *int       `json:"max_uses" binding:"omitnil,min=0"`
+	MaxUsesPerCustomer *int       `json:"max_uses_per_customer" binding:"omitnil,min=0"`
+	Active             *bool      `json:"active"`
+}
+
+// Redemption records one use of a promo code by a customer.
+type Redemption struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Code      string    `json:"code" db:"code"`
+	EventID   uuid.UUID `json:"event_id" db:"event_id"`
+	Customer  string    `json:"customer" db:"customer"`
+	Quantity  int       `json:"quantity" db:"quantity"`
+	Discount  float64   `json:"discount" db:"discount"`
+	Total     float64   `json:"total" db:"total"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// QuoteRequest asks for Quantity tickets or, for events with a seat map,
+// for the given Seats.
+type QuoteRequest struct {
+	Quantity int      `json:"quantity" binding:"required_without=Seats,excluded_with=Seats,omitempty,min=1,max=100"`
+	Seats    []string `json:"seats" binding:"max=10,unique,dive,required"`
+	Code     string   `json:"code" binding:"max=32"`
+}
+
+// RedeemPromoCodeRequest redeems a code for Quantity tickets or, for events
+// with a seat map, for the given Seats.
+type RedeemPromoCodeRequest struct {
+	EventID  uuid.UUID `json:"event_id" binding:"required"`
+	Quantity int       `json:"quantity" binding:"required_without=Seats,excluded_with=Seats,omitempty,min=1,max=100"`
+	Seats    []string  `json:"seats" binding:"max=10,unique,dive,required"`
+}
@@ -0,0 +1,181 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SeatMap is the assigned seating of an event: its price zones and the
+// sections, rows and seats of the room. Seats are numbered from 1 within
+// each row and identified as "section:row:number", e.g. "Platea:F:12".
+type SeatMap struct {
+	EventID   uuid.UUID     `json:"event_id" db:"event_id"`
+	TenantID  string        `json:"tenant_id" db:"tenant_id"`
+	Zones     []PriceZone   `json:"zones" db:"zones"`
+	Sections  []SeatSection `json:"sections" db:"sections"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// PriceZone is a price level shared by some sections or rows. Its price
+// replaces the event price for the seats in it.
+type PriceZone struct {
+	Code  string  `json:"code" binding:"required,notblank,max=20,excludes=:"`
+	Name  string  `json:"name" binding:"required,notblank,max=100"`
+	Price float64 `json:"price" binding:"min=0"`
+}
+
+type SeatSection struct {
+	Name string    `json:"name" binding:"required,notblank,max=50,excludes=:"`
+	Zone string    `json:"zone" binding:"required"`
+	Rows []SeatRow `json:"rows" binding:"required,min=1,max=50,dive"`
+}
+
+// SeatRow is a row of Seats seats numbered 1 to Seats. Accessible lists the
+// wheelchair accessible ones.
+type SeatRow struct {
+	Name  string `json:"name" binding:"required,notblank,max=10,excludes=:"`
+	Seats int    `json:"seats" binding:"required,min=1,max=200"`
+	// Zone overrides the zone of the section for this row.
+	Zone       string `json:"zone,omitempty"`
+	Accessible []int  `json:"accessible,omitempty" binding:"max=200,unique,dive,min=1"`
+}
+
+const (
+	SeatAvailable = "available"
+	SeatHeld      = "held"
+	SeatSold      = "sold"
+)
+
+// Seat is one seat of a seat map with its current status.
+type Seat struct {
+	ID         string  `json:"id"`
+	Section    string  `json:"section"`
+	Row        string  `json:"row"`
+	Number     int     `json:"number"`
+	Zone       string  `json:"zone"`
+	Price      float64 `json:"price"`
+	Accessible bool    `json:"accessible"`
+	Status     string  `json:"status"`
+}
+
+// SeatID returns the identifier of a seat.
+func SeatID(section, row string, number int) string {
+	return section + ":" + row + ":" + strconv.Itoa(number)
+}
+
+// Capacity returns the number of seats in the map.
+func (m *SeatMap) Capacity() int {
+	total := 0
+	for _, section := range m.Sections {
+		for _, row := range section.Rows {
+			total += row.Seats
+		}
+	}
+	return total
+}
+
+// Zone returns the price zone with code.
+func (m *SeatMap) Zone(code string) (PriceZone, bool) {
+	for _, zone := range m.Zones {
+		if zone.Code == code {
+			return zone, true
+		}
+	}
+	return PriceZone{}, false
+}
+
+// Seats lists the seats of the map, optionally only those of one section,
+// all of them available.
+func (m *SeatMap) Seats(section string) []Seat {
+	var seats []Seat
+	for _, s := range m.Sections {
+		if section != "" && s.Name != section {
+			continue
+		}
+		for _, row := range s.Rows {
+			for number := 1; number <= row.Seats; number++ {
+				seats = append(seats, m.seat(s, row, number))
+			}
+		}
+	}
+	return seats
+}
+
+// Seat returns the seat identified by id, if the map has it.
+func (m *SeatMap) Seat(id string) (Seat, bool) {
+	parts := strings.Split(id, ":")
+	if len(parts) != 3 {
+		return Seat{}, false
+	}
+	number, err := strconv.Atoi(parts[2])
+	if err != nil || strconv.Itoa(number) != parts[2] {
+		return Seat{}, false
+	}
+	for _, section := range m.Sections {
+		if section.Name != parts[0] {
+			continue
+		}
+		for _, row := range section.Rows {
+			if row.Name == parts[1] && number >= 1 && number <= row.Seats {
+				return m.seat(section, row, number), true
+			}
+		}
+	}
+	return Seat{}, false
+}
+
+func (m *SeatMap) seat(section SeatSection, row SeatRow, number int) Seat {
+	code := section.Zone
+	if row.Zone != "" {
+		code = row.Zone
+	}
+	zone, _ := m.Zone(code)
+	accessible := false
+	for _, n := range row.Accessible {
+		if n == number {
+			accessible = true
+			break
+		}
+	}
+	return Seat{
+		ID:         SeatID(section.Name, row.Name, number),
+		Section:    section.Name,
+		Row:        row.Name,
+		Number:     number,
+		Zone:       zone.Code,
+		Price:      zone.Price,
+		Accessible: accessible,
+		Status:     SeatAvailable,
+	}
+}
+
+// SeatHold reserves some seats of an event for a buyer until ExpiresAt.
+// Once confirmed its seats are sold and it no longer expires.
+type SeatHold struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	EventID   uuid.UUID  `json:"event_id" db:"event_id"`
+	TenantID  string     `json:"tenant_id" db:"tenant_id"`
+	Holder    string     `json:"holder" db:"holder"`
+	Seats     []string   `json:"seats" db:"seats"`
+	Status    string     `json:"status" db:"status"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Expired reports whether the hold lapsed before being confirmed.
+func (h *SeatHold) Expired(now time.Time) bool {
+	return h.Status == SeatHeld && h.ExpiresAt != nil && !now.Before(*h.ExpiresAt)
+}
+
+type PutSeatMapRequest struct {
+	Zones    []PriceZone   `json:"zones" binding:"required,min=1,max=20,dive"`
+	Sections []SeatSection `json:"sections" binding:"required,min=1,max=100,dive"`
+}
+
+type CreateHoldRequest struct {
+	Seats []string `json:"seats" binding:"required,min=1,max=10,unique,dive,required"`
+}
@@ -0,0 +1,185 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventSeries is a recurring event. Its occurrences are materialized as
+// regular events linked back through SeriesID, up to a rolling horizon.
+type EventSeries struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	TenantID    string    `json:"tenant_id" db:"tenant_id"`
+	OrganizerID string    `json:"organizer_id" db:"organizer_id"`
+
+	// Template copied onto every occurrence.
+	Name        string     `json:"name" db:"name"`
+	Description string     `json:"description" db:"description"`
+	CategoryID  uuid.UUID  `json:"category_id" db:"category_id"`
+	Location    string     `json:"location" db:"location"`
+	VenueID     *uuid.UUID `json:"venue_id,omitempty" db:"venue_id"`
+	Latitude    *float64   `json:"latitude,omitempty" db:"latitude"`
+	Longitude   *float64   `json:"longitude,omitempty" db:"longitude"`
+	Capacity    int        `json:"capacity" db:"capacity"`
+	Price       float64    `json:"price" db:"price"`
+	ImageURL    string     `json:"image_url" db:"image_url"`
+	// Status is given to the upcoming occurrences, so publishing the series
+	// puts them on sale. Series stored without one are drafts.
+	Status string `json:"status" db:"status"`
+
+	// StartsAt and EndsAt are the first occurrence (the RFC 5545 DTSTART)
+	// in UTC; every occurrence lasts as long. The rule is evaluated in
+	// TimeZone, so occurrences keep their local time across DST changes.
+	StartsAt time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt   time.Time `json:"ends_at" db:"ends_at"`
+	TimeZone string    `json:"time_zone" db:"time_zone"`
+	// RRule is an RFC 5545 recurrence rule without DTSTART, e.g.
+	// "FREQ=DAILY;COUNT=40". ExDates are the starts of skipped occurrences.
+	RRule   string      `json:"rrule" db:"rrule"`
+	ExDates []time.Time `json:"exdates,omitempty" db:"exdates"`
+
+	// MaterializedUntil is the end of the window whose occurrences exist as
+	// events.
+	MaterializedUntil time.Time `json:"materialized_until" db:"materialized_until"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Schedule sets the first occurrence, rule and exception dates. Times are
+// normalized to whole seconds in UTC, the precision of RFC 5545.
+func (s *EventSeries) Schedule(startsAt, endsAt time.Time, timeZone, rule string, exdates []time.Time) {
+	s.StartsAt = startsAt.UTC().Truncate(time.Second)
+	s.EndsAt = endsAt.UTC().Truncate(time.Second)
+	s.TimeZone = timeZone
+	s.RRule = rule
+	s.ExDates = make([]time.Time, 0, len(exdates))
+	for _, exdate := range exdates {
+		s.ExDates = append(s.ExDates, exdate.UTC().Truncate(time.Second))
+	}
+}
+
+// AtVenue links the series to venue, taking the venue's location,
+// coordinates, time zone and capacity where the series has none of its own.
+func (s *EventSeries) AtVenue(venue *Venue) {
+	id := venue.ID
+	s.VenueID = &id
+	if s.Location == "" {
+		s.Location = venue.Label()
+	}
+	if s.Latitude == nil {
+		s.Latitude, s.Longitude = venue.Coordinates()
+	}
+	if s.TimeZone == "" {
+		s.TimeZone = venue.TimeZone
+	}
+	if s.Capacity == 0 {
+		s.Capacity = venue.Capacity
+	}
+}
+
+// Zone returns the venue time zone, or UTC if it is unset or unknown.
+func (s *EventSeries) Zone() *time.Location {
+	return zone(s.TimeZone)
+}
+
+// Duration is how long each occurrence lasts.
+func (s *EventSeries) Duration() time.Duration {
+	return s.EndsAt.Sub(s.StartsAt)
+}
+
+// Excluded reports whether the occurrence starting at start was removed
+// with an exception date.
+func (s *EventSeries) Excluded(start time.Time) bool {
+	for _, exdate := range s.ExDates {
+		if exdate.Equal(start) {
+			return true
+		}
+	}
+	return false
+}
+
+// Occurrence builds the event for the occurrence starting at start. Its ID is
+// derived from the series and start, so materializing twice yields the same
+// event.
+func (s *EventSeries) Occurrence(start, now time.Time) Event {
+	start = start.UTC()
+	event := Event{
+		ID:           uuid.NewSHA1(s.ID, []byte(start.Format(time.RFC3339))),
+		TenantID:     s.TenantID,
+		OrganizerID:  s.OrganizerID,
+		Status:       EventStatusDraft,
+		SeriesID:     s.ID.String(),
+		RecurrenceID: &start,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	s.ApplyTemplate(&event)
+	event.Schedule(start, start.Add(s.Duration()), s.TimeZone)
+	return event
+}
+
+// ApplyTemplate copies the series template onto an occurrence, leaving its
+// schedule untouched.
+func (s *EventSeries) ApplyTemplate(event *Event) {
+	event.Name = s.Name
+	event.Description = s.Description
+	event.CategoryID = s.CategoryID
+	event.Location = s.Location
+	event.VenueID = s.VenueID
+	event.Latitude = s.Latitude
+	event.Longitude = s.Longitude
+	event.Capacity = s.Capacity
+	event.Price = s.Price
+	event.SetImageURL(s.ImageURL)
+	if s.Status != "" {
+		event.Status = s.Status
+	}
+}
+
+type CreateSeriesRequest struct {
+	Name        string      `json:"name" binding:"required,notblank,max=200"`
+	Description string      `json:"description" binding:"required,notblank,max=5000"`
+	CategoryID  uuid.UUID   `json:"category_id" binding:"required"`
+	VenueID     *uuid.UUID  `json:"venue_id"`
+	Location    string      `json:"location" binding:"required_without=VenueID,omitempty,notblank,max=300"`
+	Latitude    *float64    `json:"latitude" binding:"required_with=Longitude,omitnil,latitude"`
+	Longitude   *float64    `json:"longitude" binding:"required_with=Latitude,omitnil,longitude"`
+	StartsAt    time.Time   `json:"starts_at" binding:"required"`
+	EndsAt      time.Time   `json:"ends_at" binding:"required,gtfield=StartsAt"`
+	TimeZone    string      `json:"time_zone" binding:"required_without=VenueID,omitempty,timezone"`
+	RRule       string      `json:"rrule" binding:"required,rrule"`
+	ExDates     []time.Time `json:"exdates" binding:"max=1000"`
+	Capacity    int         `json:"capacity" binding:"required_without=VenueID,omitempty,min=1,max=1000000"`
+	Price       *float64    `json:"price" binding:"required,min=0,max=1000000"`
+	ImageURL    string      `json:"image_url" binding:"omitempty,http_url,max=2048"`
+}
+
+// UpdateSeriesRequest changes the series template and/or its schedule. Template
+// changes apply to upcoming occurrences that were not edited individually;
+// schedule changes regenerate them.
+type UpdateSeriesRequest struct {
+	Name        *string      `json:"name" binding:"omitnil,notblank,max=200"`
+	Description *string      `json:"description" binding:"omitnil,notblank,max=5000"`
+	CategoryID  *uuid.UUID   `json:"category_id" binding:"omitnil,required"`
+	Location    *string      `json:"location" binding:"omitnil,notblank,max=300"`
+	VenueID     *uuid.UUID   `json:"venue_id" binding:"omitnil,required"`
+	Latitude    *float64     `json:"latitude" binding:"required_with=Longitude,omitnil,latitude"`
+	Longitude   *float64     `json:"longitude" binding:"required_with=Latitude,omitnil,longitude"`
+	Capacity    *int         `json:"capacity" binding:"omitnil,min=1,max=1000000"`
+	Price       *float64     `json:"price" binding:"omitnil,min=0,max=1000000"`
+	ImageURL    *string      `json:"image_url" binding:"omitnil,max=2048,len=0|http_url"`
+	StartsAt    *time.Time   `json:"starts_at" binding:"omitnil"`
+	EndsAt      *time.Time   `json:"ends_at" binding:"omitnil"`
+	TimeZone    *string      `json:"time_zone" binding:"omitnil,timezone"`
+	RRule       *string      `json:"rrule" binding:"omitnil,rrule"`
+	ExDates     *[]time.Time `json:"exdates" binding:"omitnil,max=1000"`
+	Status      *string      `json:"status" binding:"omitnil,oneof=draft published cancelled"`
+}
+
+const (
+	// SeriesScopeThis edits only the given occurrence.
+	SeriesScopeThis = "this"
+	// SeriesScopeFuture edits the given occurrence and every later one.
+	SeriesScopeFuture = "future"
+)
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestOccurrenceStatus(t *testing.T) {
+	start := time.Date(2026, 6, 1, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		status string
+		want   string
+	}{
+		{name: "stored without status", status: "", want: EventStatusDraft},
+		{name: "draft", status: EventStatusDraft, want: EventStatusDraft},
+		{name: "published", status: EventStatusPublished, want: EventStatusPublished},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &EventSeries{ID: uuid.New(), Status: tt.status, StartsAt: start, EndsAt: start.Add(time.Hour)}
+			if got := s.Occurrence(start, start).Status; got != tt.want {
+				t.Fatalf("occurrence status = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyTemplateStatus(t *testing.T) {
+	event := Event{Status: EventStatusPublished}
+	(&EventSeries{}).ApplyTemplate(&event)
+	if event.Status != EventStatusPublished {
+		t.Fatalf("series without status changed the occurrence to %q", event.Status)
+	}
+
+	(&EventSeries{Status: EventStatusCancelled}).ApplyTemplate(&event)
+	if event.Status != EventStatusCancelled {
+		t.Fatalf("status = %q, want %q", event.Status, EventStatusCancelled)
+	}
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Venue is a place where events take place. Events referencing it take its
+// location, time zone and capacity unless they set their own.
+type Venue struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	TenantID string    `json:"tenant_id" db:"tenant_id"`
+	Name     string    `json:"name" db:"name"`
+	Address  string    `json:"address" db:"address"`
+	City     string    `json:"city" db:"city"`
+	// Country is an ISO 3166-1 alpha-2 code.
+	Country   string  `json:"country" db:"country"`
+	Latitude  float64 `json:"latitude" db:"latitude"`
+	Longitude float64 `json:"longitude" db:"longitude"`
+	TimeZone  string  `json:"time_zone" db:"time_zone"`
+	// Capacity is the default capacity of events held at the venue.
+	Capacity  int       `json:"capacity" db:"capacity"`
+	Sections  []Section `json:"sections,omitempty" db:"sections"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Section is a part of a venue with its own capacity, e.g. "Platea".
+type Section struct {
+	Name     string `json:"name" db:"name" binding:"required,notblank,max=100"`
+	Capacity int    `json:"capacity" db:"capacity" binding:"required,min=1,max=1000000"`
+}
+
+// Label is the free-text location of events held at the venue.
+func (v *Venue) Label() string {
+	parts := []string{v.Name}
+	for _, part := range []string{v.Address, v.City} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Coordinates returns copies of the venue's latitude and longitude.
+func (v *Venue) Coordinates() (lat, lng *float64) {
+	latitude, longitude := v.Latitude, v.Longitude
+	return &latitude, &longitude
+}
+
+// SectionsCapacity is the combined capacity of the sections.
+func (v *Venue) SectionsCapacity() int {
+	total := 0
+	for _, section := range v.Sections {
+		total += section.Capacity
+	}
+	return total
+}
+
+type CreateVenueRequest struct {
+	Name      string    `json:"name" binding:"required,notblank,max=200"`
+	Address   string    `json:"address" binding:"required,notblank,max=300"`
+	City      string    `json:"city" binding:"required,notblank,max=100"`
+	Country   string    `json:"country" binding:"required,iso3166_1_alpha2"`
+	Latitude  *float64  `json:"latitude" binding:"required,latitude"`
+	Longitude *float64  `json:"longitude" binding:"required,longitude"`
+	TimeZone  string    `json:"time_zone" binding:"required,timezone"`
+	Capacity  int       `json:"capacity" binding:"required,min=1,max=1000000"`
+	Sections  []Section `json:"sections" binding:"max=100,dive"`
+}
+
+// UpdateVenueRequest is a partial update; sections, when present, replace
+// the current ones. That the sections fit the capacity is checked on the
+// merged venue.
+type UpdateVenueRequest struct {
+	Name      *string    `json:"name" binding:"omitnil,notblank,max=200"`
+	Address   *string    `json:"address" binding:"omitnil,notblank,max=300"`
+	City      *string    `json:"city" binding:"omitnil,notblank,max=100"`
+	Country   *string    `json:"country" binding:"omitnil,iso3166_1_alpha2"`
+	Latitude  *float64   `json:"latitude" binding:"omitnil,latitude"`
+	Longitude *float64   `json:"longitude" binding:"omitnil,longitude"`
+	TimeZone  *string    `json:"time_zone" binding:"omitnil,timezone"`
+	Capacity  *int       `json:"capacity" binding:"omitnil,min=1,max=1000000"`
+	Sections  *[]Section `json:"sections" binding:"omitnil,max=100,dive"`
+}
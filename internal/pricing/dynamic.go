@@ -0,0 +1,71 @@
+package pricing
+
+import (
+	"time"
+
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// Adjustment is the effective price of an event under its pricing rules at
+// some point in time. Price is rounded to cents.
+type Adjustment struct {
+	BasePrice float64 `json:"base_price"`
+	// Percent is the sum of the time and sell-through adjustments in effect.
+	Percent     float64 `json:"percent"`
+	SoldPercent float64 `json:"sold_percent"`
+	Price       float64 `json:"price"`
+}
+
+// Evaluate prices event under rules at now, when sold of its tickets are
+// sold.
+func Evaluate(event *model.Event, rules *model.PricingRules, sold int, now time.Time) Adjustment {
+	var soldPercent float64
+	if event.Capacity > 0 {
+		soldPercent = float64(sold) * 100 / float64(event.Capacity)
+	}
+
+	// The time step closest to the start among those already reached
+	var timeStep *model.TimeStep
+	for i, step := range rules.TimeSteps {
+		from := event.StartsAt.Add(-time.Duration(step.HoursBefore) * time.Hour)
+		if now.Before(from) {
+			continue
+		}
+		if timeStep == nil || step.HoursBefore < timeStep.HoursBefore {
+			timeStep = &rules.TimeSteps[i]
+		}
+	}
+	// The highest sell-through step reached
+	var sellStep *model.SellThroughStep
+	for i, step := range rules.SellThroughSteps {
+		if soldPercent < step.SoldPercent {
+			continue
+		}
+		if sellStep == nil || step.SoldPercent > sellStep.SoldPercent {
+			sellStep = &rules.SellThroughSteps[i]
+		}
+	}
+
+	percent := decimal.Zero
+	if timeStep != nil {
+		percent = percent.Add(decimal.NewFromFloat(timeStep.Percent))
+	}
+	if sellStep != nil {
+		percent = percent.Add(decimal.NewFromFloat(sellStep.Percent))
+	}
+
+	price := decimal.NewFromFloat(event.Price).Mul(hundred.Add(percent)).Div(hundred)
+	if rules.Floor != nil {
+		price = decimal.Max(price, decimal.NewFromFloat(*rules.Floor))
+	}
+	if rules.Ceiling != nil {
+		price = decimal.Min(price, decimal.NewFromFloat(*rules.Ceiling))
+	}
+	return Adjustment{
+		BasePrice:   event.Price,
+		Percent:     percent.InexactFloat64(),
+		SoldPercent: cents(decimal.NewFromFloat(soldPercent)).InexactFloat64(),
+		Price:       cents(price).InexactFloat64(),
+	}
+}
@@ -0,0 +1,116 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+)
+
+func ptr(f float64) *float64 {
+	return &f
+}
+
+func TestEvaluate(t *testing.T) {
+	start := time.Date(2026, 6, 1, 20, 0, 0, 0, time.UTC)
+	event := &model.Event{Price: 50, Capacity: 200, StartsAt: start}
+
+	tests := []struct {
+		name      string
+		rules     model.PricingRules
+		sold      int
+		now       time.Time
+		wantPct   float64
+		wantSold  float64
+		wantPrice float64
+	}{
+		{
+			name:      "no steps",
+			now:       start.Add(-30 * 24 * time.Hour),
+			wantPrice: 50,
+		},
+		{
+			name:      "time step not reached",
+			rules:     model.PricingRules{TimeSteps: []model.TimeStep{{HoursBefore: 24, Percent: 20}}},
+			now:       start.Add(-25 * time.Hour),
+			wantPrice: 50,
+		},
+		{
+			name:      "closest time step to the start wins",
+			rules:     model.PricingRules{TimeSteps: []model.TimeStep{{HoursBefore: 24, Percent: 20}, {HoursBefore: 168, Percent: -10}, {HoursBefore: 2, Percent: 50}}},
+			now:       start.Add(-12 * time.Hour),
+			wantPct:   20,
+			wantPrice: 60,
+		},
+		{
+			name:      "highest sell-through step reached wins",
+			rules:     model.PricingRules{SellThroughSteps: []model.SellThroughStep{{SoldPercent: 50, Percent: 10}, {SoldPercent: 75, Percent: 25}, {SoldPercent: 90, Percent: 40}}},
+			sold:      150,
+			now:       start.Add(-30 * 24 * time.Hour),
+			wantPct:   25,
+			wantSold:  75,
+			wantPrice: 62.5,
+		},
+		{
+			name: "time and sell-through steps add up",
+			rules: model.PricingRules{
+				TimeSteps:        []model.TimeStep{{HoursBefore: 168, Percent: -15}},
+				SellThroughSteps: []model.SellThroughStep{{SoldPercent: 10, Percent: 5}},
+			},
+			sold:      21,
+			now:       start.Add(-48 * time.Hour),
+			wantPct:   -10,
+			wantSold:  10.5,
+			wantPrice: 45,
+		},
+		{
+			name:      "floor",
+			rules:     model.PricingRules{TimeSteps: []model.TimeStep{{HoursBefore: 24, Percent: -50}}, Floor: ptr(40)},
+			now:       start.Add(-time.Hour),
+			wantPct:   -50,
+			wantPrice: 40,
+		},
+		{
+			name:      "ceiling",
+			rules:     model.PricingRules{SellThroughSteps: []model.SellThroughStep{{SoldPercent: 1, Percent: 100}}, Ceiling: ptr(80)},
+			sold:      200,
+			now:       start.Add(-30 * 24 * time.Hour),
+			wantPct:   100,
+			wantSold:  100,
+			wantPrice: 80,
+		},
+		{
+			name:      "rounded to cents",
+			rules:     model.PricingRules{TimeSteps: []model.TimeStep{{HoursBefore: 24, Percent: 33.333}}},
+			now:       start.Add(-time.Hour),
+			wantPct:   33.333,
+			wantPrice: 66.67,
+		},
+		{
+			name:      "sold percent rounded",
+			rules:     model.PricingRules{SellThroughSteps: []model.SellThroughStep{{SoldPercent: 50, Percent: 10}}},
+			sold:      1,
+			now:       start.Add(-30 * 24 * time.Hour),
+			wantSold:  0.5,
+			wantPrice: 50,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Evaluate(event, &tt.rules, tt.sold, tt.now)
+			want := Adjustment{BasePrice: 50, Percent: tt.wantPct, SoldPercent: tt.wantSold, Price: tt.wantPrice}
+			if got != want {
+				t.Fatalf("Evaluate = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestEvaluateWithoutCapacity(t *testing.T) {
+	event := &model.Event{Price: 30, StartsAt: time.Now().Add(time.Hour)}
+	rules := &model.PricingRules{SellThroughSteps: []model.SellThroughStep{{SoldPercent: 1, Percent: 50}}}
+	got := Evaluate(event, rules, 10, time.Now())
+	if got.SoldPercent != 0 || got.Price != 30 {
+		t.Fatalf("Evaluate = %+v, want no sell-through adjustment", got)
+	}
+}
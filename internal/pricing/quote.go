@@ -0,0 +1,209 @@
+// Package pricing computes what buying tickets of an event costs. Amounts
+// are computed with exact decimal arithmetic and rounded to cents, half away
+// from zero, line by line.
+package pricing
+
+import (
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// Amount is an exact amount of money. It is a JSON string with two
+// decimals, e.g. "19.90", so clients need not parse it into a float.
+type Amount struct {
+	decimal.Decimal
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + a.StringFixed(2) + `"`), nil
+}
+
+// Float64 returns the nearest float64, for storing alongside other prices.
+func (a Amount) Float64() float64 {
+	return a.InexactFloat64()
+}
+
+// Charge is one line of fees or taxes.
+type Charge struct {
+	Name string `json:"name"`
+	// Rate is the percentage charged on the base or the taxable amount.
+	Rate      float64 `json:"rate,omitempty"`
+	PerTicket float64 `json:"per_ticket,omitempty"`
+	Amount    Amount  `json:"amount"`
+}
+
+// Tax statuses of a quote, telling whether Taxes can be trusted for
+// invoicing.
+const (
+	// TaxStatusTaxed means the taxes of the event's country were applied.
+	TaxStatusTaxed = "taxed"
+	// TaxStatusNoJurisdiction means the event has no venue, so its country
+	// and taxes are unknown.
+	TaxStatusNoJurisdiction = "no_jurisdiction"
+	// TaxStatusNoRates means no taxes are configured for the event's country.
+	TaxStatusNoRates = "no_rates"
+)
+
+// Charges are the fees and taxes that apply to the tickets of an event.
+// TaxStatus says how the taxes were determined; Country is the tax
+// jurisdiction, if known.
+type Charges struct {
+	Fees      []model.ServiceFee
+	Taxes     []model.Tax
+	TaxFees   bool
+	Country   string
+	TaxStatus string
+}
+
+// SeatLine is the price of one seat of a quote, that of its zone.
+type SeatLine struct {
+	Seat  string `json:"seat"`
+	Zone  string `json:"zone"`
+	Price Amount `json:"price"`
+}
+
+// Quote is the price of Quantity tickets of an event: the subtotal, at
+// UnitPrice or at the price of each of Seats, less the discount of a promo
+// code, is the Base on which fees and then taxes are charged.
+type Quote struct {
+	EventID   uuid.UUID `json:"event_id"`
+	Quantity  int       `json:"quantity"`
+	UnitPrice *Amount   `json:"unit_price,omitempty"`
+	// Seats is set instead of UnitPrice for assigned seating.
+	Seats      []SeatLine `json:"seats,omitempty"`
+	Subtotal   Amount     `json:"subtotal"`
+	Code       string     `json:"code,omitempty"`
+	Discount   Amount     `json:"discount"`
+	Base       Amount     `json:"base"`
+	Fees       []Charge   `json:"fees"`
+	FeesTotal  Amount     `json:"fees_total"`
+	Taxes      []Charge   `json:"taxes"`
+	TaxesTotal Amount     `json:"taxes_total"`
+	Total      Amount     `json:"total"`
+	// TaxStatus is set once charges are applied. Anything but
+	// TaxStatusTaxed means no taxes were charged and the quote must not be
+	// invoiced as is.
+	TaxStatus  string `json:"tax_status,omitempty"`
+	TaxCountry string `json:"tax_country,omitempty"`
+
+	promo   *model.PromoCode
+	charges Charges
+}
+
+// New quotes quantity tickets of event at its effective price, or at its
+// price if it has no pricing rules, without discount or charges.
+func New(event *model.Event, quantity int) *Quote {
+	unitPrice := event.Price
+	if event.EffectivePrice != nil {
+		unitPrice = *event.EffectivePrice
+	}
+	q := &Quote{
+		EventID:   event.ID,
+		Quantity:  quantity,
+		UnitPrice: &Amount{decimal.NewFromFloat(unitPrice)},
+	}
+	q.settle()
+	return q
+}
+
+// NewForSeats quotes seats of event, each at the price of its zone, without
+// discount or charges. If pricing rules moved the price of the event, zone
+// prices move in the same proportion.
+func NewForSeats(event *model.Event, seats []model.Seat) *Quote {
+	factor := decimal.NewFromInt(1)
+	if event.EffectivePrice != nil && event.Price > 0 {
+		factor = decimal.NewFromFloat(*event.EffectivePrice).Div(decimal.NewFromFloat(event.Price))
+	}
+
+	q := &Quote{
+		EventID:  event.ID,
+		Quantity: len(seats),
+		Seats:    make([]SeatLine, 0, len(seats)),
+	}
+	for _, seat := range seats {
+		price := cents(decimal.NewFromFloat(seat.Price).Mul(factor))
+		q.Seats = append(q.Seats, SeatLine{Seat: seat.ID, Zone: seat.Zone, Price: Amount{price}})
+	}
+	q.settle()
+	return q
+}
+
+// ApplyPromo takes the discount of promo off the subtotal. A fixed discount
+// applies once per order, never more than the subtotal. Callers check first
+// that the code is redeemable for the event.
+func (q *Quote) ApplyPromo(promo *model.PromoCode) {
+	q.promo = promo
+	q.settle()
+}
+
+// ApplyCharges adds the fees and taxes of the event to the quote.
+func (q *Quote) ApplyCharges(charges Charges) {
+	q.charges = charges
+	q.settle()
+}
+
+// settle computes every amount from the unit price and quantity or the
+// seat prices, promo code and charges.
+func (q *Quote) settle() {
+	quantity := decimal.NewFromInt(int64(q.Quantity))
+	subtotal := decimal.Zero
+	if q.UnitPrice != nil {
+		subtotal = cents(q.UnitPrice.Mul(quantity))
+	}
+	for _, line := range q.Seats {
+		subtotal = subtotal.Add(line.Price.Decimal)
+	}
+
+	discount := decimal.Zero
+	if q.promo != nil {
+		q.Code = q.promo.Code
+		value := decimal.NewFromFloat(q.promo.Value)
+		switch q.promo.Kind {
+		case model.PromoPercentage:
+			discount = cents(subtotal.Mul(value).Div(hundred))
+		case model.PromoFixed:
+			discount = cents(value)
+		}
+		discount = decimal.Min(discount, subtotal)
+	}
+	base := subtotal.Sub(discount)
+
+	q.Fees = make([]Charge, 0, len(q.charges.Fees))
+	fees := decimal.Zero
+	for _, fee := range q.charges.Fees {
+		amount := base.Mul(decimal.NewFromFloat(fee.Percent)).Div(hundred).
+			Add(decimal.NewFromFloat(fee.PerTicket).Mul(quantity))
+		amount = cents(amount)
+		fees = fees.Add(amount)
+		q.Fees = append(q.Fees, Charge{Name: fee.Name, Rate: fee.Percent, PerTicket: fee.PerTicket, Amount: Amount{amount}})
+	}
+
+	taxable := base
+	if q.charges.TaxFees {
+		taxable = taxable.Add(fees)
+	}
+	q.Taxes = make([]Charge, 0, len(q.charges.Taxes))
+	taxes := decimal.Zero
+	for _, tax := range q.charges.Taxes {
+		amount := cents(taxable.Mul(decimal.NewFromFloat(tax.Rate)).Div(hundred))
+		taxes = taxes.Add(amount)
+		q.Taxes = append(q.Taxes, Charge{Name: tax.Name, Rate: tax.Rate, Amount: Amount{amount}})
+	}
+
+	q.Subtotal = Amount{subtotal}
+	q.Discount = Amount{discount}
+	q.Base = Amount{base}
+	q.FeesTotal = Amount{fees}
+	q.TaxesTotal = Amount{taxes}
+	q.Total = Amount{base.Add(fees).Add(taxes)}
+	q.TaxStatus = q.charges.TaxStatus
+	q.TaxCountry = q.charges.Country
+}
+
+// cents rounds amount to cents, half away from zero.
+func cents(amount decimal.Decimal) decimal.Decimal {
+	return amount.Round(2)
+}
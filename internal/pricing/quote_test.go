@@ -0,0 +1,241 @@
+package pricing
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name      string
+		unitPrice float64
+		effective *float64
+		quantity  int
+		promo     *model.PromoCode
+		charges   *Charges
+		// Amounts as rendered in JSON.
+		subtotal, discount, base, fees, taxes, total string
+	}{
+		{
+			name:      "no discount or charges",
+			unitPrice: 19.99, quantity: 3,
+			subtotal: "59.97", discount: "0.00", base: "59.97", fees: "0.00", taxes: "0.00", total: "59.97",
+		},
+		{
+			name:      "effective price",
+			unitPrice: 20, effective: ptr(24.5), quantity: 2,
+			subtotal: "49.00", discount: "0.00", base: "49.00", fees: "0.00", taxes: "0.00", total: "49.00",
+		},
+		{
+			name:      "float noise does not leak into cents",
+			unitPrice: 0.1, quantity: 3,
+			subtotal: "0.30", discount: "0.00", base: "0.30", fees: "0.00", taxes: "0.00", total: "0.30",
+		},
+		{
+			name:      "percentage discount rounds half away from zero",
+			unitPrice: 10.25, quantity: 1,
+			promo:    &model.PromoCode{Code: "HALF", Kind: model.PromoPercentage, Value: 50},
+			subtotal: "10.25", discount: "5.13", base: "5.12", fees: "0.00", taxes: "0.00", total: "5.12",
+		},
+		{
+			name:      "fixed discount capped at subtotal",
+			unitPrice: 5, quantity: 2,
+			promo:    &model.PromoCode{Code: "GIFT", Kind: model.PromoFixed, Value: 25},
+			subtotal: "10.00", discount: "10.00", base: "0.00", fees: "0.00", taxes: "0.00", total: "0.00",
+		},
+		{
+			name:      "fees and taxes rounded line by line",
+			unitPrice: 33.33, quantity: 3,
+			charges: &Charges{
+				Fees:      []model.ServiceFee{{Name: "service", Percent: 7.5}, {Name: "handling", PerTicket: 0.35}},
+				Taxes:     []model.Tax{{Name: "IVA", Rate: 21}, {Name: "local", Rate: 0.5}},
+				TaxStatus: TaxStatusTaxed,
+			},
+			// 99.99 * 7.5% = 7.49925 -> 7.50; 3 * 0.35 = 1.05
+			// 99.99 * 21% = 20.9979 -> 21.00; 99.99 * 0.5% = 0.49995 -> 0.50
+			subtotal: "99.99", discount: "0.00", base: "99.99", fees: "8.55", taxes: "21.50", total: "130.04",
+		},
+		{
+			name:      "taxes on fees",
+			unitPrice: 100, quantity: 1,
+			charges: &Charges{
+				Fees:      []model.ServiceFee{{Name: "service", Percent: 10}},
+				Taxes:     []model.Tax{{Name: "VAT", Rate: 20}},
+				TaxFees:   true,
+				TaxStatus: TaxStatusTaxed,
+			},
+			subtotal: "100.00", discount: "0.00", base: "100.00", fees: "10.00", taxes: "22.00", total: "132.00",
+		},
+		{
+			name:      "charges on the discounted base",
+			unitPrice: 40, quantity: 2,
+			promo: &model.PromoCode{Code: "TEN", Kind: model.PromoFixed, Value: 10},
+			charges: &Charges{
+				Fees:      []model.ServiceFee{{Name: "service", Percent: 10}},
+				Taxes:     []model.Tax{{Name: "VAT", Rate: 10}},
+				TaxStatus: TaxStatusTaxed,
+			},
+			subtotal: "80.00", discount: "10.00", base: "70.00", fees: "7.00", taxes: "7.00", total: "84.00",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := New(&model.Event{Price: tt.unitPrice, EffectivePrice: tt.effective}, tt.quantity)
+			if tt.promo != nil {
+				q.ApplyPromo(tt.promo)
+			}
+			if tt.charges != nil {
+				q.ApplyCharges(*tt.charges)
+			}
+
+			amounts := []struct {
+				field string
+				got   Amount
+				want  string
+			}{
+				{"subtotal", q.Subtotal, tt.subtotal},
+				{"discount", q.Discount, tt.discount},
+				{"base", q.Base, tt.base},
+				{"fees_total", q.FeesTotal, tt.fees},
+				{"taxes_total", q.TaxesTotal, tt.taxes},
+				{"total", q.Total, tt.total},
+			}
+			for _, a := range amounts {
+				got, err := a.got.MarshalJSON()
+				if err != nil {
+					t.Fatalf("%s: %v", a.field, err)
+				}
+				if string(got) != `"`+a.want+`"` {
+					t.Errorf("%s = %s, want %q", a.field, got, a.want)
+				}
+			}
+		})
+	}
+}
+
+func TestQuoteSeats(t *testing.T) {
+	vip := model.Seat{ID: "Platea:A:1", Zone: "VIP", Price: 120.5}
+	general := model.Seat{ID: "Platea:B:1", Zone: "GEN", Price: 60}
+	tests := []struct {
+		name      string
+		price     float64
+		effective *float64
+		seats     []model.Seat
+		promo     *model.PromoCode
+		charges   *Charges
+		// Seat prices and amounts as rendered in JSON.
+		seatPrices                      []string
+		subtotal, discount, fees, total string
+	}{
+		{
+			name: "each seat at its zone price", price: 80,
+			seats:      []model.Seat{vip, general, general},
+			seatPrices: []string{"120.50", "60.00", "60.00"},
+			subtotal:   "240.50", discount: "0.00", fees: "0.00", total: "240.50",
+		},
+		{
+			name: "zone prices follow the effective price", price: 80, effective: ptr(100),
+			seats:      []model.Seat{vip, general},
+			seatPrices: []string{"150.63", "75.00"},
+			subtotal:   "225.63", discount: "0.00", fees: "0.00", total: "225.63",
+		},
+		{
+			name: "free event keeps zone prices", price: 0, effective: ptr(10),
+			seats:      []model.Seat{general},
+			seatPrices: []string{"60.00"},
+			subtotal:   "60.00", discount: "0.00", fees: "0.00", total: "60.00",
+		},
+		{
+			name: "discount and fees per seat", price: 80,
+			seats: []model.Seat{vip, general},
+			promo: &model.PromoCode{Code: "TEN", Kind: model.PromoPercentage, Value: 10},
+			charges: &Charges{
+				Fees:      []model.ServiceFee{{Name: "handling", PerTicket: 2}},
+				TaxStatus: TaxStatusNoJurisdiction,
+			},
+			seatPrices: []string{"120.50", "60.00"},
+			// 180.50 - 18.05 = 162.45, plus 2 seats * 2.00
+			subtotal: "180.50", discount: "18.05", fees: "4.00", total: "166.45",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewForSeats(&model.Event{Price: tt.price, EffectivePrice: tt.effective}, tt.seats)
+			if tt.promo != nil {
+				q.ApplyPromo(tt.promo)
+			}
+			if tt.charges != nil {
+				q.ApplyCharges(*tt.charges)
+			}
+
+			if q.Quantity != len(tt.seats) || q.UnitPrice != nil {
+				t.Fatalf("quantity = %d, unit price = %v, want %d and none", q.Quantity, q.UnitPrice, len(tt.seats))
+			}
+			for i, line := range q.Seats {
+				if line.Seat != tt.seats[i].ID || line.Zone != tt.seats[i].Zone || line.Price.StringFixed(2) != tt.seatPrices[i] {
+					t.Errorf("seat %d = %s %s %s, want %s %s %s", i, line.Seat, line.Zone, line.Price.StringFixed(2), tt.seats[i].ID, tt.seats[i].Zone, tt.seatPrices[i])
+				}
+			}
+			amounts := map[string]struct{ got, want string }{
+				"subtotal":   {q.Subtotal.StringFixed(2), tt.subtotal},
+				"discount":   {q.Discount.StringFixed(2), tt.discount},
+				"fees_total": {q.FeesTotal.StringFixed(2), tt.fees},
+				"total":      {q.Total.StringFixed(2), tt.total},
+			}
+			for field, a := range amounts {
+				if a.got != a.want {
+					t.Errorf("%s = %s, want %s", field, a.got, a.want)
+				}
+			}
+		})
+	}
+}
+
+func TestQuoteJSON(t *testing.T) {
+	q := NewForSeats(&model.Event{Price: 10}, []model.Seat{{ID: "A:1:1", Zone: "GEN", Price: 12.5}})
+	data, err := json.Marshal(q)
+	if err != nil {
+		t.Fatalf("marshaling quote: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshaling quote: %v", err)
+	}
+	if got["total"] != "12.50" || got["subtotal"] != "12.50" {
+		t.Fatalf("total = %#v, subtotal = %#v, want decimal strings", got["total"], got["subtotal"])
+	}
+	if _, ok := got["unit_price"]; ok {
+		t.Fatalf("unit_price = %#v, want it omitted for seats", got["unit_price"])
+	}
+	seats, _ := got["seats"].([]any)
+	if len(seats) != 1 || seats[0].(map[string]any)["price"] != "12.50" {
+		t.Fatalf("seats = %#v", got["seats"])
+	}
+}
+
+func TestQuoteTaxStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		charges     Charges
+		wantStatus  string
+		wantCountry string
+	}{
+		{name: "taxed", charges: Charges{Taxes: []model.Tax{{Name: "IVA", Rate: 21}}, Country: "ES", TaxStatus: TaxStatusTaxed}, wantStatus: TaxStatusTaxed, wantCountry: "ES"},
+		{name: "no venue", charges: Charges{TaxStatus: TaxStatusNoJurisdiction}, wantStatus: TaxStatusNoJurisdiction},
+		{name: "no rates", charges: Charges{Country: "PT", TaxStatus: TaxStatusNoRates}, wantStatus: TaxStatusNoRates, wantCountry: "PT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := New(&model.Event{Price: 10}, 1)
+			if q.TaxStatus != "" {
+				t.Fatalf("TaxStatus before charges = %q, want empty", q.TaxStatus)
+			}
+			q.ApplyCharges(tt.charges)
+			if q.TaxStatus != tt.wantStatus || q.TaxCountry != tt.wantCountry {
+				t.Fatalf("got %s/%s, want %s/%s", q.TaxStatus, q.TaxCountry, tt.wantStatus, tt.wantCountry)
+			}
+		})
+	}
+}
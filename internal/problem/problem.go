@@ -0,0 +1,134 @@
+// Package problem writes error responses as RFC 7807 problem details
+// (application/problem+json).
+package problem
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/jhonathanssegura/ticket-events/internal/logging"
+	"github.com/jhonathanssegura/ticket-events/internal/validation"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem types. They are relative URIs identifying the kind of error and
+// are stable, so clients can switch on them.
+const (
+	TypeBadRequest      = "/problems/bad-request"
+	TypeValidation      = "/problems/validation"
+	TypeUnauthorized    = "/problems/unauthorized"
+	TypeForbidden       = "/problems/forbidden"
+	TypeNotFound        = "/problems/not-found"
+	TypeConflict        = "/problems/conflict"
+	TypeTooLarge        = "/problems/payload-too-large"
+	TypeUnsupportedType = "/problems/unsupported-media-type"
+	TypeUnprocessable   = "/problems/unprocessable"
+	TypeTooManyRequests = "/problems/too-many-requests"
+	TypeInternal        = "/problems/internal"
+)
+
+// Problem is an RFC 7807 problem details object. RequestID and Errors are
+// extension members.
+type Problem struct {
+	Type      string                  `json:"type"`
+	Title     string                  `json:"title"`
+	Status    int                     `json:"status"`
+	Detail    string                  `json:"detail,omitempty"`
+	Instance  string                  `json:"instance,omitempty"`
+	RequestID string                  `json:"request_id,omitempty"`
+	Errors    []validation.FieldError `json:"errors,omitempty"`
+}
+
+var titles = map[string]i18n.Key{
+	TypeBadRequest:      i18n.ProblemBadRequest,
+	TypeValidation:      i18n.ProblemValidation,
+	TypeUnauthorized:    i18n.ProblemUnauthorized,
+	TypeForbidden:       i18n.ProblemForbidden,
+	TypeNotFound:        i18n.ProblemNotFound,
+	TypeConflict:        i18n.ProblemConflict,
+	TypeTooLarge:        i18n.ProblemTooLarge,
+	TypeUnsupportedType: i18n.ProblemUnsupportedType,
+	TypeUnprocessable:   i18n.ProblemUnprocessable,
+	TypeTooManyRequests: i18n.ProblemTooManyRequests,
+	TypeInternal:        i18n.ProblemInternal,
+}
+
+// Respond writes p and aborts the handler chain. Type, Title, Instance and
+// RequestID are filled in when empty; the title is localized.
+func Respond(c *gin.Context, p Problem) {
+	if p.Type == "" {
+		p.Type = TypeInternal
+	}
+	if p.Title == "" {
+		p.Title = i18n.T(c.Request.Context(), titles[p.Type])
+	}
+	if p.Instance == "" {
+		p.Instance = c.Request.URL.Path
+	}
+	if p.RequestID == "" {
+		p.RequestID = logging.RequestID(c.Request.Context())
+	}
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// respond writes a problem of typ whose detail is the message for key in the
+// request's locale.
+func respond(c *gin.Context, typ string, status int, detail i18n.Key) {
+	Respond(c, Problem{Type: typ, Status: status, Detail: i18n.T(c.Request.Context(), detail)})
+}
+
+func BadRequest(c *gin.Context, detail i18n.Key) {
+	respond(c, TypeBadRequest, http.StatusBadRequest, detail)
+}
+
+// Validation reports invalid input field by field.
+func Validation(c *gin.Context, detail i18n.Key, errs []validation.FieldError) {
+	Respond(c, Problem{
+		Type:   TypeValidation,
+		Status: http.StatusBadRequest,
+		Detail: i18n.T(c.Request.Context(), detail),
+		Errors: errs,
+	})
+}
+
+func Unauthorized(c *gin.Context, detail i18n.Key) {
+	respond(c, TypeUnauthorized, http.StatusUnauthorized, detail)
+}
+
+func Forbidden(c *gin.Context, detail i18n.Key) {
+	respond(c, TypeForbidden, http.StatusForbidden, detail)
+}
+
+func NotFound(c *gin.Context, detail i18n.Key) {
+	respond(c, TypeNotFound, http.StatusNotFound, detail)
+}
+
+func Conflict(c *gin.Context, detail i18n.Key) {
+	respond(c, TypeConflict, http.StatusConflict, detail)
+}
+
+func UnsupportedType(c *gin.Context, detail i18n.Key) {
+	respond(c, TypeUnsupportedType, http.StatusUnsupportedMediaType, detail)
+}
+
+func Unprocessable(c *gin.Context, detail i18n.Key) {
+	respond(c, TypeUnprocessable, http.StatusUnprocessableEntity, detail)
+}
+
+func TooLarge(c *gin.Context, detail i18n.Key) {
+	respond(c, TypeTooLarge, http.StatusRequestEntityTooLarge, detail)
+}
+
+func TooManyRequests(c *gin.Context, detail i18n.Key) {
+	respond(c, TypeTooManyRequests, http.StatusTooManyRequests, detail)
+}
+
+// Internal reports a server-side failure. The cause is never sent to the
+// client: callers log it, and the request ID in the response lets support
+// find that log line.
+func Internal(c *gin.Context, detail i18n.Key) {
+	respond(c, TypeInternal, http.StatusInternalServerError, detail)
+}
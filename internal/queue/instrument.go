@@ -0,0 +1,100 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/jhonathanssegura/ticket-events/internal/metrics"
+	"github.com/jhonathanssegura/ticket-events/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewSQSClient builds an SQSClient for queueURL whose calls are instrumented.
+func NewSQSClient(cfg aws.Config, queueURL string) *SQSClient {
+	return &SQSClient{
+		Client: sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+			o.APIOptions = append(o.APIOptions, addInstrumentation)
+		}),
+		QueueURL: queueURL,
+	}
+}
+
+// addInstrumentation records metrics and a span for every SQS operation. For
+// SendMessage the span context is injected into the message attributes so
+// consumers can continue the trace.
+func addInstrumentation(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("TicketEventsInstrumentation",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			operation := middleware.GetOperationName(ctx)
+
+			attrs := []attribute.KeyValue{
+				semconv.MessagingSystemAWSSqs,
+				semconv.RPCSystemKey.String("aws-api"),
+				semconv.RPCService("SQS"),
+				semconv.RPCMethod(operation),
+			}
+			kind := trace.SpanKindClient
+			switch input := in.Parameters.(type) {
+			case *sqs.SendMessageInput:
+				kind = trace.SpanKindProducer
+				attrs = append(attrs, semconv.MessagingOperationTypePublish, semconv.MessagingDestinationName(aws.ToString(input.QueueUrl)))
+			case *sqs.ReceiveMessageInput:
+				kind = trace.SpanKindConsumer
+				attrs = append(attrs, semconv.MessagingOperationTypeReceive, semconv.MessagingDestinationName(aws.ToString(input.QueueUrl)))
+			}
+
+			ctx, span := tracing.Tracer().Start(ctx, "SQS."+operation, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+
+			if input, ok := in.Parameters.(*sqs.SendMessageInput); ok {
+				if input.MessageAttributes == nil {
+					input.MessageAttributes = make(map[string]types.MessageAttributeValue)
+				}
+				otel.GetTextMapPropagator().Inject(ctx, attributeCarrier(input.MessageAttributes))
+			}
+
+			start := time.Now()
+			out, md, err := next.HandleInitialize(ctx, in)
+			metrics.ObserveSQS(operation, time.Since(start), err)
+			switch res := out.Result.(type) {
+			case *sqs.SendMessageOutput:
+				span.SetAttributes(semconv.MessagingMessageID(aws.ToString(res.MessageId)))
+			case *sqs.ReceiveMessageOutput:
+				metrics.AddSQSMessagesReceived(len(res.Messages))
+			}
+			tracing.End(span, err)
+			return out, md, err
+		}), middleware.Before)
+}
+
+// attributeCarrier adapts SQS message attributes to the OpenTelemetry
+// propagation API.
+type attributeCarrier map[string]types.MessageAttributeValue
+
+func (c attributeCarrier) Get(key string) string {
+	if v, ok := c[key]; ok {
+		return aws.ToString(v.StringValue)
+	}
+	return ""
+}
+
+func (c attributeCarrier) Set(key, value string) {
+	c[key] = types.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+}
+
+func (c attributeCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -4,55 +4,156 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/jhonathanssegura/ticket-events/internal/logging"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	// RequestIDAttribute is the SQS message attribute carrying the ID of the
+	// HTTP request that produced the message.
+	RequestIDAttribute = "X-Request-ID"
+	// TenantAttribute is the SQS message attribute carrying the tenant the
+	// message belongs to, so consumers can filter or route per brand.
+	TenantAttribute = "X-Tenant-ID"
 )
 
 type EventMessage struct {
 	EventID   string `json:"event_id"`
 	EventName string `json:"event_name"`
 	Action    string `json:"action"`
+	TenantID  string `json:"tenant_id"`
+
+	// RequestID is filled from the message attributes on receive.
+	RequestID string `json:"-"`
+
+	attributes attributeCarrier
+}
+
+// Context returns parent enriched with the request ID, tenant and trace
+// context the producer attached, so processing the message continues the
+// original trace within the right tenant.
+func (m EventMessage) Context(parent context.Context) context.Context {
+	ctx := parent
+	if m.attributes != nil {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, m.attributes)
+	}
+	if m.RequestID != "" {
+		ctx = logging.WithRequestID(ctx, m.RequestID)
+	}
+	if m.TenantID != "" {
+		ctx = tenant.WithTenant(ctx, m.TenantID)
+	}
+	return ctx
 }
 
+// asyncSendTimeout bounds each background publish so a stuck SQS call cannot
+// hold up shutdown forever.
+const asyncSendTimeout = 10 * time.Second
+
 type SQSClient struct {
 	Client   *sqs.Client
 	QueueURL string
+
+	pending sync.WaitGroup
 }
 
-func (s *SQSClient) SendMessage(message string) error {
-	ctx := context.Background()
-	_, err := s.Client.SendMessage(ctx, &sqs.SendMessageInput{
-		QueueUrl:    aws.String(s.QueueURL),
-		MessageBody: aws.String(message),
-	})
-	if err != nil {
-		return fmt.Errorf("error sending SQS message: %w", err)
+func (s *SQSClient) SendMessage(ctx context.Context, message string) error {
+	return s.send(ctx, message)
+}
+
+// SendMessageAsync publishes message in the background so the HTTP response
+// does not wait on SQS. Use Flush to wait for pending publishes. Values in ctx
+// such as the request ID are kept, but its cancellation is not.
+func (s *SQSClient) SendMessageAsync(ctx context.Context, message string) {
+	ctx = context.WithoutCancel(ctx)
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		ctx, cancel := context.WithTimeout(ctx, asyncSendTimeout)
+		defer cancel()
+		if err := s.send(ctx, message); err != nil {
+			slog.ErrorContext(ctx, "error publicando mensaje en SQS", "error", err)
+		}
+	}()
+}
+
+// Flush blocks until every publish started with SendMessageAsync has finished
+// or ctx is done.
+func (s *SQSClient) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("pending SQS messages not flushed: %w", ctx.Err())
 	}
-	return nil
 }
 
 func (s *SQSClient) SendEventMessage(ctx context.Context, msg EventMessage) error {
+	if msg.TenantID == "" {
+		msg.TenantID = tenant.FromContext(ctx)
+	}
 	body, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("error marshaling SQS message: %w", err)
 	}
+	return s.send(ctx, string(body))
+}
 
-	_, err = s.Client.SendMessage(ctx, &sqs.SendMessageInput{
+func (s *SQSClient) send(ctx context.Context, body string) error {
+	input := &sqs.SendMessageInput{
 		QueueUrl:    aws.String(s.QueueURL),
-		MessageBody: aws.String(string(body)),
-	})
+		MessageBody: aws.String(body),
+	}
+	attrs := make(attributeCarrier)
+	if id := logging.RequestID(ctx); id != "" {
+		attrs.Set(RequestIDAttribute, id)
+	}
+	if id := tenant.FromContext(ctx); id != "" {
+		attrs.Set(TenantAttribute, id)
+	}
+	if len(attrs) > 0 {
+		input.MessageAttributes = attrs
+	}
+
+	out, err := s.Client.SendMessage(ctx, input)
 	if err != nil {
 		return fmt.Errorf("error sending SQS message: %w", err)
 	}
+	slog.DebugContext(ctx, "mensaje publicado en SQS", "message_id", aws.ToString(out.MessageId))
+	return nil
+}
+
+// Ping verifies that the configured queue exists and is reachable.
+func (s *SQSClient) Ping(ctx context.Context) error {
+	_, err := s.Client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(s.QueueURL),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameQueueArn},
+	})
+	if err != nil {
+		return fmt.Errorf("error getting SQS queue attributes: %w", err)
+	}
 	return nil
 }
 
 func (s *SQSClient) ReceiveEventMessages(ctx context.Context, maxMessages int32) ([]EventMessage, error) {
 	resp, err := s.Client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
-		QueueUrl:            aws.String(s.QueueURL),
-		MaxNumberOfMessages: maxMessages,
-		WaitTimeSeconds:     10,
+		QueueUrl:              aws.String(s.QueueURL),
+		MaxNumberOfMessages:   maxMessages,
+		WaitTimeSeconds:       10,
+		MessageAttributeNames: []string{"All"},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error receiving SQS messages: %w", err)
@@ -61,9 +162,16 @@ func (s *SQSClient) ReceiveEventMessages(ctx context.Context, maxMessages int32)
 	var messages []EventMessage
 	for _, m := range resp.Messages {
 		var msg EventMessage
-		if err := json.Unmarshal([]byte(*m.Body), &msg); err == nil {
-			messages = append(messages, msg)
+		if err := json.Unmarshal([]byte(*m.Body), &msg); err != nil {
+			slog.WarnContext(ctx, "mensaje SQS inválido descartado", "message_id", aws.ToString(m.MessageId), "error", err)
+			continue
 		}
+		msg.attributes = attributeCarrier(m.MessageAttributes)
+		msg.RequestID = msg.attributes.Get(RequestIDAttribute)
+		if msg.TenantID == "" {
+			msg.TenantID = msg.attributes.Get(TenantAttribute)
+		}
+		messages = append(messages, msg)
 	}
 	return messages, nil
-} 
\ No newline at end of file
+}
@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+)
+
+// maxAttempts bounds the optimistic-concurrency retries when several replicas
+// update the same bucket at once.
+const maxAttempts = 5
+
+// DynamoStore keeps buckets in DynamoDB so every replica shares the same
+// limits. Updates use conditional writes on the previous timestamp.
+type DynamoStore struct {
+	DB *db.DynamoClient
+}
+
+func NewDynamoStore(db *db.DynamoClient) *DynamoStore {
+	return &DynamoStore{DB: db}
+}
+
+func (s *DynamoStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		state, err := s.DB.GetRateLimitBucket(ctx, key)
+		if err != nil {
+			return Result{}, err
+		}
+
+		var prev bucket
+		if state != nil {
+			prev = bucket{Tokens: state.Tokens, UpdatedAt: state.UpdatedAt}
+		}
+		next, res := take(prev, limit, now)
+
+		err = s.DB.PutRateLimitBucket(ctx, key, db.RateLimitBucket{
+			Tokens:    next.Tokens,
+			UpdatedAt: next.UpdatedAt,
+			ExpiresAt: now.Add(res.Reset + time.Minute),
+		}, state)
+		if errors.Is(err, db.ErrRateLimitConflict) {
+			continue
+		}
+		if err != nil {
+			return Result{}, err
+		}
+		return res, nil
+	}
+	return Result{}, fmt.Errorf("rate limit bucket %q: too much contention", key)
+}
@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	BackendNone     = "none"
+	BackendMemory   = "memory"
+	BackendDynamoDB = "dynamodb"
+)
+
+// Limit is a token bucket refilled at Rate tokens per second up to Burst.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result describes the outcome of taking one token.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next token, when not allowed
+}
+
+// Store keeps token buckets. Implementations must be safe for concurrent use.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// bucket is the persisted state of a token bucket.
+type bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// take refills b up to now and tries to spend one token.
+func take(b bucket, limit Limit, now time.Time) (bucket, Result) {
+	burst := float64(limit.Burst)
+	tokens := burst
+	if !b.UpdatedAt.IsZero() {
+		elapsed := now.Sub(b.UpdatedAt).Seconds()
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		tokens = math.Min(burst, b.Tokens+elapsed*limit.Rate)
+	}
+
+	res := Result{Limit: limit.Burst}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = secondsToDuration((1 - tokens) / limit.Rate)
+	}
+	res.Remaining = int(math.Floor(tokens))
+	res.Reset = secondsToDuration((burst - tokens) / limit.Rate)
+
+	return bucket{Tokens: tokens, UpdatedAt: now}, res
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// ParseLimit parses "<rate>:<burst>", e.g. "5:10" for 5 requests per second
+// with bursts of 10.
+func ParseLimit(s string) (Limit, error) {
+	rateStr, burstStr, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected <rate>:<burst>", s)
+	}
+	rate, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil || rate <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate in %q", s)
+	}
+	burst, err := strconv.Atoi(burstStr)
+	if err != nil || burst < 1 {
+		return Limit{}, fmt.Errorf("invalid burst in %q", s)
+	}
+	return Limit{Rate: rate, Burst: burst}, nil
+}
+
+// ParseRouteLimits parses per-route overrides such as
+// "GET /api/events=2:5,POST /api/events=1:3". Routes use Gin's path syntax.
+func ParseRouteLimits(s string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, limitStr, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid route rate limit %q, expected <METHOD> <path>=<rate>:<burst>", entry)
+		}
+		limit, err := ParseLimit(limitStr)
+		if err != nil {
+			return nil, err
+		}
+		limits[strings.Join(strings.Fields(route), " ")] = limit
+	}
+	return limits, nil
+}
+
+// Policy selects the limit applied to a request. Route overrides, keyed by
+// "<METHOD> <path>", take precedence over the per-client defaults.
+type Policy struct {
+	Anonymous Limit
+	APIKey    Limit
+	Routes    map[string]Limit
+}
+
+func (p Policy) For(route string, apiKey bool) Limit {
+	if limit, ok := p.Routes[route]; ok {
+		return limit
+	}
+	if apiKey {
+		return p.APIKey
+	}
+	return p.Anonymous
+}
@@ -0,0 +1,197 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTake(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limit := Limit{Rate: 2, Burst: 4}
+
+	tests := []struct {
+		name          string
+		bucket        bucket
+		now           time.Time
+		wantAllowed   bool
+		wantRemaining int
+		wantRetry     time.Duration
+		wantReset     time.Duration
+	}{
+		{
+			name:          "new bucket starts full",
+			now:           t0,
+			wantAllowed:   true,
+			wantRemaining: 3,
+			wantReset:     500 * time.Millisecond,
+		},
+		{
+			name:        "empty bucket",
+			bucket:      bucket{Tokens: 0, UpdatedAt: t0},
+			now:         t0,
+			wantRetry:   500 * time.Millisecond,
+			wantReset:   2 * time.Second,
+			wantAllowed: false,
+		},
+		{
+			name:          "refills with elapsed time",
+			bucket:        bucket{Tokens: 0, UpdatedAt: t0},
+			now:           t0.Add(time.Second),
+			wantAllowed:   true,
+			wantRemaining: 1,
+			wantReset:     1500 * time.Millisecond,
+		},
+		{
+			name:          "refill capped at burst",
+			bucket:        bucket{Tokens: 1, UpdatedAt: t0},
+			now:           t0.Add(time.Hour),
+			wantAllowed:   true,
+			wantRemaining: 3,
+			wantReset:     500 * time.Millisecond,
+		},
+		{
+			name:        "clock going backwards does not refill",
+			bucket:      bucket{Tokens: 0.5, UpdatedAt: t0},
+			now:         t0.Add(-time.Second),
+			wantRetry:   250 * time.Millisecond,
+			wantReset:   1750 * time.Millisecond,
+			wantAllowed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, res := take(tt.bucket, limit, tt.now)
+			if res.Allowed != tt.wantAllowed {
+				t.Fatalf("Allowed = %v, want %v", res.Allowed, tt.wantAllowed)
+			}
+			if res.Remaining != tt.wantRemaining {
+				t.Errorf("Remaining = %d, want %d", res.Remaining, tt.wantRemaining)
+			}
+			if res.RetryAfter != tt.wantRetry {
+				t.Errorf("RetryAfter = %v, want %v", res.RetryAfter, tt.wantRetry)
+			}
+			if res.Reset != tt.wantReset {
+				t.Errorf("Reset = %v, want %v", res.Reset, tt.wantReset)
+			}
+			if res.Limit != limit.Burst {
+				t.Errorf("Limit = %d, want %d", res.Limit, limit.Burst)
+			}
+			if !next.UpdatedAt.Equal(tt.now) {
+				t.Errorf("UpdatedAt = %v, want %v", next.UpdatedAt, tt.now)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreExhaustsBurst(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limit := Limit{Rate: 1, Burst: 3}
+
+	for i := 0; i < limit.Burst; i++ {
+		res, err := store.Take(context.Background(), "client", limit, now)
+		if err != nil || !res.Allowed {
+			t.Fatalf("take %d: allowed = %v, err = %v", i, res.Allowed, err)
+		}
+	}
+	res, _ := store.Take(context.Background(), "client", limit, now)
+	if res.Allowed {
+		t.Fatal("take past burst allowed")
+	}
+	res, _ = store.Take(context.Background(), "other", limit, now)
+	if !res.Allowed {
+		t.Fatal("buckets are not per key")
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{in: "5:10", want: Limit{Rate: 5, Burst: 10}},
+		{in: " 0.5:1 ", want: Limit{Rate: 0.5, Burst: 1}},
+		{in: "5", wantErr: true},
+		{in: "0:10", wantErr: true},
+		{in: "-1:10", wantErr: true},
+		{in: "x:10", wantErr: true},
+		{in: "5:0", wantErr: true},
+		{in: "5:1.5", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLimit(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRouteLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    map[string]Limit
+		wantErr bool
+	}{
+		{name: "empty", in: "", want: map[string]Limit{}},
+		{
+			name: "several routes",
+			in:   "GET /api/events=2:5, POST  /api/events=1:3,",
+			want: map[string]Limit{
+				"GET /api/events":  {Rate: 2, Burst: 5},
+				"POST /api/events": {Rate: 1, Burst: 3},
+			},
+		},
+		{name: "missing limit", in: "GET /api/events", wantErr: true},
+		{name: "bad limit", in: "GET /api/events=fast", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRouteLimits(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for route, limit := range tt.want {
+				if got[route] != limit {
+					t.Errorf("%s: got %+v, want %+v", route, got[route], limit)
+				}
+			}
+		})
+	}
+}
+
+func TestPolicyFor(t *testing.T) {
+	p := Policy{
+		Anonymous: Limit{Rate: 1, Burst: 1},
+		APIKey:    Limit{Rate: 10, Burst: 10},
+		Routes:    map[string]Limit{"POST /api/events": {Rate: 2, Burst: 2}},
+	}
+	tests := []struct {
+		route  string
+		apiKey bool
+		want   Limit
+	}{
+		{route: "GET /api/events", want: p.Anonymous},
+		{route: "GET /api/events", apiKey: true, want: p.APIKey},
+		{route: "POST /api/events", apiKey: true, want: p.Routes["POST /api/events"]},
+	}
+	for _, tt := range tests {
+		if got := p.For(tt.route, tt.apiKey); got != tt.want {
+			t.Errorf("For(%q, %v) = %+v, want %+v", tt.route, tt.apiKey, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+// Package search keeps an in-memory inverted index of events for full-text
+// search. Matching is accent-insensitive, the last query term also matches as
+// a prefix, and longer terms tolerate typos; results are ranked with BM25.
+package search
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+)
+
+const (
+	// nameBoost weighs matches in the name over those in the description and
+	// location.
+	nameBoost = 3.0
+	// prefixWeight and fuzzyWeight scale matches that are not exact.
+	prefixWeight = 0.7
+	fuzzyWeight  = 0.5
+	// maxExpansions caps how many indexed terms a prefix or fuzzy query term
+	// expands to.
+	maxExpansions = 50
+
+	// BM25 parameters.
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Hit is an event matching a query, with its relevance score.
+type Hit struct {
+	ID    string
+	Score float64
+}
+
+// Index is an inverted index of the events of every tenant. It is safe for
+// concurrent use.
+type Index struct {
+	mu      sync.RWMutex
+	tenants map[string]*corpus
+	// journal records the latest version of each event put (or nil if
+	// removed) while a rebuild loads events.
+	journal    map[change]*model.Event
+	rebuilding sync.Mutex
+}
+
+// change identifies an event in the journal.
+type change struct {
+	tenantID string
+	eventID  string
+}
+
+func NewIndex() *Index {
+	return &Index{tenants: make(map[string]*corpus)}
+}
+
+// corpus is the index of one tenant.
+type corpus struct {
+	docs map[string]*document
+	// postings maps each term to the documents containing it.
+	postings map[string]map[string]posting
+	// vocabulary is the sorted list of terms, for prefix lookups.
+	vocabulary []string
+	nameLen    int
+	bodyLen    int
+	// bulk skips keeping the vocabulary sorted while the corpus is built.
+	bulk bool
+}
+
+// document is an indexed event, recording its terms so it can be removed.
+type document struct {
+	terms   []string
+	nameLen int
+	bodyLen int
+}
+
+// posting holds the frequency of a term in each field of a document.
+type posting struct {
+	name int
+	body int
+}
+
+func newCorpus() *corpus {
+	return &corpus{
+		docs:     make(map[string]*document),
+		postings: make(map[string]map[string]posting),
+	}
+}
+
+// Put indexes event, replacing any previous version of it. Its name,
+// description and location are indexed along with their translations.
+func (ix *Index) Put(event model.Event) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	c := ix.tenants[event.TenantID]
+	if c == nil {
+		c = newCorpus()
+		ix.tenants[event.TenantID] = c
+	}
+	c.put(event)
+	if ix.journal != nil {
+		ix.journal[change{event.TenantID, event.ID.String()}] = &event
+	}
+}
+
+// Remove drops the event from the index of the tenant.
+func (ix *Index) Remove(tenantID, eventID string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if c := ix.tenants[tenantID]; c != nil {
+		c.remove(eventID)
+	}
+	if ix.journal != nil {
+		ix.journal[change{tenantID, eventID}] = nil
+	}
+}
+
+// Rebuild replaces the whole index with the events returned by load. Events
+// put or removed while load runs are applied on top, so writes racing with
+// the rebuild are not lost.
+func (ix *Index) Rebuild(load func() ([]model.Event, error)) error {
+	ix.rebuilding.Lock()
+	defer ix.rebuilding.Unlock()
+
+	ix.mu.Lock()
+	ix.journal = make(map[change]*model.Event)
+	ix.mu.Unlock()
+
+	events, err := load()
+	if err != nil {
+		ix.mu.Lock()
+		ix.journal = nil
+		ix.mu.Unlock()
+		return err
+	}
+
+	tenants := make(map[string]*corpus)
+	add := func(event model.Event) {
+		c := tenants[event.TenantID]
+		if c == nil {
+			// The vocabulary is sorted once at the end
+			c = newCorpus()
+			c.bulk = true
+			tenants[event.TenantID] = c
+		}
+		c.put(event)
+	}
+	for _, event := range events {
+		add(event)
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for key, event := range ix.journal {
+		if event != nil {
+			add(*event)
+		} else if c := tenants[key.tenantID]; c != nil {
+			c.remove(key.eventID)
+		}
+	}
+	for _, c := range tenants {
+		c.vocabulary = slices.Sorted(maps.Keys(c.postings))
+		c.bulk = false
+	}
+	ix.tenants = tenants
+	ix.journal = nil
+	return nil
+}
+
+// Len returns how many events of the tenant are indexed.
+func (ix *Index) Len(tenantID string) int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	if c := ix.tenants[tenantID]; c != nil {
+		return len(c.docs)
+	}
+	return 0
+}
+
+// Search returns up to limit events of the tenant matching query, most
+// relevant first. Events matching more of the query terms rank higher.
+func (ix *Index) Search(tenantID, query string, limit int) []Hit {
+	terms := queryTerms(query)
+	if len(terms) == 0 || limit <= 0 {
+		return nil
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	c := ix.tenants[tenantID]
+	if c == nil {
+		return nil
+	}
+
+	scores := make(map[string]float64)
+	matched := make(map[string]int)
+	for i, term := range terms {
+		best := c.score(c.expand(term, i == len(terms)-1))
+		for id, score := range best {
+			scores[id] += score
+			matched[id]++
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		coverage := float64(matched[id]) / float64(len(terms))
+		hits = append(hits, Hit{ID: id, Score: score * coverage * coverage})
+	}
+	slices.SortFunc(hits, func(x, y Hit) int {
+		if x.Score != y.Score {
+			if x.Score > y.Score {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(x.ID, y.ID)
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// queryTerms returns the terms of query to look up. Stopwords are skipped
+// except for the last term, which may be the start of a longer word.
+func queryTerms(query string) []string {
+	all := Terms(query)
+	var terms []string
+	for i, term := range all {
+		if stopwords[term] && i < len(all)-1 {
+			continue
+		}
+		if !slices.Contains(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+func (c *corpus) put(event model.Event) {
+	id := event.ID.String()
+	c.remove(id)
+
+	names := []string{event.Name}
+	bodies := []string{event.Description, event.Location}
+	for _, t := range event.Translations {
+		names = append(names, t.Name)
+		bodies = append(bodies, t.Description)
+	}
+
+	freqs := make(map[string]posting)
+	doc := &document{}
+	for _, text := range names {
+		for _, term := range indexTerms(text) {
+			p := freqs[term]
+			p.name++
+			freqs[term] = p
+			doc.nameLen++
+		}
+	}
+	for _, text := range bodies {
+		for _, term := range indexTerms(text) {
+			p := freqs[term]
+			p.body++
+			freqs[term] = p
+			doc.bodyLen++
+		}
+	}
+
+	for term, p := range freqs {
+		docs := c.postings[term]
+		if docs == nil {
+			docs = make(map[string]posting)
+			c.postings[term] = docs
+			if !c.bulk {
+				i, _ := slices.BinarySearch(c.vocabulary, term)
+				c.vocabulary = slices.Insert(c.vocabulary, i, term)
+			}
+		}
+		docs[id] = p
+		doc.terms = append(doc.terms, term)
+	}
+	c.docs[id] = doc
+	c.nameLen += doc.nameLen
+	c.bodyLen += doc.bodyLen
+}
+
+func (c *corpus) remove(id string) {
+	doc := c.docs[id]
+	if doc == nil {
+		return
+	}
+	for _, term := range doc.terms {
+		delete(c.postings[term], id)
+		if len(c.postings[term]) == 0 {
+			delete(c.postings, term)
+			if i, ok := slices.BinarySearch(c.vocabulary, term); ok {
+				c.vocabulary = slices.Delete(c.vocabulary, i, i+1)
+			}
+		}
+	}
+	delete(c.docs, id)
+	c.nameLen -= doc.nameLen
+	c.bodyLen -= doc.bodyLen
+}
+
+// expand returns the indexed terms matching a query term with the weight of
+// the match: the term itself, the terms it is a prefix of when prefix is
+// set, and the terms within a few typos of it.
+func (c *corpus) expand(term string, prefix bool) map[string]float64 {
+	weights := make(map[string]float64)
+	if _, ok := c.postings[term]; ok {
+		weights[term] = 1
+	}
+
+	if prefix {
+		start, _ := slices.BinarySearch(c.vocabulary, term)
+		for _, candidate := range c.vocabulary[start:] {
+			if !strings.HasPrefix(candidate, term) || len(weights) >= maxExpansions {
+				break
+			}
+			if candidate != term {
+				weights[candidate] = prefixWeight
+			}
+		}
+	}
+
+	query := []rune(term)
+	limit := maxEdits(len(query))
+	if limit == 0 {
+		return weights
+	}
+	for _, candidate := range c.vocabulary {
+		if len(weights) >= maxExpansions {
+			break
+		}
+		if _, ok := weights[candidate]; ok {
+			continue
+		}
+		if withinEdits(query, []rune(candidate), limit) {
+			weights[candidate] = fuzzyWeight
+		}
+	}
+	return weights
+}
+
+// score returns the BM25 score of each document for one query term, taking
+// the best of its expansions so a short prefix matching many words does not
+// outweigh an exact match.
+func (c *corpus) score(weights map[string]float64) map[string]float64 {
+	best := make(map[string]float64)
+	n := float64(len(c.docs))
+	avgName := math.Max(float64(c.nameLen)/n, 1)
+	avgBody := math.Max(float64(c.bodyLen)/n, 1)
+
+	for term, weight := range weights {
+		docs := c.postings[term]
+		df := float64(len(docs))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, p := range docs {
+			doc := c.docs[id]
+			tf := nameBoost*saturate(p.name, doc.nameLen, avgName) + saturate(p.body, doc.bodyLen, avgBody)
+			if score := weight * idf * tf; score > best[id] {
+				best[id] = score
+			}
+		}
+	}
+	return best
+}
+
+// saturate is the BM25 term frequency component for one field.
+func saturate(freq, length int, avgLength float64) float64 {
+	if freq == 0 {
+		return 0
+	}
+	tf := float64(freq)
+	return tf * (k1 + 1) / (tf + k1*(1-b+b*float64(length)/avgLength))
+}
@@ -0,0 +1,199 @@
+package search
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+)
+
+func TestWithinEdits(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  bool
+	}{
+		{a: "concierto", b: "concierto", limit: 0, want: true},
+		{a: "concierto", b: "conciertos", limit: 1, want: true},
+		{a: "concierto", b: "concerto", limit: 1, want: true},
+		{a: "concierto", b: "concireto", limit: 1, want: true},
+		{a: "concierto", b: "conzierto", limit: 1, want: true},
+		{a: "concierto", b: "cncierot", limit: 1, want: false},
+		{a: "concierto", b: "cncierot", limit: 2, want: true},
+		{a: "jazz", b: "jazzfest", limit: 2, want: false},
+		{a: "música", b: "musica", limit: 1, want: true},
+		{a: "", b: "ab", limit: 2, want: true},
+		{a: "teatro", b: "cinema", limit: 2, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := withinEdits([]rune(tt.a), []rune(tt.b), tt.limit); got != tt.want {
+				t.Fatalf("withinEdits(%q, %q, %d) = %v, want %v", tt.a, tt.b, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerms(t *testing.T) {
+	got := Terms("¡Música en VIVO! Jazz & Blues, 2026")
+	want := []string{"musica", "en", "vivo", "jazz", "blues", "2026"}
+	if len(got) != len(want) {
+		t.Fatalf("Terms = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Terms = %v, want %v", got, want)
+		}
+	}
+}
+
+var (
+	jazzID    = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	rockID    = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	theatreID = uuid.MustParse("00000000-0000-0000-0000-000000000003")
+)
+
+func testIndex() *Index {
+	ix := NewIndex()
+	ix.Put(model.Event{
+		ID: jazzID, TenantID: "acme",
+		Name:        "Festival de Jazz",
+		Description: "Tres noches de jazz en directo",
+		Location:    "Madrid",
+		Translations: model.Translations{
+			"en": {Name: "Jazz Festival", Description: "Three nights of live jazz"},
+		},
+	})
+	ix.Put(model.Event{
+		ID: rockID, TenantID: "acme",
+		Name:        "Concierto de rock",
+		Description: "Música en directo con bandas locales",
+		Location:    "Barcelona",
+	})
+	ix.Put(model.Event{
+		ID: theatreID, TenantID: "acme",
+		Name:        "Teatro clásico",
+		Description: "Obra de teatro con música de jazz",
+		Location:    "Madrid",
+	})
+	ix.Put(model.Event{
+		ID: uuid.New(), TenantID: "other",
+		Name: "Jazz en el parque",
+	})
+	return ix
+}
+
+func TestIndexSearch(t *testing.T) {
+	ix := testIndex()
+
+	tests := []struct {
+		name  string
+		query string
+		want  []uuid.UUID
+	}{
+		{name: "exact term ranks name matches first", query: "jazz", want: []uuid.UUID{jazzID, theatreID}},
+		{name: "accent insensitive", query: "MUSICA", want: []uuid.UUID{rockID, theatreID}},
+		{name: "prefix on last term", query: "conci", want: []uuid.UUID{rockID}},
+		{name: "typo", query: "conciert rok", want: []uuid.UUID{rockID}},
+		{name: "translation", query: "festival nights", want: []uuid.UUID{jazzID}},
+		{name: "more terms matched ranks higher", query: "madrid jazz", want: []uuid.UUID{jazzID, theatreID}},
+		{name: "stopwords only", query: "de la", want: nil},
+		{name: "no match", query: "ballet", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := ix.Search("acme", tt.query, 10)
+			if len(hits) != len(tt.want) {
+				t.Fatalf("Search(%q) = %v, want %v", tt.query, hits, tt.want)
+			}
+			for i, id := range tt.want {
+				if hits[i].ID != id.String() {
+					t.Fatalf("Search(%q) = %v, want %v", tt.query, hits, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestIndexSearchLimit(t *testing.T) {
+	ix := testIndex()
+	if hits := ix.Search("acme", "jazz", 1); len(hits) != 1 || hits[0].ID != jazzID.String() {
+		t.Fatalf("Search with limit 1 = %v", hits)
+	}
+	if hits := ix.Search("acme", "jazz", 0); hits != nil {
+		t.Fatalf("Search with limit 0 = %v", hits)
+	}
+}
+
+func TestIndexTenantIsolation(t *testing.T) {
+	ix := testIndex()
+	if got := ix.Len("other"); got != 1 {
+		t.Fatalf("Len(other) = %d, want 1", got)
+	}
+	for _, hit := range ix.Search("other", "jazz", 10) {
+		if hit.ID == jazzID.String() || hit.ID == theatreID.String() {
+			t.Fatalf("tenant other sees event %s of acme", hit.ID)
+		}
+	}
+	if hits := ix.Search("nobody", "jazz", 10); hits != nil {
+		t.Fatalf("unknown tenant got %v", hits)
+	}
+}
+
+func TestIndexPutReplacesAndRemove(t *testing.T) {
+	ix := testIndex()
+	ix.Put(model.Event{ID: rockID, TenantID: "acme", Name: "Concierto de pop"})
+
+	if hits := ix.Search("acme", "rock", 10); len(hits) != 0 {
+		t.Fatalf("replaced terms still match: %v", hits)
+	}
+	if hits := ix.Search("acme", "pop", 10); len(hits) != 1 || hits[0].ID != rockID.String() {
+		t.Fatalf("new terms do not match: %v", hits)
+	}
+
+	ix.Remove("acme", rockID.String())
+	if hits := ix.Search("acme", "pop", 10); len(hits) != 0 {
+		t.Fatalf("removed event still matches: %v", hits)
+	}
+	if got := ix.Len("acme"); got != 2 {
+		t.Fatalf("Len(acme) = %d, want 2", got)
+	}
+}
+
+func TestIndexRebuild(t *testing.T) {
+	ix := testIndex()
+
+	err := ix.Rebuild(func() ([]model.Event, error) {
+		// Writes racing with the load are applied on top of it
+		ix.Put(model.Event{ID: theatreID, TenantID: "acme", Name: "Ópera"})
+		ix.Remove("acme", rockID.String())
+		return []model.Event{
+			{ID: jazzID, TenantID: "acme", Name: "Festival de Jazz"},
+			{ID: rockID, TenantID: "acme", Name: "Concierto de rock"},
+		}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := ix.Len("acme"); got != 2 {
+		t.Fatalf("Len(acme) = %d, want 2", got)
+	}
+	if got := ix.Len("other"); got != 0 {
+		t.Fatalf("Len(other) = %d, want 0", got)
+	}
+	if hits := ix.Search("acme", "opera", 10); len(hits) != 1 || hits[0].ID != theatreID.String() {
+		t.Fatalf("put during rebuild lost: %v", hits)
+	}
+	if hits := ix.Search("acme", "rock", 10); len(hits) != 0 {
+		t.Fatalf("remove during rebuild lost: %v", hits)
+	}
+
+	// A failed load keeps the current index
+	if err := ix.Rebuild(func() ([]model.Event, error) { return nil, errors.New("boom") }); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := ix.Len("acme"); got != 2 {
+		t.Fatalf("Len(acme) after failed rebuild = %d, want 2", got)
+	}
+}
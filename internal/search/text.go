@@ -0,0 +1,101 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// stopwords are frequent Spanish and English words that are not indexed, so
+// they neither match nearly every event nor weigh on the ranking.
+var stopwords = map[string]bool{
+	"a": true, "al": true, "con": true, "de": true, "del": true, "el": true,
+	"en": true, "es": true, "la": true, "las": true, "lo": true, "los": true,
+	"o": true, "para": true, "por": true, "se": true, "su": true, "un": true,
+	"una": true, "y": true,
+	"an": true, "and": true, "at": true, "for": true, "in": true, "of": true,
+	"on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+// Normalize folds s for matching: lowercased, with accents and other
+// combining marks removed, so "Música" and "musica" are the same.
+func Normalize(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+	return strings.ToLower(folded)
+}
+
+// Terms splits s into normalized words, in order and with repetitions.
+func Terms(s string) []string {
+	return strings.FieldsFunc(Normalize(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// indexTerms returns the terms of s worth indexing, without stopwords.
+func indexTerms(s string) []string {
+	terms := Terms(s)
+	kept := terms[:0]
+	for _, term := range terms {
+		if !stopwords[term] {
+			kept = append(kept, term)
+		}
+	}
+	return kept
+}
+
+// maxEdits is how many typos a query term of the given length tolerates:
+// none for short terms, where a single edit changes the word entirely.
+func maxEdits(length int) int {
+	switch {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// withinEdits reports whether a and b are at most limit edits apart, an
+// edit being an insertion, deletion, substitution or swap of two adjacent
+// characters.
+func withinEdits(a, b []rune, limit int) bool {
+	if diff := len(a) - len(b); diff > limit || -diff > limit {
+		return false
+	}
+	// Three rows of the edit distance matrix: swaps look two rows back
+	prevPrev := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		best := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prevPrev[j-2]+1)
+			}
+			best = min(best, curr[j])
+		}
+		// Later rows never drop below the minimum of this one
+		if best > limit {
+			return false
+		}
+		prevPrev, prev, curr = prev, curr, prevPrev
+	}
+	return prev[len(b)] <= limit
+}
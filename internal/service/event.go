@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/jhonathanssegura/ticket-events/internal/db"
 	"github.com/jhonathanssegura/ticket-events/internal/model"
@@ -15,22 +17,26 @@ func NewEventService(dynamoDB *db.DynamoClient) *EventService {
 }
 
 // CreateEvent creates a new event
-func (s *EventService) CreateEvent(req model.CreateEventRequest) (*model.Event, error) {
+func (s *EventService) CreateEvent(ctx context.Context, req model.CreateEventRequest) (*model.Event, error) {
 	eventID := uuid.New()
+	var price float64
+	if req.Price != nil {
+		price = *req.Price
+	}
 	event := &model.Event{
 		ID:          eventID,
 		Name:        req.Name,
 		Description: req.Description,
 		CategoryID:  req.CategoryID,
 		Location:    req.Location,
-		Date:        req.Date,
 		Capacity:    req.Capacity,
-		Price:       req.Price,
+		Price:       price,
 		Status:      model.EventStatusDraft,
 		ImageURL:    req.ImageURL,
 	}
+	event.Schedule(req.StartsAt, req.EndsAt, req.TimeZone)
 
-	err := s.dynamoDB.SaveEvent(*event)
+	err := s.dynamoDB.SaveEvent(ctx, *event)
 	if err != nil {
 		return nil, err
 	}
@@ -39,19 +45,19 @@ func (s *EventService) CreateEvent(req model.CreateEventRequest) (*model.Event,
 }
 
 // GetEvent retrieves an event by ID
-func (s *EventService) GetEvent(id string) (*model.Event, error) {
-	return s.dynamoDB.GetEventByID(id)
+func (s *EventService) GetEvent(ctx context.Context, id string) (*model.Event, error) {
+	return s.dynamoDB.GetEventByID(ctx, id)
 }
 
 // ListEvents retrieves all events with optional filtering
-func (s *EventService) ListEvents(categoryID string, limit int) ([]model.Event, error) {
-	return s.dynamoDB.GetEvents(categoryID, limit)
+func (s *EventService) ListEvents(ctx context.Context, categoryID string, limit int) ([]model.Event, error) {
+	return s.dynamoDB.GetEvents(ctx, categoryID, limit)
 }
 
 // UpdateEvent updates an existing event
-func (s *EventService) UpdateEvent(id string, updatedEvent *model.Event) (*model.Event, error) {
+func (s *EventService) UpdateEvent(ctx context.Context, id string, updatedEvent *model.Event) (*model.Event, error) {
 	// Get existing event
-	existingEvent, err := s.dynamoDB.GetEventByID(id)
+	existingEvent, err := s.dynamoDB.GetEventByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -69,8 +75,8 @@ func (s *EventService) UpdateEvent(id string, updatedEvent *model.Event) (*model
 	if updatedEvent.Location != "" {
 		existingEvent.Location = updatedEvent.Location
 	}
-	if !updatedEvent.Date.IsZero() {
-		existingEvent.Date = updatedEvent.Date
+	if !updatedEvent.StartsAt.IsZero() && updatedEvent.EndsAt.After(updatedEvent.StartsAt) {
+		existingEvent.Schedule(updatedEvent.StartsAt, updatedEvent.EndsAt, updatedEvent.TimeZone)
 	}
 	if updatedEvent.Capacity > 0 {
 		existingEvent.Capacity = updatedEvent.Capacity
@@ -82,10 +88,10 @@ func (s *EventService) UpdateEvent(id string, updatedEvent *model.Event) (*model
 		existingEvent.Status = updatedEvent.Status
 	}
 	if updatedEvent.ImageURL != "" {
-		existingEvent.ImageURL = updatedEvent.ImageURL
+		existingEvent.SetImageURL(updatedEvent.ImageURL)
 	}
 
-	err = s.dynamoDB.SaveEvent(*existingEvent)
+	err = s.dynamoDB.SaveEvent(ctx, *existingEvent)
 	if err != nil {
 		return nil, err
 	}
@@ -94,13 +100,13 @@ func (s *EventService) UpdateEvent(id string, updatedEvent *model.Event) (*model
 }
 
 // DeleteEvent deletes an event
-func (s *EventService) DeleteEvent(id string) error {
-	return s.dynamoDB.DeleteEvent(id)
+func (s *EventService) DeleteEvent(ctx context.Context, id string) error {
+	return s.dynamoDB.DeleteEvent(ctx, id)
 }
 
 // GetEventWithStats retrieves an event with basic statistics
-func (s *EventService) GetEventWithStats(id string) (*model.Event, error) {
-	event, err := s.GetEvent(id)
+func (s *EventService) GetEventWithStats(ctx context.Context, id string) (*model.Event, error) {
+	event, err := s.GetEvent(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/pricing"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+// EvaluatePricing prices event under rules at now. Sales are only read if
+// some rule depends on them.
+func EvaluatePricing(ctx context.Context, store *db.DynamoClient, event *model.Event, rules *model.PricingRules, now time.Time) (pricing.Adjustment, error) {
+	sold := 0
+	if len(rules.SellThroughSteps) > 0 {
+		var err error
+		if sold, err = store.GetSoldCount(ctx, event.ID.String()); err != nil {
+			return pricing.Adjustment{}, err
+		}
+	}
+	return pricing.Evaluate(event, rules, sold, now), nil
+}
+
+// NewPriceChange describes the move of the effective price of event from
+// previous to adjustment.
+func NewPriceChange(event *model.Event, adjustment pricing.Adjustment, previous *float64, reason string, now time.Time) *model.PriceChange {
+	return &model.PriceChange{
+		EventID:       event.ID,
+		Price:         adjustment.Price,
+		PreviousPrice: previous,
+		BasePrice:     adjustment.BasePrice,
+		Percent:       adjustment.Percent,
+		SoldPercent:   adjustment.SoldPercent,
+		Reason:        reason,
+		ChangedAt:     now,
+	}
+}
+
+// RecordPriceChange records the effective price of event at now in its
+// price history if it changed since last recorded. Events without pricing
+// rules have no history and are skipped.
+func RecordPriceChange(ctx context.Context, store *db.DynamoClient, event *model.Event, now time.Time) error {
+	rules, err := store.GetPricingRules(ctx, event.ID.String())
+	if errors.Is(err, db.ErrPricingRulesNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return recordPriceChange(ctx, store, event, rules, now)
+}
+
+func recordPriceChange(ctx context.Context, store *db.DynamoClient, event *model.Event, rules *model.PricingRules, now time.Time) error {
+	adjustment, err := EvaluatePricing(ctx, store, event, rules, now)
+	if err != nil {
+		return err
+	}
+	if rules.CurrentPrice != nil && *rules.CurrentPrice == adjustment.Price {
+		return nil
+	}
+
+	change := NewPriceChange(event, adjustment, rules.CurrentPrice, model.PriceChangeDynamic, now)
+	// Concurrent sales and the background job race to record the change;
+	// one of them wins
+	if err := store.RecordPriceChange(ctx, *change); err != nil && !errors.Is(err, db.ErrPriceChangeConflict) {
+		return err
+	}
+	return nil
+}
+
+// RecordPriceChanges records the price changes of every event with pricing
+// rules, in every tenant. Sales record theirs as they happen, but time steps
+// move the price with no request involved. Events already over are skipped,
+// and a failing event is logged and skipped.
+func RecordPriceChanges(ctx context.Context, store *db.DynamoClient) error {
+	all, err := store.ScanPricingRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range all {
+		rules := &all[i]
+		tenantCtx := tenant.WithTenant(ctx, rules.TenantID)
+		event, err := store.GetEventByID(tenantCtx, rules.EventID.String())
+		if err != nil {
+			if !strings.Contains(err.Error(), "not found") {
+				slog.WarnContext(ctx, "error obteniendo evento", "event_id", rules.EventID.String(), "error", err)
+			}
+			continue
+		}
+		if event.EndsAt.Before(now) {
+			continue
+		}
+		if err := recordPriceChange(tenantCtx, store, event, rules, now); err != nil {
+			slog.WarnContext(ctx, "error registrando cambio de precio", "event_id", rules.EventID.String(), "error", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+	"github.com/teambition/rrule-go"
+)
+
+// maxOccurrences caps how many occurrences one materialization pass creates
+// for a series; the next pass continues where it stopped.
+const maxOccurrences = 1000
+
+// ErrNotOccurrence is returned by SplitSeries when the given start is not an
+// occurrence of the series.
+var ErrNotOccurrence = errors.New("not an occurrence of the series")
+
+// SeriesService materializes the occurrences of recurring series as events,
+// up to a rolling horizon.
+type SeriesService struct {
+	dynamoDB *db.DynamoClient
+	horizon  time.Duration
+}
+
+func NewSeriesService(dynamoDB *db.DynamoClient, horizon time.Duration) *SeriesService {
+	return &SeriesService{dynamoDB: dynamoDB, horizon: horizon}
+}
+
+// Occurrences returns the starts of the occurrences of series in [from,
+// until), skipping exception dates, at most limit of them.
+func Occurrences(series *model.EventSeries, from, until time.Time, limit int) ([]time.Time, error) {
+	rule, err := recurrence(series)
+	if err != nil {
+		return nil, err
+	}
+
+	var starts []time.Time
+	next := rule.Iterator()
+	for len(starts) < limit {
+		start, ok := next()
+		if !ok || !start.Before(until) {
+			break
+		}
+		start = start.UTC()
+		if start.Before(from) || series.Excluded(start) {
+			continue
+		}
+		starts = append(starts, start)
+	}
+	return starts, nil
+}
+
+// recurrence builds the rule of series, anchored at its first occurrence in
+// the venue time zone so local times survive DST changes.
+func recurrence(series *model.EventSeries) (*rrule.RRule, error) {
+	option, err := rrule.StrToROption(series.RRule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rrule: %w", err)
+	}
+	option.Dtstart = series.StartsAt.In(series.Zone())
+	return rrule.NewRRule(*option)
+}
+
+// SplitSeries ends series right before the occurrence starting at at and
+// returns a new series that continues from there with the same rule, so an
+// edit to "this and following occurrences" leaves earlier ones untouched.
+// If at is the first occurrence there is nothing to split and tail is nil.
+func SplitSeries(series *model.EventSeries, at, now time.Time) (head model.EventSeries, tail *model.EventSeries, err error) {
+	head = *series
+	at = at.UTC()
+	if at.Equal(series.StartsAt) {
+		return head, nil, nil
+	}
+
+	option, err := rrule.StrToROption(series.RRule)
+	if err != nil {
+		return head, nil, fmt.Errorf("invalid rrule: %w", err)
+	}
+	rule, err := recurrence(series)
+	if err != nil {
+		return head, nil, err
+	}
+
+	// COUNT includes excluded dates, so count every instance before at.
+	before, found := 0, false
+	next := rule.Iterator()
+	for {
+		start, ok := next()
+		if !ok || start.After(at) {
+			break
+		}
+		if start.Equal(at) {
+			found = true
+			break
+		}
+		before++
+	}
+	if !found {
+		return head, nil, ErrNotOccurrence
+	}
+
+	headOption, tailOption := *option, *option
+	headOption.Count = 0
+	headOption.Until = at.Add(-time.Second)
+	if option.Count > 0 {
+		tailOption.Count = option.Count - before
+	}
+
+	head.RRule = headOption.RRuleString()
+	head.ExDates = nil
+	head.UpdatedAt = now
+
+	rest := *series
+	tail = &rest
+	tail.ID = uuid.New()
+	tail.StartsAt = at
+	tail.EndsAt = at.Add(series.Duration())
+	tail.RRule = tailOption.RRuleString()
+	tail.ExDates = nil
+	tail.MaterializedUntil = time.Time{}
+	tail.CreatedAt = now
+	tail.UpdatedAt = now
+
+	for _, exdate := range series.ExDates {
+		if exdate.Before(at) {
+			head.ExDates = append(head.ExDates, exdate)
+		} else {
+			tail.ExDates = append(tail.ExDates, exdate)
+		}
+	}
+	return head, tail, nil
+}
+
+// Materialize creates the occurrences of series from where the previous pass
+// stopped (or now) up to the horizon, and records how far it got.
+func (s *SeriesService) Materialize(ctx context.Context, series *model.EventSeries, now time.Time) (int, error) {
+	return s.materialize(ctx, series, now, nil)
+}
+
+// Rematerialize regenerates the upcoming occurrences after the schedule of
+// series changed. Occurrences edited individually are kept and their slots
+// are not generated again.
+func (s *SeriesService) Rematerialize(ctx context.Context, series *model.EventSeries, now time.Time) (int, error) {
+	events, err := s.dynamoDB.GetEventsBySeries(ctx, series.ID.String(), now)
+	if err != nil {
+		return 0, err
+	}
+
+	keep := make(map[time.Time]bool)
+	for _, event := range events {
+		if event.Detached && event.RecurrenceID != nil {
+			keep[*event.RecurrenceID] = true
+			continue
+		}
+		if err := s.dynamoDB.DeleteEvent(ctx, event.ID.String()); err != nil {
+			return 0, err
+		}
+	}
+
+	series.MaterializedUntil = time.Time{}
+	return s.materialize(ctx, series, now, keep)
+}
+
+func (s *SeriesService) materialize(ctx context.Context, series *model.EventSeries, now time.Time, keep map[time.Time]bool) (int, error) {
+	from := series.MaterializedUntil
+	if from.Before(now) {
+		from = now
+	}
+	until := now.Add(s.horizon)
+	if !from.Before(until) {
+		return 0, nil
+	}
+
+	starts, err := Occurrences(series, from, until, maxOccurrences)
+	if err != nil {
+		return 0, err
+	}
+	if len(starts) == maxOccurrences {
+		until = starts[len(starts)-1].Add(time.Second)
+	}
+
+	created := 0
+	for _, start := range starts {
+		if keep[start] {
+			continue
+		}
+		if err := s.dynamoDB.SaveEvent(ctx, series.Occurrence(start, now)); err != nil {
+			return created, err
+		}
+		created++
+	}
+
+	series.MaterializedUntil = until
+	if err := s.dynamoDB.SetSeriesMaterializedUntil(ctx, *series); err != nil {
+		return created, err
+	}
+	return created, nil
+}
+
+// MoveOccurrences hands the occurrences of head starting at or after from
+// over to tail after a split. Occurrences edited individually are relinked
+// to tail and keep their slot; the rest are deleted and tail materializes
+// them again from its own template.
+func (s *SeriesService) MoveOccurrences(ctx context.Context, head, tail *model.EventSeries, from, now time.Time) (int, error) {
+	events, err := s.dynamoDB.GetEventsBySeries(ctx, head.ID.String(), from)
+	if err != nil {
+		return 0, err
+	}
+
+	keep := make(map[time.Time]bool)
+	for _, event := range events {
+		if event.Detached && event.RecurrenceID != nil {
+			event.SeriesID = tail.ID.String()
+			event.UpdatedAt = now
+			if err := s.dynamoDB.SaveEvent(ctx, event); err != nil {
+				return 0, err
+			}
+			keep[*event.RecurrenceID] = true
+			continue
+		}
+		if err := s.dynamoDB.DeleteEvent(ctx, event.ID.String()); err != nil {
+			return 0, err
+		}
+	}
+
+	return s.materialize(ctx, tail, now, keep)
+}
+
+// PropagateTemplate copies the template of series onto its occurrences
+// starting at or after from, except those edited individually.
+func (s *SeriesService) PropagateTemplate(ctx context.Context, series *model.EventSeries, from, now time.Time) error {
+	events, err := s.dynamoDB.GetEventsBySeries(ctx, series.ID.String(), from)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if event.Detached {
+			continue
+		}
+		series.ApplyTemplate(&event)
+		event.UpdatedAt = now
+		if err := s.dynamoDB.SaveEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteOccurrences removes the occurrences of a series starting at or after
+// from. With keepDetached, occurrences edited individually are left alone.
+func (s *SeriesService) DeleteOccurrences(ctx context.Context, seriesID string, from time.Time, keepDetached bool) error {
+	events, err := s.dynamoDB.GetEventsBySeries(ctx, seriesID, from)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if keepDetached && event.Detached {
+			continue
+		}
+		if err := s.dynamoDB.DeleteEvent(ctx, event.ID.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcile brings the upcoming occurrences of a series in line with its
+// stored version after a materialization pass worked on a stale copy: they
+// are regenerated, or removed if the series was deleted.
+func (s *SeriesService) reconcile(ctx context.Context, seriesID string, now time.Time) (int, error) {
+	series, err := s.dynamoDB.GetSeriesByID(ctx, seriesID)
+	if errors.Is(err, db.ErrSeriesNotFound) {
+		return 0, s.DeleteOccurrences(ctx, seriesID, now, false)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return s.Rematerialize(ctx, series, now)
+}
+
+// MaterializeAll extends every series of every tenant up to the horizon. A
+// failing series is logged and skipped.
+func (s *SeriesService) MaterializeAll(ctx context.Context) error {
+	series, err := s.dynamoDB.ScanSeries(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range series {
+		tenantCtx := tenant.WithTenant(ctx, series[i].TenantID)
+		created, err := s.Materialize(tenantCtx, &series[i], now)
+		if errors.Is(err, db.ErrSeriesChanged) || errors.Is(err, db.ErrSeriesNotFound) {
+			// The scanned copy went stale while this pass ran, so what it
+			// created may follow an old template or schedule
+			created, err = s.reconcile(tenantCtx, series[i].ID.String(), now)
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "error materializando serie", "series_id", series[i].ID.String(), "error", err)
+			continue
+		}
+		if created > 0 {
+			slog.InfoContext(ctx, "ocurrencias de serie creadas", "series_id", series[i].ID.String(), "count", created)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/google/uuid"
+	"github.com/jhonathanssegura/ticket-events/internal/model"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t.UTC()
+}
+
+func dates(ss ...string) []time.Time {
+	ts := make([]time.Time, len(ss))
+	for i, s := range ss {
+		ts[i] = date(s)
+	}
+	return ts
+}
+
+func newSeries(startsAt, timeZone, rule string, exdates ...string) *model.EventSeries {
+	s := &model.EventSeries{ID: uuid.New()}
+	start := date(startsAt)
+	s.Schedule(start, start.Add(2*time.Hour), timeZone, rule, dates(exdates...))
+	return s
+}
+
+func TestOccurrences(t *testing.T) {
+	far := date("2030-01-01T00:00:00Z")
+
+	tests := []struct {
+		name        string
+		series      *model.EventSeries
+		from, until time.Time
+		limit       int
+		want        []time.Time
+	}{
+		{
+			name:   "count",
+			series: newSeries("2026-03-02T19:00:00Z", "UTC", "FREQ=WEEKLY;COUNT=3"),
+			until:  far, limit: 10,
+			want: dates("2026-03-02T19:00:00Z", "2026-03-09T19:00:00Z", "2026-03-16T19:00:00Z"),
+		},
+		{
+			name: "local time kept across DST",
+			// 20:00 in Madrid is 19:00Z in winter and 18:00Z in summer
+			series: newSeries("2026-03-28T19:00:00Z", "Europe/Madrid", "FREQ=DAILY;COUNT=3"),
+			until:  far, limit: 10,
+			want: dates("2026-03-28T19:00:00Z", "2026-03-29T18:00:00Z", "2026-03-30T18:00:00Z"),
+		},
+		{
+			name:   "exdates skipped",
+			series: newSeries("2026-03-02T19:00:00Z", "UTC", "FREQ=DAILY;COUNT=4", "2026-03-03T19:00:00Z"),
+			until:  far, limit: 10,
+			want: dates("2026-03-02T19:00:00Z", "2026-03-04T19:00:00Z", "2026-03-05T19:00:00Z"),
+		},
+		{
+			name:   "window",
+			series: newSeries("2026-03-02T19:00:00Z", "UTC", "FREQ=DAILY"),
+			from:   date("2026-03-04T00:00:00Z"), until: date("2026-03-06T19:00:00Z"), limit: 10,
+			want: dates("2026-03-04T19:00:00Z", "2026-03-05T19:00:00Z"),
+		},
+		{
+			name:   "limit",
+			series: newSeries("2026-03-02T19:00:00Z", "UTC", "FREQ=DAILY"),
+			until:  far, limit: 2,
+			want: dates("2026-03-02T19:00:00Z", "2026-03-03T19:00:00Z"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Occurrences(tt.series, tt.from, tt.until, tt.limit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertTimes(t, got, tt.want)
+		})
+	}
+}
+
+func TestOccurrencesInvalidRule(t *testing.T) {
+	series := newSeries("2026-03-02T19:00:00Z", "UTC", "FREQ=SOMETIMES")
+	if _, err := Occurrences(series, time.Time{}, date("2030-01-01T00:00:00Z"), 10); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSplitSeries(t *testing.T) {
+	now := date("2026-03-01T00:00:00Z")
+	far := date("2030-01-01T00:00:00Z")
+
+	tests := []struct {
+		name     string
+		series   *model.EventSeries
+		at       time.Time
+		wantHead []time.Time
+		wantTail []time.Time
+	}{
+		{
+			name:     "count is shared between head and tail",
+			series:   newSeries("2026-03-02T19:00:00Z", "UTC", "FREQ=DAILY;COUNT=5"),
+			at:       date("2026-03-04T19:00:00Z"),
+			wantHead: dates("2026-03-02T19:00:00Z", "2026-03-03T19:00:00Z"),
+			wantTail: dates("2026-03-04T19:00:00Z", "2026-03-05T19:00:00Z", "2026-03-06T19:00:00Z"),
+		},
+		{
+			name:     "excluded dates still count",
+			series:   newSeries("2026-03-02T19:00:00Z", "UTC", "FREQ=DAILY;COUNT=5", "2026-03-03T19:00:00Z", "2026-03-05T19:00:00Z"),
+			at:       date("2026-03-04T19:00:00Z"),
+			wantHead: dates("2026-03-02T19:00:00Z"),
+			wantTail: dates("2026-03-04T19:00:00Z", "2026-03-06T19:00:00Z"),
+		},
+		{
+			name:     "across DST",
+			series:   newSeries("2026-03-27T19:00:00Z", "Europe/Madrid", "FREQ=DAILY;COUNT=4"),
+			at:       date("2026-03-29T18:00:00Z"),
+			wantHead: dates("2026-03-27T19:00:00Z", "2026-03-28T19:00:00Z"),
+			wantTail: dates("2026-03-29T18:00:00Z", "2026-03-30T18:00:00Z"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head, tail, err := SplitSeries(tt.series, tt.at, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tail == nil {
+				t.Fatal("tail is nil")
+			}
+			if head.ID != tt.series.ID || tail.ID == tt.series.ID {
+				t.Fatalf("head keeps the id and tail gets a new one: head %s, tail %s", head.ID, tail.ID)
+			}
+			if !tail.StartsAt.Equal(tt.at) || tail.Duration() != tt.series.Duration() {
+				t.Fatalf("tail starts %v lasting %v", tail.StartsAt, tail.Duration())
+			}
+			if !tail.MaterializedUntil.IsZero() {
+				t.Fatalf("tail MaterializedUntil = %v, want zero", tail.MaterializedUntil)
+			}
+
+			got, err := Occurrences(&head, time.Time{}, far, 100)
+			if err != nil {
+				t.Fatalf("head occurrences: %v", err)
+			}
+			assertTimes(t, got, tt.wantHead)
+			got, err = Occurrences(tail, time.Time{}, far, 100)
+			if err != nil {
+				t.Fatalf("tail occurrences: %v", err)
+			}
+			assertTimes(t, got, tt.wantTail)
+		})
+	}
+}
+
+func TestSplitSeriesFirstOccurrence(t *testing.T) {
+	series := newSeries("2026-03-02T19:00:00Z", "UTC", "FREQ=DAILY;COUNT=5")
+	head, tail, err := SplitSeries(series, series.StartsAt, date("2026-03-01T00:00:00Z"))
+	if err != nil || tail != nil {
+		t.Fatalf("tail = %v, err = %v, want nil, nil", tail, err)
+	}
+	if head.RRule != series.RRule {
+		t.Fatalf("head rule = %q, want %q", head.RRule, series.RRule)
+	}
+}
+
+func TestSplitSeriesNotOccurrence(t *testing.T) {
+	series := newSeries("2026-03-02T19:00:00Z", "UTC", "FREQ=DAILY;COUNT=5")
+	tests := []time.Time{
+		date("2026-03-03T20:00:00Z"),
+		date("2026-03-10T19:00:00Z"),
+	}
+	for _, at := range tests {
+		if _, _, err := SplitSeries(series, at, date("2026-03-01T00:00:00Z")); !errors.Is(err, ErrNotOccurrence) {
+			t.Errorf("SplitSeries at %v: err = %v, want ErrNotOccurrence", at, err)
+		}
+	}
+}
+
+func assertTimes(t *testing.T, got, want []time.Time) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/smithy-go/middleware"
+	"github.com/jhonathanssegura/ticket-events/internal/metrics"
+	"github.com/jhonathanssegura/ticket-events/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentation returns a middleware that records metrics and a client
+// span for every S3 operation on bucket.
+func instrumentation(bucket string) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("TicketEventsInstrumentation",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				operation := middleware.GetOperationName(ctx)
+
+				ctx, span := tracing.Tracer().Start(ctx, "S3."+operation,
+					trace.WithSpanKind(trace.SpanKindClient),
+					trace.WithAttributes(
+						semconv.RPCSystemKey.String("aws-api"),
+						semconv.RPCService("S3"),
+						semconv.RPCMethod(operation),
+						semconv.AWSS3Bucket(bucket),
+					))
+
+				start := time.Now()
+				out, md, err := next.HandleInitialize(ctx, in)
+				metrics.ObserveS3(operation, time.Since(start), err)
+				tracing.End(span, err)
+				return out, md, err
+			}), middleware.Before)
+	}
+}
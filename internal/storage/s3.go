@@ -0,0 +1,93 @@
+// Package storage keeps uploaded files in an S3-compatible bucket.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+type S3Client struct {
+	Client *s3.Client
+	Bucket string
+	// BaseURL is the public URL the objects of Bucket are served from, so
+	// that a key is served at BaseURL/key.
+	BaseURL string
+}
+
+// NewS3Client builds an S3Client for bucket whose calls are instrumented.
+// Requests use path-style addressing, which LocalStack and most
+// S3-compatible stores expect.
+func NewS3Client(cfg aws.Config, bucket, baseURL string) *S3Client {
+	return &S3Client{
+		Client: s3.NewFromConfig(cfg, func(o *s3.Options) {
+			o.UsePathStyle = true
+			o.APIOptions = append(o.APIOptions, instrumentation(bucket))
+		}),
+		Bucket:  bucket,
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Put stores data under key and returns its public URL.
+func (s *S3Client) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	_, err := s.Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.Bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(data),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(int64(len(data))),
+		// Keys are never reused, so objects can be cached forever
+		CacheControl: aws.String("public, max-age=31536000, immutable"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error storing %s: %w", key, err)
+	}
+	return s.URL(key), nil
+}
+
+// Delete removes the objects under keys. Missing objects are not an error.
+func (s *S3Client) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	objects := make([]types.ObjectIdentifier, 0, len(keys))
+	for _, key := range keys {
+		objects = append(objects, types.ObjectIdentifier{Key: aws.String(key)})
+	}
+
+	out, err := s.Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+		Bucket: aws.String(s.Bucket),
+		Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting objects: %w", err)
+	}
+	if len(out.Errors) > 0 {
+		return fmt.Errorf("error deleting %s: %s", aws.ToString(out.Errors[0].Key), aws.ToString(out.Errors[0].Message))
+	}
+	return nil
+}
+
+// URL returns the public URL of key.
+func (s *S3Client) URL(key string) string {
+	return s.BaseURL + "/" + key
+}
+
+// Key returns the key of the object served at url, or false if url is not
+// served from the bucket.
+func (s *S3Client) Key(url string) (string, bool) {
+	key, ok := strings.CutPrefix(url, s.BaseURL+"/")
+	return key, ok && key != ""
+}
+
+// Ping checks that the bucket exists and is reachable.
+func (s *S3Client) Ping(ctx context.Context) error {
+	_, err := s.Client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(s.Bucket)})
+	return err
+}
@@ -0,0 +1,41 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// ErrMissing is returned by data access code when a request context carries
+// no tenant, so nothing can be read or written outside a tenant.
+var ErrMissing = errors.New("tenant not resolved for request")
+
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// Valid reports whether id is an acceptable tenant identifier: lowercase
+// letters, digits and hyphens, up to 63 characters.
+func Valid(id string) bool {
+	return validID.MatchString(id)
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx scoped to tenant id.
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant of ctx, or "" if none was resolved.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(tenantKey{}).(string)
+	return id
+}
+
+// Require returns the tenant of ctx or ErrMissing.
+func Require(ctx context.Context) (string, error) {
+	id := FromContext(ctx)
+	if id == "" {
+		return "", ErrMissing
+	}
+	return id, nil
+}
@@ -0,0 +1,263 @@
+// Package validation configures request validation and turns binding errors
+// into a list of field errors with machine-readable codes.
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/jhonathanssegura/ticket-events/internal/auth"
+	"github.com/jhonathanssegura/ticket-events/internal/i18n"
+	"github.com/teambition/rrule-go"
+)
+
+// Error codes returned in FieldError.Code. They are stable and meant for
+// clients; messages may change.
+const (
+	CodeRequired       = "required"
+	CodeBlank          = "blank"
+	CodeTooShort       = "too_short"
+	CodeTooLong        = "too_long"
+	CodeTooSmall       = "too_small"
+	CodeTooLarge       = "too_large"
+	CodeNotFuture      = "not_future"
+	CodeNotAfter       = "not_after"
+	CodeInvalidZone    = "invalid_time_zone"
+	CodeInvalidURL     = "invalid_url"
+	CodeInvalidScope   = "invalid_scope"
+	CodeInvalidRRule   = "invalid_rrule"
+	CodeInvalidCoord   = "invalid_coordinate"
+	CodeInvalidCountry = "invalid_country"
+	CodeInvalidCode    = "invalid_code"
+	CodeOverCapacity   = "over_capacity"
+	CodeDuplicate      = "duplicate"
+	CodeNotFound       = "not_found"
+	CodeInvalidType    = "invalid_type"
+	CodeInvalidFormat  = "invalid_format"
+	CodeInvalid        = "invalid"
+	CodeMalformedBody  = "malformed_body"
+)
+
+// FieldError describes why one field of a request is invalid. Field is the
+// JSON path of the field, or empty when the error concerns the whole body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// promoCodePattern is the shape of a promo code as typed by customers.
+var promoCodePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{3,32}$`)
+
+// Register installs the custom rules (notblank, future, scope, rrule,
+// promocode) on Gin's validator and makes errors report JSON field names.
+// Call it once at startup, before any request is bound.
+func Register() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected binding validator engine")
+	}
+	v.RegisterTagNameFunc(jsonName)
+
+	rules := map[string]validator.Func{
+		"notblank":  notBlank,
+		"future":    future,
+		"scope":     scope,
+		"rrule":     recurrence,
+		"promocode": promoCode,
+	}
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return fmt.Errorf("registering %s validation: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// Errors converts an error returned by Gin's binding into field errors, with
+// messages in the locale of ctx.
+func Errors(ctx context.Context, err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, fieldError(ctx, fe))
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []FieldError{{Field: typeErr.Field, Code: CodeInvalidType, Message: i18n.T(ctx, i18n.ValidationInvalidType)}}
+	}
+	var timeErr *time.ParseError
+	if errors.As(err, &timeErr) {
+		return []FieldError{{Code: CodeInvalidFormat, Message: i18n.T(ctx, i18n.ValidationInvalidFormat)}}
+	}
+	return []FieldError{{Code: CodeMalformedBody, Message: i18n.T(ctx, i18n.ValidationMalformedBody)}}
+}
+
+// Valid reports whether value satisfies tag, a rule as written in binding
+// tags. It checks values that do not come from a request body, such as path
+// parameters.
+func Valid(value any, tag string) bool {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	return ok && v.Var(value, tag) == nil
+}
+
+func fieldError(ctx context.Context, fe validator.FieldError) FieldError {
+	field := fe.Namespace()
+	// Drop the struct name prefix: "CreateEventRequest.name" -> "name".
+	if _, rest, ok := strings.Cut(field, "."); ok {
+		field = rest
+	}
+
+	var (
+		code string
+		key  i18n.Key
+		args []any
+	)
+	text, list := fe.Kind() == reflect.String, fe.Kind() == reflect.Slice
+	switch fe.Tag() {
+	case "required", "required_without", "required_with":
+		code, key = CodeRequired, i18n.ValidationRequired
+	case "notblank":
+		code, key = CodeBlank, i18n.ValidationBlank
+	case "min":
+		args = []any{fe.Param()}
+		switch {
+		case text:
+			code, key = CodeTooShort, i18n.ValidationTooShort
+		case list:
+			code, key = CodeTooShort, i18n.ValidationTooFewItems
+		default:
+			code, key = CodeTooSmall, i18n.ValidationTooSmall
+		}
+	case "max":
+		args = []any{fe.Param()}
+		switch {
+		case text:
+			code, key = CodeTooLong, i18n.ValidationTooLong
+		case list:
+			code, key = CodeTooLong, i18n.ValidationTooManyItems
+		default:
+			code, key = CodeTooLarge, i18n.ValidationTooLarge
+		}
+	case "future":
+		code, key = CodeNotFuture, i18n.ValidationNotFuture
+	case "gtfield":
+		code, key = CodeNotAfter, i18n.ValidationNotAfter
+		args = []any{snakeCase(fe.Param())}
+	case "timezone":
+		code, key = CodeInvalidZone, i18n.ValidationInvalidZone
+	case "http_url", "len=0|http_url":
+		code, key = CodeInvalidURL, i18n.ValidationInvalidURL
+	case "scope":
+		code, key = CodeInvalidScope, i18n.ValidationInvalidScope
+		args = []any{strings.Join(auth.Scopes, ", ")}
+	case "rrule":
+		code, key = CodeInvalidRRule, i18n.ValidationInvalidRRule
+	case "latitude":
+		code, key = CodeInvalidCoord, i18n.ValidationInvalidLatitude
+	case "longitude":
+		code, key = CodeInvalidCoord, i18n.ValidationInvalidLongitude
+	case "iso3166_1_alpha2":
+		code, key = CodeInvalidCountry, i18n.ValidationInvalidCountry
+	case "excludes":
+		code, key = CodeInvalid, i18n.ValidationExcludes
+		args = []any{fe.Param()}
+	case "unique":
+		code, key = CodeDuplicate, i18n.ValidationDuplicateItems
+	case "oneof":
+		code, key = CodeInvalid, i18n.ValidationOneOf
+		args = []any{strings.ReplaceAll(fe.Param(), " ", ", ")}
+	case "excluded_with":
+		code, key = CodeInvalid, i18n.ValidationExcludedWith
+		args = []any{snakeCase(fe.Param())}
+	case "promocode":
+		code, key = CodeInvalidCode, i18n.ValidationPromoCode
+	default:
+		code, key = CodeInvalid, i18n.ValidationInvalid
+	}
+	return NewFieldError(ctx, field, code, key, args...)
+}
+
+// NewFieldError builds a field error for rules checked outside the
+// validator, e.g. across fields of a merged update.
+func NewFieldError(ctx context.Context, field, code string, key i18n.Key, args ...any) FieldError {
+	return FieldError{Field: field, Code: code, Message: i18n.T(ctx, key, args...)}
+}
+
+// snakeCase turns a Go field name such as "StartsAt" or "EventID" into its
+// JSON name.
+func snakeCase(name string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range name {
+		if unicode.IsUpper(r) {
+			// Acronyms stay together: "EventID" -> "event_id"
+			if prev != 0 && !unicode.IsUpper(prev) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(r)
+		}
+		prev = r
+	}
+	return b.String()
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// notBlank rejects strings made only of whitespace.
+func notBlank(fl validator.FieldLevel) bool {
+	return strings.TrimSpace(fl.Field().String()) != ""
+}
+
+// future requires a time after now.
+func future(fl validator.FieldLevel) bool {
+	t, ok := fl.Field().Interface().(time.Time)
+	return ok && t.After(time.Now())
+}
+
+// scope requires a known API key scope.
+func scope(fl validator.FieldLevel) bool {
+	return auth.ValidScope(fl.Field().String())
+}
+
+// recurrence requires an RFC 5545 RRULE that repeats at most daily; finer
+// frequencies would flood the events table.
+func recurrence(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if strings.Contains(value, "\n") {
+		// DTSTART comes from starts_at, not from the rule.
+		return false
+	}
+	option, err := rrule.StrToROption(value)
+	return err == nil && option.Freq <= rrule.DAILY
+}
+
+// promoCode requires a code customers can type: letters, digits, hyphens
+// and underscores.
+func promoCode(fl validator.FieldLevel) bool {
+	return promoCodePattern.MatchString(fl.Field().String())
+}
@@ -0,0 +1,125 @@
+package validation
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestMain(m *testing.M) {
+	if err := Register(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestCustomRules(t *testing.T) {
+	tests := []struct {
+		tag   string
+		value any
+		want  bool
+	}{
+		{tag: "notblank", value: "Festival", want: true},
+		{tag: "notblank", value: " \t\n", want: false},
+		{tag: "notblank", value: "", want: false},
+		{tag: "future", value: time.Now().Add(time.Hour), want: true},
+		{tag: "future", value: time.Now().Add(-time.Hour), want: false},
+		{tag: "future", value: "tomorrow", want: false},
+		{tag: "scope", value: "events:read", want: true},
+		{tag: "scope", value: "promos:redeem", want: true},
+		{tag: "scope", value: "events:delete", want: false},
+		{tag: "scope", value: "", want: false},
+		{tag: "rrule", value: "FREQ=WEEKLY;COUNT=10", want: true},
+		{tag: "rrule", value: "FREQ=DAILY;UNTIL=20261231T000000Z", want: true},
+		{tag: "rrule", value: "FREQ=HOURLY;COUNT=10", want: false},
+		{tag: "rrule", value: "DTSTART:20260101T000000Z\nFREQ=DAILY", want: false},
+		{tag: "rrule", value: "FREQ=SOMETIMES", want: false},
+		{tag: "promocode", value: "SUMMER-2026", want: true},
+		{tag: "promocode", value: "early_bird", want: true},
+		{tag: "promocode", value: "AB", want: false},
+		{tag: "promocode", value: "NO SPACES", want: false},
+		{tag: "promocode", value: "DESCUENTO€", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := Valid(tt.value, tt.tag); got != tt.want {
+				t.Fatalf("Valid(%v, %q) = %v, want %v", tt.value, tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+type testRequest struct {
+	Name     string    `json:"name" binding:"required,notblank,max=10"`
+	Scopes   []string  `json:"scopes" binding:"required,min=1,unique,dive,scope"`
+	StartsAt time.Time `json:"starts_at" binding:"required,future"`
+	EndsAt   time.Time `json:"ends_at" binding:"required,gtfield=StartsAt"`
+	Code     string    `json:"code" binding:"omitempty,promocode"`
+}
+
+func TestErrors(t *testing.T) {
+	start := time.Now().Add(time.Hour)
+	valid := testRequest{
+		Name:     "Festival",
+		Scopes:   []string{"events:read"},
+		StartsAt: start,
+		EndsAt:   start.Add(time.Hour),
+	}
+
+	tests := []struct {
+		name      string
+		mutate    func(r *testRequest)
+		wantField string
+		wantCode  string
+	}{
+		{name: "blank", mutate: func(r *testRequest) { r.Name = "   " }, wantField: "name", wantCode: CodeBlank},
+		{name: "too long", mutate: func(r *testRequest) { r.Name = "Festival de Jazz" }, wantField: "name", wantCode: CodeTooLong},
+		{name: "required", mutate: func(r *testRequest) { r.Scopes = nil }, wantField: "scopes", wantCode: CodeRequired},
+		{name: "duplicate", mutate: func(r *testRequest) { r.Scopes = []string{"events:read", "events:read"} }, wantField: "scopes", wantCode: CodeDuplicate},
+		{name: "unknown scope", mutate: func(r *testRequest) { r.Scopes = []string{"events:read", "admin"} }, wantField: "scopes[1]", wantCode: CodeInvalidScope},
+		{name: "past", mutate: func(r *testRequest) { r.StartsAt = time.Now().Add(-time.Hour) }, wantField: "starts_at", wantCode: CodeNotFuture},
+		{name: "ends before start", mutate: func(r *testRequest) { r.EndsAt = r.StartsAt }, wantField: "ends_at", wantCode: CodeNotAfter},
+		{name: "promo code", mutate: func(r *testRequest) { r.Code = "no spaces" }, wantField: "code", wantCode: CodeInvalidCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.mutate(&req)
+			err := binding.Validator.ValidateStruct(&req)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			fields := Errors(context.Background(), err)
+			if len(fields) != 1 {
+				t.Fatalf("got %d field errors, want 1: %+v", len(fields), fields)
+			}
+			if fields[0].Field != tt.wantField || fields[0].Code != tt.wantCode {
+				t.Fatalf("got %s/%s, want %s/%s", fields[0].Field, fields[0].Code, tt.wantField, tt.wantCode)
+			}
+			if fields[0].Message == "" {
+				t.Fatal("empty message")
+			}
+		})
+	}
+
+	if err := binding.Validator.ValidateStruct(&valid); err != nil {
+		t.Fatalf("valid request rejected: %v", err)
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"StartsAt":   "starts_at",
+		"EventID":    "event_id",
+		"Name":       "name",
+		"ValidUntil": "valid_until",
+	}
+	for in, want := range tests {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -23,14 +23,44 @@ if [ -z "$table_exists" ]; then
   echo "📝 Creando tabla DynamoDB 'events'..."
   aws $AWS_ENDPOINT dynamodb create-table \
     --table-name events \
-    --attribute-definitions AttributeName=id,AttributeType=S \
+    --attribute-definitions \
+      AttributeName=id,AttributeType=S \
+      AttributeName=tenant_organizer,AttributeType=S \
+      AttributeName=tenant_id,AttributeType=S \
+      AttributeName=created_at,AttributeType=S \
+      AttributeName=series_id,AttributeType=S \
+      AttributeName=starts_at,AttributeType=S \
+      AttributeName=geo_cell,AttributeType=S \
+      AttributeName=geohash,AttributeType=S \
     --key-schema AttributeName=id,KeyType=HASH \
+    --global-secondary-indexes \
+      "IndexName=tenant_organizer-index,KeySchema=[{AttributeName=tenant_organizer,KeyType=HASH},{AttributeName=created_at,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+      "IndexName=tenant_id-index,KeySchema=[{AttributeName=tenant_id,KeyType=HASH},{AttributeName=created_at,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+      "IndexName=series_id-index,KeySchema=[{AttributeName=series_id,KeyType=HASH},{AttributeName=starts_at,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+      "IndexName=geo_cell-index,KeySchema=[{AttributeName=geo_cell,KeyType=HASH},{AttributeName=geohash,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
     --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
   echo "✅ Tabla DynamoDB 'events' creada exitosamente"
 else
   echo "✅ La tabla DynamoDB 'events' ya existe."
 fi
 
+# Añadir índices a tablas 'events' creadas con versiones anteriores
+ensure_events_index() {
+  local index_name=$1 hash_key=$2 range_key=$3
+  if ! aws $AWS_ENDPOINT dynamodb describe-table --table-name events 2>/dev/null | grep -q "\"$index_name\""; then
+    echo "📝 Creando índice '$index_name' en la tabla 'events'..."
+    aws $AWS_ENDPOINT dynamodb update-table \
+      --table-name events \
+      --attribute-definitions AttributeName=$hash_key,AttributeType=S AttributeName=$range_key,AttributeType=S \
+      --global-secondary-index-updates \
+        "[{\"Create\":{\"IndexName\":\"$index_name\",\"KeySchema\":[{\"AttributeName\":\"$hash_key\",\"KeyType\":\"HASH\"},{\"AttributeName\":\"$range_key\",\"KeyType\":\"RANGE\"}],\"Projection\":{\"ProjectionType\":\"ALL\"},\"ProvisionedThroughput\":{\"ReadCapacityUnits\":5,\"WriteCapacityUnits\":5}}}]"
+  fi
+}
+ensure_events_index tenant_organizer-index tenant_organizer created_at
+ensure_events_index tenant_id-index tenant_id created_at
+ensure_events_index series_id-index series_id starts_at
+ensure_events_index geo_cell-index geo_cell geohash
+
 # Crear tabla DynamoDB de categorías solo si no existe
 echo "🗄️ Configurando tabla DynamoDB de categorías..."
 table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep 'categories' || true)
@@ -47,6 +77,236 @@ else
 fi
 
 
+# Crear tabla DynamoDB de API keys solo si no existe
+echo "🗄️ Configurando tabla DynamoDB de API keys..."
+table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep '"api_keys"' || true)
+if [ -z "$table_exists" ]; then
+  echo "📝 Creando tabla DynamoDB 'api_keys'..."
+  aws $AWS_ENDPOINT dynamodb create-table \
+    --table-name api_keys \
+    --attribute-definitions \
+      AttributeName=id,AttributeType=S \
+      AttributeName=tenant_id,AttributeType=S \
+      AttributeName=created_at,AttributeType=S \
+    --key-schema AttributeName=id,KeyType=HASH \
+    --global-secondary-indexes \
+      "IndexName=tenant_id-index,KeySchema=[{AttributeName=tenant_id,KeyType=HASH},{AttributeName=created_at,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
+  echo "✅ Tabla DynamoDB 'api_keys' creada exitosamente"
+else
+  echo "✅ La tabla DynamoDB 'api_keys' ya existe."
+fi
+
+# Crear tabla de límites de peticiones (RATE_LIMIT_BACKEND=dynamodb)
+table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep '"rate_limits"' || true)
+if [ -z "$table_exists" ]; then
+  echo "📝 Creando tabla DynamoDB 'rate_limits'..."
+  aws $AWS_ENDPOINT dynamodb create-table \
+    --table-name rate_limits \
+    --attribute-definitions AttributeName=id,AttributeType=S \
+    --key-schema AttributeName=id,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
+  aws $AWS_ENDPOINT dynamodb update-time-to-live \
+    --table-name rate_limits \
+    --time-to-live-specification Enabled=true,AttributeName=expires_at
+  echo "✅ Tabla DynamoDB 'rate_limits' creada exitosamente"
+else
+  echo "✅ La tabla DynamoDB 'rate_limits' ya existe."
+fi
+
+# Crear tabla de turnos de las tareas en segundo plano (una réplica a la vez)
+table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep '"leases"' || true)
+if [ -z "$table_exists" ]; then
+  echo "📝 Creando tabla DynamoDB 'leases'..."
+  aws $AWS_ENDPOINT dynamodb create-table \
+    --table-name leases \
+    --attribute-definitions AttributeName=id,AttributeType=S \
+    --key-schema AttributeName=id,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
+  aws $AWS_ENDPOINT dynamodb update-time-to-live \
+    --table-name leases \
+    --time-to-live-specification Enabled=true,AttributeName=expires_at
+  echo "✅ Tabla DynamoDB 'leases' creada exitosamente"
+else
+  echo "✅ La tabla DynamoDB 'leases' ya existe."
+fi
+
+# Crear tabla de respuestas idempotentes (cabecera Idempotency-Key)
+table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep '"idempotency_keys"' || true)
+if [ -z "$table_exists" ]; then
+  echo "📝 Creando tabla DynamoDB 'idempotency_keys'..."
+  aws $AWS_ENDPOINT dynamodb create-table \
+    --table-name idempotency_keys \
+    --attribute-definitions AttributeName=id,AttributeType=S \
+    --key-schema AttributeName=id,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
+  aws $AWS_ENDPOINT dynamodb update-time-to-live \
+    --table-name idempotency_keys \
+    --time-to-live-specification Enabled=true,AttributeName=expires_at
+  echo "✅ Tabla DynamoDB 'idempotency_keys' creada exitosamente"
+else
+  echo "✅ La tabla DynamoDB 'idempotency_keys' ya existe."
+fi
+
+# Crear tabla DynamoDB de recintos solo si no existe
+table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep '"venues"' || true)
+if [ -z "$table_exists" ]; then
+  echo "📝 Creando tabla DynamoDB 'venues'..."
+  aws $AWS_ENDPOINT dynamodb create-table \
+    --table-name venues \
+    --attribute-definitions \
+      AttributeName=id,AttributeType=S \
+      AttributeName=tenant_id,AttributeType=S \
+      AttributeName=created_at,AttributeType=S \
+    --key-schema AttributeName=id,KeyType=HASH \
+    --global-secondary-indexes \
+      "IndexName=tenant_id-index,KeySchema=[{AttributeName=tenant_id,KeyType=HASH},{AttributeName=created_at,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
+  echo "✅ Tabla DynamoDB 'venues' creada exitosamente"
+else
+  echo "✅ La tabla DynamoDB 'venues' ya existe."
+fi
+
+# Crear tabla de series recurrentes solo si no existe
+table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep '"event_series"' || true)
+if [ -z "$table_exists" ]; then
+  echo "📝 Creando tabla DynamoDB 'event_series'..."
+  aws $AWS_ENDPOINT dynamodb create-table \
+    --table-name event_series \
+    --attribute-definitions AttributeName=id,AttributeType=S \
+    --key-schema AttributeName=id,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
+  echo "✅ Tabla DynamoDB 'event_series' creada exitosamente"
+else
+  echo "✅ La tabla DynamoDB 'event_series' ya existe."
+fi
+
+# Crear tablas de planos de asientos y de asientos reservados o vendidos
+table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep '"seat_maps"' || true)
+if [ -z "$table_exists" ]; then
+  echo "📝 Creando tabla DynamoDB 'seat_maps'..."
+  aws $AWS_ENDPOINT dynamodb create-table \
+    --table-name seat_maps \
+    --attribute-definitions AttributeName=id,AttributeType=S \
+    --key-schema AttributeName=id,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
+  echo "✅ Tabla DynamoDB 'seat_maps' creada exitosamente"
+else
+  echo "✅ La tabla DynamoDB 'seat_maps' ya existe."
+fi
+
+table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep '"event_seats"' || true)
+if [ -z "$table_exists" ]; then
+  echo "📝 Creando tabla DynamoDB 'event_seats'..."
+  aws $AWS_ENDPOINT dynamodb create-table \
+    --table-name event_seats \
+    --attribute-definitions \
+      AttributeName=event_id,AttributeType=S \
+      AttributeName=id,AttributeType=S \
+    --key-schema AttributeName=event_id,KeyType=HASH AttributeName=id,KeyType=RANGE \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
+  aws $AWS_ENDPOINT dynamodb update-time-to-live \
+    --table-name event_seats \
+    --time-to-live-specification Enabled=true,AttributeName=expires_at
+  echo "✅ Tabla DynamoDB 'event_seats' creada exitosamente"
+else
+  echo "✅ La tabla DynamoDB 'event_seats' ya existe."
+fi
+
+# Crear tablas de códigos promocionales y sus canjes
+table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep '"promo_codes"' || true)
+if [ -z "$table_exists" ]; then
+  echo "📝 Creando tabla DynamoDB 'promo_codes'..."
+  aws $AWS_ENDPOINT dynamodb create-table \
+    --table-name promo_codes \
+    --attribute-definitions \
+      AttributeName=id,AttributeType=S \
+      AttributeName=tenant_id,AttributeType=S \
+      AttributeName=created_at,AttributeType=S \
+    --key-schema AttributeName=id,KeyType=HASH \
+    --global-secondary-indexes \
+      "IndexName=tenant_id-index,KeySchema=[{AttributeName=tenant_id,KeyType=HASH},{AttributeName=created_at,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
+  echo "✅ Tabla DynamoDB 'promo_codes' creada exitosamente"
+else
+  echo "✅ La tabla DynamoDB 'promo_codes' ya existe."
+fi
+
+table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep '"promo_redemptions"' || true)
+if [ -z "$table_exists" ]; then
+  echo "📝 Creando tabla DynamoDB 'promo_redemptions'..."
+  aws $AWS_ENDPOINT dynamodb create-table \
+    --table-name promo_redemptions \
+    --attribute-definitions \
+      AttributeName=code_id,AttributeType=S \
+      AttributeName=id,AttributeType=S \
+    --key-schema AttributeName=code_id,KeyType=HASH AttributeName=id,KeyType=RANGE \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
+  echo "✅ Tabla DynamoDB 'promo_redemptions' creada exitosamente"
+else
+  echo "✅ La tabla DynamoDB 'promo_redemptions' ya existe."
+fi
+
+# Crear tablas de reglas de precio dinámico y su historial de cambios
+table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep '"pricing_rules"' || true)
+if [ -z "$table_exists" ]; then
+  echo "📝 Creando tabla DynamoDB 'pricing_rules'..."
+  aws $AWS_ENDPOINT dynamodb create-table \
+    --table-name pricing_rules \
+    --attribute-definitions AttributeName=id,AttributeType=S \
+    --key-schema AttributeName=id,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
+  echo "✅ Tabla DynamoDB 'pricing_rules' creada exitosamente"
+else
+  echo "✅ La tabla DynamoDB 'pricing_rules' ya existe."
+fi
+
+table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep '"price_history"' || true)
+if [ -z "$table_exists" ]; then
+  echo "📝 Creando tabla DynamoDB 'price_history'..."
+  aws $AWS_ENDPOINT dynamodb create-table \
+    --table-name price_history \
+    --attribute-definitions \
+      AttributeName=event_id,AttributeType=S \
+      AttributeName=id,AttributeType=S \
+    --key-schema AttributeName=event_id,KeyType=HASH AttributeName=id,KeyType=RANGE \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
+  echo "✅ Tabla DynamoDB 'price_history' creada exitosamente"
+else
+  echo "✅ La tabla DynamoDB 'price_history' ya existe."
+fi
+
+# Crear tablas de impuestos por país y cargos por servicio por categoría
+table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep '"tax_rates"' || true)
+if [ -z "$table_exists" ]; then
+  echo "📝 Creando tabla DynamoDB 'tax_rates'..."
+  aws $AWS_ENDPOINT dynamodb create-table \
+    --table-name tax_rates \
+    --attribute-definitions \
+      AttributeName=id,AttributeType=S \
+      AttributeName=tenant_id,AttributeType=S \
+      AttributeName=country,AttributeType=S \
+    --key-schema AttributeName=id,KeyType=HASH \
+    --global-secondary-indexes \
+      "IndexName=tenant_id-index,KeySchema=[{AttributeName=tenant_id,KeyType=HASH},{AttributeName=country,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
+  echo "✅ Tabla DynamoDB 'tax_rates' creada exitosamente"
+else
+  echo "✅ La tabla DynamoDB 'tax_rates' ya existe."
+fi
+
+table_exists=$(aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null | grep '"category_fees"' || true)
+if [ -z "$table_exists" ]; then
+  echo "📝 Creando tabla DynamoDB 'category_fees'..."
+  aws $AWS_ENDPOINT dynamodb create-table \
+    --table-name category_fees \
+    --attribute-definitions AttributeName=id,AttributeType=S \
+    --key-schema AttributeName=id,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5
+  echo "✅ Tabla DynamoDB 'category_fees' creada exitosamente"
+else
+  echo "✅ La tabla DynamoDB 'category_fees' ya existe."
+fi
 
 # Crear cola SQS solo si no existe
 echo "📬 Configurando cola SQS..."
@@ -59,6 +319,22 @@ else
   echo "✅ La cola SQS 'event-queue' ya existe."
 fi
 
+# Crear bucket S3 de imágenes de eventos solo si no existe; sus objetos se
+# sirven públicamente en IMAGE_BASE_URL
+echo "🖼️ Configurando bucket S3 de imágenes..."
+bucket_exists=$(aws $AWS_ENDPOINT s3api list-buckets 2>/dev/null | grep '"event-images"' || true)
+if [ -z "$bucket_exists" ]; then
+  echo "📝 Creando bucket S3 'event-images'..."
+  aws $AWS_ENDPOINT s3api create-bucket --bucket event-images
+  aws $AWS_ENDPOINT s3api put-bucket-policy --bucket event-images --policy '{
+    "Version": "2012-10-17",
+    "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::event-images/*"}]
+  }'
+  echo "✅ Bucket S3 'event-images' creado exitosamente"
+else
+  echo "✅ El bucket S3 'event-images' ya existe."
+fi
+
 # Verificar configuración
 echo "🔍 Verificando configuración..."
 echo "📊 Tablas DynamoDB:"
@@ -67,5 +343,8 @@ aws $AWS_ENDPOINT dynamodb list-tables 2>/dev/null || echo "❌ Error listando t
 echo "📊 Colas SQS:"
 aws $AWS_ENDPOINT sqs list-queues 2>/dev/null || echo "❌ Error listando colas SQS"
 
+echo "📊 Buckets S3:"
+aws $AWS_ENDPOINT s3api list-buckets 2>/dev/null || echo "❌ Error listando buckets S3"
+
 echo "🎉 Configuración completada!"
 echo "💡 Ahora puedes ejecutar: go run cmd/main.go" 
\ No newline at end of file
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/jhonathanssegura/ticket-events/internal/awsconfig"
+	"github.com/jhonathanssegura/ticket-events/internal/db"
+	"github.com/jhonathanssegura/ticket-events/internal/tenant"
+)
+
+// Asigna a DEFAULT_TENANT los eventos y categorías creados antes de que
+// existieran los tenants. Puede ejecutarse varias veces.
+func main() {
+	tenantID := os.Getenv("DEFAULT_TENANT")
+	if tenantID == "" {
+		log.Fatal("DEFAULT_TENANT es obligatorio")
+	}
+	if !tenant.Valid(tenantID) {
+		log.Fatalf("DEFAULT_TENANT no válido: %q", tenantID)
+	}
+
+	cfg, err := awsconfig.LoadAWSConfig()
+	if err != nil {
+		log.Fatalf("Error cargando configuración AWS: %v", err)
+	}
+	dynamoClient := db.NewDynamoClient(cfg)
+
+	ctx := tenant.WithTenant(context.Background(), tenantID)
+	events, categories, err := dynamoClient.BackfillTenant(ctx)
+	log.Printf("Asignados al tenant %q: %d eventos, %d categorías", tenantID, events, categories)
+	if err != nil {
+		log.Fatalf("Error asignando tenant: %v", err)
+	}
+}
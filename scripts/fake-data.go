@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -21,6 +22,12 @@ func main() {
 		log.Fatalf("Error cargando configuración AWS: %v", err)
 	}
 
+	// Tenant al que pertenecen los datos de prueba
+	tenantID := os.Getenv("DEFAULT_TENANT")
+	if tenantID == "" {
+		tenantID = "default"
+	}
+
 	// Crear cliente DynamoDB
 	dynamoClient := dynamodb.NewFromConfig(cfg)
 
@@ -80,7 +87,9 @@ func main() {
 			Description: "Un increíble concierto de rock al aire libre con las mejores bandas del momento",
 			CategoryID:  categoryIDs["cat-musica"],
 			Location:    "Parque Central",
-			Date:        time.Now().AddDate(0, 1, 15), // 1 mes y 15 días
+			StartsAt:    time.Now().AddDate(0, 1, 15), // 1 mes y 15 días
+			EndsAt:      time.Now().AddDate(0, 1, 15).Add(3 * time.Hour),
+			TimeZone:    "America/Bogota",
 			Capacity:    5000,
 			Price:       75.00,
 			Status:      model.EventStatusPublished,
@@ -94,7 +103,9 @@ func main() {
 			Description: "La famosa obra de Shakespeare presentada por la compañía nacional de teatro",
 			CategoryID:  categoryIDs["cat-teatro"],
 			Location:    "Teatro Nacional",
-			Date:        time.Now().AddDate(0, 0, 10), // 10 días
+			StartsAt:    time.Now().AddDate(0, 0, 10), // 10 días
+			EndsAt:      time.Now().AddDate(0, 0, 10).Add(3 * time.Hour),
+			TimeZone:    "America/Bogota",
 			Capacity:    800,
 			Price:       45.00,
 			Status:      model.EventStatusPublished,
@@ -108,7 +119,9 @@ func main() {
 			Description: "La gran final de la liga local entre los dos mejores equipos",
 			CategoryID:  categoryIDs["cat-deportes"],
 			Location:    "Estadio Municipal",
-			Date:        time.Now().AddDate(0, 0, 5), // 5 días
+			StartsAt:    time.Now().AddDate(0, 0, 5), // 5 días
+			EndsAt:      time.Now().AddDate(0, 0, 5).Add(3 * time.Hour),
+			TimeZone:    "America/Bogota",
 			Capacity:    25000,
 			Price:       30.00,
 			Status:      model.EventStatusPublished,
@@ -122,7 +135,9 @@ func main() {
 			Description: "El estreno mundial de la nueva película de acción y aventura",
 			CategoryID:  categoryIDs["cat-cine"],
 			Location:    "Cine Multiplex",
-			Date:        time.Now().AddDate(0, 0, 3), // 3 días
+			StartsAt:    time.Now().AddDate(0, 0, 3), // 3 días
+			EndsAt:      time.Now().AddDate(0, 0, 3).Add(3 * time.Hour),
+			TimeZone:    "America/Bogota",
 			Capacity:    300,
 			Price:       12.00,
 			Status:      model.EventStatusPublished,
@@ -136,7 +151,9 @@ func main() {
 			Description: "La conferencia más importante del año sobre las últimas tendencias en tecnología",
 			CategoryID:  categoryIDs["cat-tecnologia"],
 			Location:    "Centro de Convenciones",
-			Date:        time.Now().AddDate(0, 2, 0), // 2 meses
+			StartsAt:    time.Now().AddDate(0, 2, 0), // 2 meses
+			EndsAt:      time.Now().AddDate(0, 2, 0).Add(3 * time.Hour),
+			TimeZone:    "America/Bogota",
 			Capacity:    1000,
 			Price:       150.00,
 			Status:      model.EventStatusPublished,
@@ -153,6 +170,7 @@ func main() {
 	for i, category := range categories {
 		item := map[string]types.AttributeValue{
 			"id":          &types.AttributeValueMemberS{Value: category.ID.String()},
+			"tenant_id":   &types.AttributeValueMemberS{Value: tenantID},
 			"name":        &types.AttributeValueMemberS{Value: category.Name},
 			"description": &types.AttributeValueMemberS{Value: category.Description},
 			"created_at":  &types.AttributeValueMemberS{Value: category.CreatedAt.Format(time.RFC3339)},
@@ -176,11 +194,14 @@ func main() {
 	for i, event := range events {
 		item := map[string]types.AttributeValue{
 			"id":          &types.AttributeValueMemberS{Value: event.ID.String()},
+			"tenant_id":   &types.AttributeValueMemberS{Value: tenantID},
 			"name":        &types.AttributeValueMemberS{Value: event.Name},
 			"description": &types.AttributeValueMemberS{Value: event.Description},
 			"category_id": &types.AttributeValueMemberS{Value: event.CategoryID.String()},
 			"location":    &types.AttributeValueMemberS{Value: event.Location},
-			"date":        &types.AttributeValueMemberS{Value: event.Date.Format(time.RFC3339)},
+			"starts_at":   &types.AttributeValueMemberS{Value: event.StartsAt.UTC().Format(time.RFC3339)},
+			"ends_at":     &types.AttributeValueMemberS{Value: event.EndsAt.UTC().Format(time.RFC3339)},
+			"time_zone":   &types.AttributeValueMemberS{Value: event.TimeZone},
 			"capacity":    &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", event.Capacity)},
 			"price":       &types.AttributeValueMemberN{Value: fmt.Sprintf("%.2f", event.Price)},
 			"status":      &types.AttributeValueMemberS{Value: event.Status},
@@ -225,5 +246,5 @@ func main() {
 	fmt.Println("\n5. Crear un nuevo evento:")
 	fmt.Println("   curl -X POST http://localhost:8080/api/events \\")
 	fmt.Println("     -H 'Content-Type: application/json' \\")
-	fmt.Println("     -d '{\"name\":\"Nuevo Evento\",\"description\":\"Descripción del evento\",\"category_id\":\"550e8400-e29b-41d4-a716-446655440001\",\"location\":\"Ubicación\",\"date\":\"2024-08-15T19:00:00Z\",\"capacity\":100,\"price\":25.00}'")
+	fmt.Println("     -d '{\"name\":\"Nuevo Evento\",\"description\":\"Descripción del evento\",\"category_id\":\"550e8400-e29b-41d4-a716-446655440001\",\"location\":\"Ubicación\",\"starts_at\":\"2030-08-15T19:00:00-05:00\",\"ends_at\":\"2030-08-15T22:00:00-05:00\",\"time_zone\":\"America/Bogota\",\"capacity\":100,\"price\":25.00}'")
 } 
\ No newline at end of file